@@ -0,0 +1,60 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+)
+
+func NewValidateCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Args:  cobra.NoArgs,
+		Short: "Validate price models in the config file",
+		Long: `Validate price models in the config file without connecting to origins.
+
+Errors and warnings are printed along with their location in the config file.
+The command exits with a non-zero exit code if any errors are found.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			var diags hcl.Diagnostics
+			if err := config.LoadFiles(&opts.Config, opts.ConfigFilePath); err != nil {
+				if !errors.As(err, &diags) {
+					return err
+				}
+			} else {
+				diags = opts.Config.Gofer.Validate()
+			}
+			if len(diags) == 0 {
+				fmt.Println("Configuration is valid")
+				return nil
+			}
+			if err := config.WriteDiagnostics(os.Stderr, opts.ConfigFilePath, diags); err != nil {
+				return err
+			}
+			if diags.HasErrors() {
+				return fmt.Errorf("configuration is invalid: %d error(s) found", len(diags.Errs()))
+			}
+			return nil
+		},
+	}
+}
@@ -37,6 +37,7 @@ func main() {
 		NewPairsCmd(&opts),
 		NewPricesCmd(&opts),
 		NewAgentCmd(&opts),
+		NewValidateCmd(&opts),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -34,6 +34,12 @@ with aggregates that increase reliability in the DeFi environment.`,
 		"f",
 		"output format",
 	)
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.NoAgent,
+		"no-agent",
+		false,
+		"disable the use of the agent",
+	)
 
 	return rootCmd
 }
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+)
+
+func NewAgentCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "agent",
+		Args:  cobra.NoArgs,
+		Short: "Start an HTTP API server",
+		Long:  `Start an HTTP API server that provides prices from price models.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := config.LoadFiles(&opts.Config, opts.ConfigFilePath); err != nil {
+				return err
+			}
+			ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer ctxCancel()
+			services, err := opts.Config.AgentServices(opts.Logger())
+			if err != nil {
+				return err
+			}
+			if err = services.Start(ctx); err != nil {
+				return err
+			}
+			return <-services.Wait()
+		},
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	legacyGofer "github.com/chronicleprotocol/oracle-suite/pkg/config/gofer"
+	gofer "github.com/chronicleprotocol/oracle-suite/pkg/config/gofernext"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/compare"
+)
+
+func NewCompareCmd(opts *options) *cobra.Command {
+	var (
+		legacyConfigPath []string
+		threshold        float64
+		interval         time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Args:  cobra.NoArgs,
+		Short: "Compare prices with the legacy gofer price provider",
+		Long: `Compare prices calculated using the gofernext config with prices calculated
+using the legacy gofer config.
+
+Prices for all pairs supported by both configs are obtained at the same time.
+The command reports relative deviations between prices, pairs supported by
+only one of the configs, and origins used by only one of the configs.
+Legacy prices are verified using the price hooks from the legacy config,
+in the same way as the legacy gofer does.
+
+The command exits with a non-zero exit code if the deviation for any pair
+exceeds the threshold, or if only one of the configs is able to calculate
+the price.
+
+With the --interval flag, prices are compared continuously and divergences
+are logged until the command is interrupted.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			var legacyConfig legacyGofer.Config
+			if err := config.LoadFiles(&opts.Config, opts.ConfigFilePath); err != nil {
+				return err
+			}
+			if err := config.LoadFiles(&legacyConfig, legacyConfigPath); err != nil {
+				return err
+			}
+			ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer ctxCancel()
+			services, err := opts.Config.Services(opts.Logger(), opts.NoAgent)
+			if err != nil {
+				return err
+			}
+			if err = services.Start(ctx); err != nil {
+				return err
+			}
+			legacyServices, err := legacyConfig.ClientServices(ctx, opts.Logger(), opts.NoAgent, marshal.Plain)
+			if err != nil {
+				return err
+			}
+			if err = legacyServices.Start(ctx); err != nil {
+				return err
+			}
+			if interval > 0 {
+				return compareContinuously(ctx, services.Logger, legacyServices, services, threshold, interval)
+			}
+			report, err := compare.Compare(ctx, legacyServices.PriceProvider, legacyServices.PriceHook, services.PriceProvider)
+			if err != nil {
+				return err
+			}
+			marshaled, err := marshalComparison(report, threshold, opts.Format.format)
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(marshaled))
+			if n := len(report.Divergent(threshold)); n > 0 {
+				return fmt.Errorf("prices for %d pair(s) diverge more than the threshold", n)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(
+		&legacyConfigPath,
+		"legacy-config",
+		nil,
+		"legacy gofer config file",
+	)
+	cmd.Flags().Float64Var(
+		&threshold,
+		"threshold",
+		0.01,
+		"maximum allowed relative deviation between prices",
+	)
+	cmd.Flags().DurationVar(
+		&interval,
+		"interval",
+		0,
+		"compare prices continuously with the given interval",
+	)
+	_ = cmd.MarkFlagRequired("legacy-config")
+	return cmd
+}
+
+func compareContinuously(
+	ctx context.Context,
+	logger log.Logger,
+	legacyServices *legacyGofer.ClientServices,
+	services *gofer.Services,
+	threshold float64,
+	interval time.Duration,
+) error {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := compare.Compare(ctx, legacyServices.PriceProvider, legacyServices.PriceHook, services.PriceProvider)
+		if err != nil {
+			logger.WithError(err).Error("Unable to compare prices")
+		} else {
+			logComparison(logger, report, threshold)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func logComparison(logger log.Logger, report *compare.Report, threshold float64) {
+	divergent := report.Divergent(threshold)
+	for _, r := range divergent {
+		logger.
+			WithFields(log.Fields{
+				"pair":        r.Pair,
+				"model":       r.Model,
+				"legacyPrice": r.LegacyPrice,
+				"nextPrice":   r.NextPrice,
+				"deviation":   r.Deviation,
+				"legacyError": r.LegacyError,
+				"nextError":   r.NextError,
+			}).
+			Warn("Prices diverge")
+	}
+	logger.
+		WithFields(log.Fields{
+			"pairs":      len(report.Results),
+			"divergent":  len(divergent),
+			"legacyOnly": report.LegacyOnly,
+			"nextOnly":   report.NextOnly,
+		}).
+		Info("Prices compared")
+}
+
+func marshalComparison(report *compare.Report, threshold float64, format string) ([]byte, error) {
+	switch format {
+	case formatPlain, "":
+		return marshalComparisonPlain(report, threshold), nil
+	case formatJSON:
+		return json.Marshal(report)
+	default:
+		return nil, fmt.Errorf("unsupported format")
+	}
+}
+
+func marshalComparisonPlain(report *compare.Report, threshold float64) []byte {
+	var buf bytes.Buffer
+	for _, r := range report.Results {
+		buf.WriteString(fmt.Sprintf(
+			"%s (model %s): legacy %g, next %g, deviation %.4f%%",
+			r.Pair, r.Model, r.LegacyPrice, r.NextPrice, r.Deviation*100,
+		))
+		if r.Diverges(threshold) {
+			buf.WriteString(" [DIVERGENT]")
+		}
+		buf.WriteString("\n")
+		if r.LegacyError != "" {
+			buf.WriteString(fmt.Sprintf("  legacy error: %s\n", r.LegacyError))
+		}
+		if r.NextError != "" {
+			buf.WriteString(fmt.Sprintf("  next error: %s\n", r.NextError))
+		}
+		if len(r.LegacySources) > 0 {
+			buf.WriteString(fmt.Sprintf("  origins used only by legacy: %s\n", strings.Join(r.LegacySources, ", ")))
+		}
+		if len(r.NextSources) > 0 {
+			buf.WriteString(fmt.Sprintf("  origins used only by next: %s\n", strings.Join(r.NextSources, ", ")))
+		}
+	}
+	if len(report.LegacyOnly) > 0 {
+		buf.WriteString(fmt.Sprintf("Pairs only in legacy: %s\n", strings.Join(report.LegacyOnly, ", ")))
+	}
+	if len(report.NextOnly) > 0 {
+		buf.WriteString(fmt.Sprintf("Pairs only in next: %s\n", strings.Join(report.NextOnly, ", ")))
+	}
+	return buf.Bytes()
+}
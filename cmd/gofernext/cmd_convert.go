@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	legacyGofer "github.com/chronicleprotocol/oracle-suite/pkg/config/gofer"
+)
+
+func NewConvertCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "convert LEGACY_CONFIG...",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Convert a legacy gofer config to the gofernext format",
+		Long: `Convert origins and price models from a legacy gofer config to the gofernext format.
+
+Legacy origins are converted to generic_jq, generic_evm or DEX origins where
+possible. Everything that could not be converted automatically, or that behaves
+differently after the conversion, is listed in a report printed to stderr.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			var cfg legacyGofer.Config
+			if err := config.LoadFiles(&cfg, args); err != nil {
+				return err
+			}
+			conv := cfg.Gofer.Convert()
+			if output == "" {
+				fmt.Print(string(conv.Config))
+			} else if err := os.WriteFile(output, conv.Config, 0644); err != nil { //nolint:gosec
+				return err
+			}
+			if len(conv.Report) > 0 {
+				fmt.Fprintln(os.Stderr, "The following items require attention:")
+				for _, r := range conv.Report {
+					fmt.Fprintf(os.Stderr, "  - %s\n", r)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		"",
+		"write the converted config to the given file instead of stdout",
+	)
+	return cmd
+}
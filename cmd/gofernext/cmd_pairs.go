@@ -13,6 +13,7 @@ import (
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/config"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/graphrender"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
 )
 
@@ -29,7 +30,7 @@ func NewPairsCmd(opts *options) *cobra.Command {
 			}
 			ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
 			defer ctxCancel()
-			services, err := opts.Config.Services(opts.Logger())
+			services, err := opts.Config.Services(opts.Logger(), opts.NoAgent)
 			if err != nil {
 				return err
 			}
@@ -58,6 +59,10 @@ func marshalModels(models map[string]provider.Model, format string) ([]byte, err
 		return marshalModelsTrace(models)
 	case formatJSON:
 		return marshalModelsJSON(models)
+	case formatDOT:
+		return provider.MarshalModelsGraph(models, graphrender.DOT)
+	case formatMermaid:
+		return provider.MarshalModelsGraph(models, graphrender.Mermaid)
 	default:
 		return nil, fmt.Errorf("unsupported format")
 	}
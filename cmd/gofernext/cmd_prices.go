@@ -13,23 +13,34 @@ import (
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/config"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/replay"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/graphrender"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
 )
 
 func NewPricesCmd(opts *options) *cobra.Command {
-	return &cobra.Command{
+	var recordDir, replayDir string
+	cmd := &cobra.Command{
 		Use:     "prices [PAIR...]",
 		Aliases: []string{"price"},
 		Args:    cobra.MinimumNArgs(0),
 		Short:   "Return prices for given PAIRs",
-		Long:    `Return prices for given PAIRs.`,
+		Long: `Return prices for given PAIRs.
+
+With the --record flag, responses of origins are saved to the given directory.
+With the --replay flag, prices are calculated from responses saved in the given
+directory, without network access. Prices are calculated as of the time at
+which the responses were recorded, so replayed prices match the recorded ones.`,
 		RunE: func(c *cobra.Command, args []string) (err error) {
 			if err := config.LoadFiles(&opts.Config, opts.ConfigFilePath); err != nil {
 				return err
 			}
+			if opts.Config.Fixture, err = fixture(recordDir, replayDir); err != nil {
+				return err
+			}
 			ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
 			defer ctxCancel()
-			services, err := opts.Config.Services(opts.Logger())
+			services, err := opts.Config.Services(opts.Logger(), opts.NoAgent)
 			if err != nil {
 				return err
 			}
@@ -48,6 +59,33 @@ func NewPricesCmd(opts *options) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(
+		&recordDir,
+		"record",
+		"",
+		"record origin responses to the given directory",
+	)
+	cmd.Flags().StringVar(
+		&replayDir,
+		"replay",
+		"",
+		"replay origin responses from the given directory",
+	)
+	cmd.MarkFlagsMutuallyExclusive("record", "replay")
+	return cmd
+}
+
+// fixture returns a fixture for the --record or --replay flag, or nil if
+// neither is used.
+func fixture(recordDir, replayDir string) (*replay.Fixture, error) {
+	switch {
+	case recordDir != "":
+		return replay.NewFixture(recordDir, replay.Record)
+	case replayDir != "":
+		return replay.NewFixture(replayDir, replay.Replay)
+	default:
+		return nil, nil
+	}
 }
 
 func marshalTicks(ticks map[string]provider.Tick, format string) ([]byte, error) {
@@ -58,6 +96,10 @@ func marshalTicks(ticks map[string]provider.Tick, format string) ([]byte, error)
 		return marshalTicksTrace(ticks)
 	case formatJSON:
 		return marshalTicksJSON(ticks)
+	case formatDOT:
+		return provider.MarshalTicksGraph(ticks, graphrender.DOT)
+	case formatMermaid:
+		return provider.MarshalTicksGraph(ticks, graphrender.Mermaid)
 	default:
 		return nil, fmt.Errorf("unsupported format")
 	}
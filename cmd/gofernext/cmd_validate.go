@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+)
+
+func NewValidateCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Args:  cobra.NoArgs,
+		Short: "Validate price models in the config file",
+		Long: `Validate price models in the config file without connecting to origins.
+
+Errors and warnings are printed along with their location in the config file.
+The command exits with a non-zero exit code if any errors are found.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			var diags hcl.Diagnostics
+			if err := config.LoadFiles(&opts.Config, opts.ConfigFilePath); err != nil {
+				if !errors.As(err, &diags) {
+					return err
+				}
+			} else {
+				diags = opts.Config.Gofer.Validate()
+			}
+			if len(diags) == 0 {
+				fmt.Println("Configuration is valid")
+				return nil
+			}
+			if err := config.WriteDiagnostics(os.Stderr, opts.ConfigFilePath, diags); err != nil {
+				return err
+			}
+			if diags.HasErrors() {
+				return fmt.Errorf("configuration is invalid: %d error(s) found", len(diags.Errs()))
+			}
+			return nil
+		},
+	}
+}
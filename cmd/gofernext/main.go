@@ -19,6 +19,10 @@ func main() {
 	rootCmd.AddCommand(
 		NewPairsCmd(&opts),
 		NewPricesCmd(&opts),
+		NewAgentCmd(&opts),
+		NewValidateCmd(&opts),
+		NewConvertCmd(),
+		NewCompareCmd(&opts),
 	)
 
 	if err := rootCmd.Execute(); err != nil {
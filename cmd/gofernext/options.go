@@ -9,9 +9,11 @@ import (
 )
 
 const (
-	formatPlain = "plain"
-	formatTrace = "trace"
-	formatJSON  = "json"
+	formatPlain   = "plain"
+	formatTrace   = "trace"
+	formatJSON    = "json"
+	formatDOT     = "dot"
+	formatMermaid = "mermaid"
 )
 
 // These are the command options that can be set by CLI flags.
@@ -19,6 +21,7 @@ type options struct {
 	flag.LoggerFlag
 	ConfigFilePath []string
 	Format         formatTypeValue
+	NoAgent        bool
 	Config         gofer.Config
 	Version        string
 }
@@ -39,6 +42,10 @@ func (v *formatTypeValue) Set(s string) error {
 		v.format = formatTrace
 	case formatJSON:
 		v.format = formatJSON
+	case formatDOT:
+		v.format = formatDOT
+	case formatMermaid:
+		v.format = formatMermaid
 	default:
 		return fmt.Errorf("unsupported format")
 	}
@@ -46,5 +53,5 @@ func (v *formatTypeValue) Set(s string) error {
 }
 
 func (v *formatTypeValue) Type() string {
-	return "plain|trace|json"
+	return "plain|trace|json|dot|mermaid"
 }
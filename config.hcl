@@ -82,10 +82,11 @@ transport {
       "/dns/spire-bootstrap1.makerops.services/tcp/8000/p2p/12D3KooWRfYU5FaY9SmJcRD5Ku7c1XMBRqV6oM4nsnGQ1QRakSJi",
       "/dns/spire-bootstrap2.makerops.services/tcp/8000/p2p/12D3KooWBGqjW4LuHUoYZUhbWW1PnDVRUvUEpc4qgWE3Yg9z1MoR"
     ])
-    direct_peers_addrs = try(env.CFG_LIBP2P_DIRECT_PEERS_ADDRS == "" ? [] : split(",", env.CFG_LIBP2P_DIRECT_PEERS_ADDRS), [])
-    blocked_addrs      = try(env.CFG_LIBP2P_BLOCKED_ADDRS == "" ? [] : split(",", env.CFG_LIBP2P_BLOCKED_ADDRS), [])
-    disable_discovery  = tobool(try(env.CFG_LIBP2P_DISABLE_DISCOVERY, false))
-    ethereum_key       = try(env.CFG_ETH_FROM, "") == "" ? "" : "default"
+    direct_peers_addrs  = try(env.CFG_LIBP2P_DIRECT_PEERS_ADDRS == "" ? [] : split(",", env.CFG_LIBP2P_DIRECT_PEERS_ADDRS), [])
+    blocked_addrs       = try(env.CFG_LIBP2P_BLOCKED_ADDRS == "" ? [] : split(",", env.CFG_LIBP2P_BLOCKED_ADDRS), [])
+    disable_discovery   = tobool(try(env.CFG_LIBP2P_DISABLE_DISCOVERY, false))
+    price_batch_scoring = tobool(try(env.CFG_LIBP2P_PRICE_BATCH_SCORING, false))
+    ethereum_key        = try(env.CFG_ETH_FROM, "") == "" ? "" : "default"
   }
 
   # WebAPI transport configuration. Enabled if CFG_WEBAPI_LISTEN_ADDR is set to a listen address.
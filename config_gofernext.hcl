@@ -1,4 +1,6 @@
 gofernext {
+  agent_listen_addr = "127.0.0.1:9200"
+
   origin "coinbase" {
     origin = "generic_jq"
     url    = "https://api.pro.coinbase.com/products/$${ucbase}-$${ucquote}/ticker"
@@ -2,11 +2,13 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
 	"github.com/zclconf/go-cty/cty/function/stdlib"
@@ -66,6 +68,19 @@ func LoadFiles(config any, paths []string) error {
 	return nil
 }
 
+// WriteDiagnostics writes the diagnostics to the writer in a human-readable
+// form. Source snippets are included for diagnostics that refer to one of
+// the given paths.
+func WriteDiagnostics(w io.Writer, paths []string, diags hcl.Diagnostics) error {
+	parser := hclparse.NewParser()
+	for _, path := range paths {
+		// Errors are ignored, because the files are used only to show
+		// source snippets.
+		_, _ = parser.ParseHCLFile(path)
+	}
+	return hcl.NewDiagnosticTextWriter(w, parser.Files(), 0, false).WriteDiagnostics(diags)
+}
+
 // getEnvVars retrieves environment variables from the system and returns
 // them as a cty object type, where keys are variable names and values are
 // their corresponding values.
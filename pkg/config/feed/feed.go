@@ -26,6 +26,7 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/feeder"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	nextProvider "github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/timeutil"
 )
@@ -41,6 +42,20 @@ type Config struct {
 	// Pairs must be in the format "BASE/QUOTE".
 	Pairs []provider.Pair `hcl:"pairs"`
 
+	// PriceProvider is the name of the price provider to use, either
+	// "gofer" (default) or "gofernext". If "gofernext" is used, prices are
+	// obtained from price models named after pairs.
+	PriceProvider string `hcl:"price_provider,optional"`
+
+	// PriceBatch enables broadcasting of prices for all pairs in a single
+	// price/v2 message, in addition to price/v0 and price/v1 messages.
+	PriceBatch bool `hcl:"price_batch,optional"`
+
+	// PriceBatchOnly disables broadcasting of price/v0 and price/v1 messages,
+	// so prices are broadcast only in the price/v2 message. It should be
+	// enabled only when all consumers support the price/v2 message.
+	PriceBatchOnly bool `hcl:"price_batch_only,optional"`
+
 	// HCL fields:
 	Range   hcl.Range       `hcl:",range"`
 	Content hcl.BodyContent `hcl:",content"`
@@ -49,13 +64,25 @@ type Config struct {
 	feeder *feeder.Feeder
 }
 
+const (
+	PriceProviderGofer     = "gofer"
+	PriceProviderGoferNext = "gofernext"
+)
+
 type Dependencies struct {
 	KeysRegistry  ethereumConfig.KeyRegistry
 	PriceProvider provider.Provider
+	TickProvider  nextProvider.Provider
 	Transport     transport.Transport
 	Logger        log.Logger
 }
 
+// UsesGoferNext returns true if the feed is configured to use the next-gen
+// price provider.
+func (c *Config) UsesGoferNext() bool {
+	return c.PriceProvider == PriceProviderGoferNext
+}
+
 func (c *Config) Feed(d Dependencies) (*feeder.Feeder, error) {
 	if c.feeder != nil {
 		return c.feeder, nil
@@ -68,6 +95,16 @@ func (c *Config) Feed(d Dependencies) (*feeder.Feeder, error) {
 			Subject:  c.Content.Attributes["interval"].Range.Ptr(),
 		}}
 	}
+	switch c.PriceProvider {
+	case "", PriceProviderGofer, PriceProviderGoferNext:
+	default:
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   fmt.Sprintf("Unknown price provider: %s", c.PriceProvider),
+			Subject:  c.Content.Attributes["price_provider"].Range.Ptr(),
+		}
+	}
 	ethereumKey, ok := d.KeysRegistry[c.EthereumKey]
 	if !ok {
 		return nil, &hcl.Diagnostic{
@@ -82,12 +119,18 @@ func (c *Config) Feed(d Dependencies) (*feeder.Feeder, error) {
 		pairs[i] = p.String()
 	}
 	cfg := feeder.Config{
-		PriceProvider: d.PriceProvider,
-		Signer:        ethereumKey,
-		Transport:     d.Transport,
-		Logger:        d.Logger,
-		Interval:      timeutil.NewTicker(time.Second * time.Duration(c.Interval)),
-		Pairs:         pairs,
+		Signer:         ethereumKey,
+		Transport:      d.Transport,
+		Logger:         d.Logger,
+		Interval:       timeutil.NewTicker(time.Second * time.Duration(c.Interval)),
+		Pairs:          pairs,
+		PriceBatch:     c.PriceBatch,
+		PriceBatchOnly: c.PriceBatchOnly,
+	}
+	if c.UsesGoferNext() {
+		cfg.TickProvider = d.TickProvider
+	} else {
+		cfg.PriceProvider = d.PriceProvider
 	}
 	feed, err := feeder.New(cfg)
 	if err != nil {
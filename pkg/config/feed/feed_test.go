@@ -12,6 +12,7 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	providerMocks "github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
 )
 
@@ -27,6 +28,8 @@ func TestConfig(t *testing.T) {
 			test: func(t *testing.T, cfg *Config) {
 				assert.Equal(t, "key", cfg.EthereumKey)
 				assert.Equal(t, uint32(60), cfg.Interval)
+				assert.False(t, cfg.PriceBatch)
+				assert.False(t, cfg.PriceBatchOnly)
 				expectedPairs := []provider.Pair{
 					{Base: "ETH", Quote: "USD"},
 					{Base: "BTC", Quote: "USD"},
@@ -54,6 +57,28 @@ func TestConfig(t *testing.T) {
 				assert.NotNil(t, feed)
 			},
 		},
+		{
+			name: "gofernext",
+			path: "config_gofernext.hcl",
+			test: func(t *testing.T, cfg *Config) {
+				assert.True(t, cfg.UsesGoferNext())
+				assert.True(t, cfg.PriceBatch)
+				assert.True(t, cfg.PriceBatchOnly)
+				transport := local.New([]byte("test"), 1, nil)
+				logger := null.New()
+				keyRegistry := ethereum.KeyRegistry{
+					"key": &ethereumMocks.Key{},
+				}
+				feed, err := cfg.Feed(Dependencies{
+					KeysRegistry: keyRegistry,
+					TickProvider: graph.NewProvider(nil, nil),
+					Transport:    transport,
+					Logger:       logger,
+				})
+				require.NoError(t, err)
+				assert.NotNil(t, feed)
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,10 @@
+ethereum_key     = "key"
+interval         = 60
+price_provider   = "gofernext"
+price_batch      = true
+price_batch_only = true
+
+pairs = [
+  "ETH/USD",
+  "BTC/USD",
+]
@@ -3,6 +3,7 @@ package ghost
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -11,9 +12,11 @@ import (
 	feedConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/feed"
 	loggerConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/logger"
 	priceproviderConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/priceprovider"
+	priceprovidernextConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/priceprovidernext"
 	transportConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/feeder"
+	nextProvider "github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
 
 	pkgSupervisor "github.com/chronicleprotocol/oracle-suite/pkg/supervisor"
 	"github.com/chronicleprotocol/oracle-suite/pkg/sysmon"
@@ -23,11 +26,12 @@ import (
 
 // Config is the configuration for Lair.
 type Config struct {
-	Ghost     feedConfig.Config          `hcl:"ghost,block"`
-	Gofer     priceproviderConfig.Config `hcl:"gofer,block"`
-	Ethereum  ethereumConfig.Config      `hcl:"ethereum,block"`
-	Transport transportConfig.Config     `hcl:"transport,block"`
-	Logger    *loggerConfig.Config       `hcl:"logger,block,optional"`
+	Ghost     feedConfig.Config               `hcl:"ghost,block"`
+	Gofer     *priceproviderConfig.Config     `hcl:"gofer,block,optional"`
+	GoferNext *priceprovidernextConfig.Config `hcl:"gofernext,block,optional"`
+	Ethereum  ethereumConfig.Config           `hcl:"ethereum,block"`
+	Transport transportConfig.Config          `hcl:"transport,block"`
+	Logger    *loggerConfig.Config            `hcl:"logger,block,optional"`
 
 	// HCL fields:
 	Remain  hcl.Body        `hcl:",remain"` // To ignore unknown blocks.
@@ -36,9 +40,10 @@ type Config struct {
 
 // Services returns the services that are configured from the Config struct.
 type Services struct {
-	Feed      *feeder.Feeder
-	Transport pkgTransport.Transport
-	Logger    log.Logger
+	Feed         *feeder.Feeder
+	TickProvider nextProvider.Provider
+	Transport    pkgTransport.Transport
+	Logger       log.Logger
 
 	supervisor *pkgSupervisor.Supervisor
 }
@@ -50,6 +55,9 @@ func (s *Services) Start(ctx context.Context) error {
 	}
 	s.supervisor = pkgSupervisor.New(s.Logger)
 	s.supervisor.Watch(s.Transport, s.Feed, sysmon.New(time.Minute, s.Logger))
+	if p, ok := s.TickProvider.(pkgSupervisor.Service); ok {
+		s.supervisor.Watch(p)
+	}
 	if l, ok := s.Logger.(pkgSupervisor.Service); ok {
 		s.supervisor.Watch(l)
 	}
@@ -84,31 +92,60 @@ func (c *Config) Services(baseLogger log.Logger, noRPC bool) (*Services, error)
 		Messages: map[string]pkgTransport.Message{
 			messages.PriceV0MessageName: (*messages.Price)(nil),
 			messages.PriceV1MessageName: (*messages.Price)(nil),
+			messages.PriceV2MessageName: (*messages.PriceBatch)(nil),
 		},
 		Logger: logger,
 	})
 	if err != nil {
 		return nil, err
 	}
-	gofer, err := c.Gofer.PriceProvider(priceproviderConfig.Dependencies{
-		Clients: clients,
-		Logger:  logger,
-	}, noRPC)
-	if err != nil {
-		return nil, err
+	feedDeps := feedConfig.Dependencies{
+		KeysRegistry: keys,
+		Transport:    transport,
+		Logger:       logger,
 	}
-	ghost, err := c.Ghost.Feed(feedConfig.Dependencies{
-		KeysRegistry:  keys,
-		PriceProvider: gofer,
-		Transport:     transport,
-		Logger:        logger,
-	})
+	if c.Ghost.UsesGoferNext() {
+		if c.GoferNext == nil {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Validation error",
+				Detail:   "The gofernext block is required to use the gofernext price provider",
+				Subject:  c.Ghost.Range.Ptr(),
+			}
+		}
+		feedDeps.TickProvider, err = c.GoferNext.PriceProvider(priceprovidernextConfig.Dependencies{
+			HTTPClient: &http.Client{},
+			Clients:    clients,
+			Logger:     logger,
+		}, noRPC)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if c.Gofer == nil {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Validation error",
+				Detail:   "The gofer block is required to use the gofer price provider",
+				Subject:  c.Ghost.Range.Ptr(),
+			}
+		}
+		feedDeps.PriceProvider, err = c.Gofer.PriceProvider(priceproviderConfig.Dependencies{
+			Clients: clients,
+			Logger:  logger,
+		}, noRPC)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ghost, err := c.Ghost.Feed(feedDeps)
 	if err != nil {
 		return nil, err
 	}
 	return &Services{
-		Feed:      ghost,
-		Transport: transport,
-		Logger:    logger,
+		Feed:         ghost,
+		TickProvider: feedDeps.TickProvider,
+		Transport:    transport,
+		Logger:       logger,
 	}, nil
 }
@@ -24,6 +24,16 @@ func TestConfig(t *testing.T) {
 				require.NotNil(t, services.Logger)
 			},
 		},
+		{
+			path: "config_gofernext.hcl",
+			test: func(t *testing.T, cfg *Config) {
+				services, err := cfg.Services(null.New(), false)
+				require.NoError(t, err)
+				require.NotNil(t, services.Feed)
+				require.NotNil(t, services.Transport)
+				require.NotNil(t, services.Logger)
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.path, func(t *testing.T) {
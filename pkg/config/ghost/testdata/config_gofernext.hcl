@@ -0,0 +1,41 @@
+ghost {
+  ethereum_key   = "key1"
+  interval       = 60
+  price_provider = "gofernext"
+
+  pairs = [
+    "ETH/USD",
+    "BTC/USD",
+  ]
+}
+
+gofernext {
+  origin "coinbase" {
+    origin = "generic_jq"
+    url    = "https://api.pro.coinbase.com/products/$${ucbase}-$${ucquote}/ticker"
+    jq     = "{price: .price, time: .time, volume: .volume}"
+  }
+
+  price_model "ETH/USD" "ETH/USD" {
+    origin "coinbase" "ETH/USD" {}
+  }
+}
+
+ethereum {
+  rand_keys = ["key1"]
+
+  client "client1" {
+    rpc_urls     = ["https://rpc1.example"]
+    chain_id     = 1
+    ethereum_key = "key1"
+  }
+}
+
+transport {
+  libp2p {
+    feeds             = ["0x1234567890123456789012345678901234567890"]
+    listen_addrs      = ["/ip4/0.0.0.0/tcp/6000"]
+    disable_discovery = false
+    ethereum_key      = "key1"
+  }
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 
@@ -12,7 +13,11 @@ import (
 	priceProviderConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/priceprovidernext"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/api"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/replay"
 	pkgSupervisor "github.com/chronicleprotocol/oracle-suite/pkg/supervisor"
+	"github.com/chronicleprotocol/oracle-suite/pkg/sysmon"
 )
 
 // Config is the configuration for Gofer.
@@ -21,6 +26,10 @@ type Config struct {
 	Ethereum *ethereumConfig.Config     `hcl:"ethereum,block,optional"`
 	Logger   *loggerConfig.Config       `hcl:"logger,block,optional"`
 
+	// Fixture, if set, is used to record responses of origins or to replay
+	// previously recorded ones instead of querying origins.
+	Fixture *replay.Fixture
+
 	// HCL fields:
 	Remain  hcl.Body        `hcl:",remain"` // To ignore unknown blocks.
 	Content hcl.BodyContent `hcl:",content"`
@@ -54,8 +63,43 @@ func (s *Services) Wait() <-chan error {
 	return s.supervisor.Wait()
 }
 
+// AgentServices returns the services that are configured from the Config
+// struct for the agent mode.
+type AgentServices struct {
+	PriceProvider provider.Provider
+	Updater       *graph.UpdaterService
+	Agent         *api.Server
+	Logger        log.Logger
+
+	supervisor *pkgSupervisor.Supervisor
+}
+
+// Start implements the supervisor.Service interface.
+func (s *AgentServices) Start(ctx context.Context) error {
+	if s.supervisor != nil {
+		return fmt.Errorf("services already started")
+	}
+	s.supervisor = pkgSupervisor.New(s.Logger)
+	s.supervisor.Watch(s.Updater, s.Agent, sysmon.New(time.Minute, s.Logger))
+	if p, ok := s.PriceProvider.(pkgSupervisor.Service); ok {
+		s.supervisor.Watch(p)
+	}
+	if l, ok := s.Logger.(pkgSupervisor.Service); ok {
+		s.supervisor.Watch(l)
+	}
+	return s.supervisor.Start(ctx)
+}
+
+// Wait implements the supervisor.Service interface.
+func (s *AgentServices) Wait() <-chan error {
+	return s.supervisor.Wait()
+}
+
 // Services returns the services configured for Gofer.
-func (c *Config) Services(baseLogger log.Logger) (*Services, error) {
+//
+// If noAgent is true, or the fixture is set, local price models are used even
+// if the agent address is configured.
+func (c *Config) Services(baseLogger log.Logger, noAgent bool) (*Services, error) {
 	logger, err := c.Logger.Logger(loggerConfig.Dependencies{
 		AppName:    "gofer",
 		BaseLogger: baseLogger,
@@ -67,7 +111,47 @@ func (c *Config) Services(baseLogger log.Logger) (*Services, error) {
 	if err != nil {
 		return nil, err
 	}
+	httpClient := &http.Client{}
+	var clock func() time.Time
+	if c.Fixture != nil {
+		// Recorded responses are only meaningful for local price models.
+		noAgent = true
+		httpClient.Transport = c.Fixture.RoundTripper(nil)
+		clients = c.Fixture.RPCClients(clients)
+
+		// Ticks are evaluated as of the recording time, so replayed prices
+		// do not depend on when the fixture is replayed.
+		clock = c.Fixture.Time
+	}
 	priceProvider, err := c.Gofer.PriceProvider(priceProviderConfig.Dependencies{
+		HTTPClient: httpClient,
+		Clients:    clients,
+		Logger:     logger,
+		Clock:      clock,
+	}, noAgent)
+	if err != nil {
+		return nil, err
+	}
+	return &Services{
+		PriceProvider: priceProvider,
+		Logger:        logger,
+	}, nil
+}
+
+// AgentServices returns the services configured for Gofer Agent.
+func (c *Config) AgentServices(baseLogger log.Logger) (*AgentServices, error) {
+	logger, err := c.Logger.Logger(loggerConfig.Dependencies{
+		AppName:    "gofer",
+		BaseLogger: baseLogger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	clients, err := c.Ethereum.ClientRegistry(ethereumConfig.Dependencies{Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	priceProvider, updater, err := c.Gofer.AsyncPriceProvider(priceProviderConfig.Dependencies{
 		HTTPClient: &http.Client{},
 		Clients:    clients,
 		Logger:     logger,
@@ -75,8 +159,17 @@ func (c *Config) Services(baseLogger log.Logger) (*Services, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Services{
+	agent, err := c.Gofer.Agent(priceProviderConfig.AgentDependencies{
+		Provider: priceProvider,
+		Logger:   logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AgentServices{
 		PriceProvider: priceProvider,
+		Updater:       updater,
+		Agent:         agent,
 		Logger:        logger,
 	}, nil
 }
@@ -0,0 +1,321 @@
+package priceprovider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
+)
+
+// Conversion is the result of converting the configuration to the gofernext
+// format.
+type Conversion struct {
+	// Config is the converted configuration with a single "gofernext" block.
+	Config []byte
+
+	// Report lists everything that could not be converted automatically or
+	// that behaves differently after the conversion and should be reviewed.
+	Report []string
+}
+
+// jqOrigin describes how to query an exchange using the generic_jq origin.
+type jqOrigin struct {
+	baseURL string
+	path    string
+	jq      string
+}
+
+// jqOrigins maps legacy origin types to their generic_jq equivalents. The
+// URLs and response fields are the same as in the legacy implementations.
+var jqOrigins = map[string]jqOrigin{
+	"binance": {
+		baseURL: "https://api.binance.com",
+		path:    "/api/v3/ticker/24hr",
+		jq:      `.[] | select(.symbol == ($ucbase + $ucquote)) | {price: .lastPrice, volume: .volume, time: (.closeTime / 1000)}`,
+	},
+	"bitstamp": {
+		baseURL: "https://www.bitstamp.net",
+		path:    "/api/v2/ticker/${lcbase}${lcquote}",
+		jq:      `{price: .last, volume: .volume, time: (.timestamp | tonumber)}`,
+	},
+	"coinbase": {
+		baseURL: "https://api.pro.coinbase.com",
+		path:    "/products/${ucbase}-${ucquote}/ticker",
+		jq:      `{price: .price, time: .time, volume: .volume}`,
+	},
+	"coinbasepro": {
+		baseURL: "https://api.pro.coinbase.com",
+		path:    "/products/${ucbase}-${ucquote}/ticker",
+		jq:      `{price: .price, time: .time, volume: .volume}`,
+	},
+	"gemini": {
+		baseURL: "https://api.gemini.com",
+		path:    "/v1/pubticker/${lcbase}${lcquote}",
+		jq:      `{price: .last}`,
+	},
+	"kraken": {
+		baseURL: "https://api.kraken.com",
+		path:    "/0/public/Ticker?pair=${ucbase}/${ucquote}",
+		jq:      `.result[$ucbase + "/" + $ucquote] | {price: .c[0], volume: .v[0]}`,
+	},
+	"kucoin": {
+		baseURL: "https://api.kucoin.com",
+		path:    "/api/v1/market/orderbook/level1?symbol=${ucbase}-${ucquote}",
+		jq:      `.data | {price: .price, time: (.time / 1000)}`,
+	},
+	"okx": {
+		baseURL: "https://www.okx.com",
+		path:    "/api/v5/market/ticker?instId=${ucbase}-${ucquote}-SWAP",
+		jq:      `.data[0] | {price: .last, volume: .vol24h, time: (.ts | tonumber / 1000)}`,
+	},
+}
+
+// evmOrigins maps legacy origin types that read a single contract method to
+// the method signature used by the generic_evm origin.
+var evmOrigins = map[string]string{
+	"wsteth":     "stEthPerToken() view returns (uint256)",
+	"rocketpool": "getExchangeRate() view returns (uint256)",
+}
+
+// dexOrigins maps legacy origin types to the gofernext origins that read
+// pool reserves directly from the blockchain.
+var dexOrigins = map[string]string{
+	"uniswap":    "uniswap_v2",
+	"uniswapV2":  "uniswap_v2",
+	"uniswapV3":  "uniswap_v3",
+	"balancerV2": "balancer_v2",
+}
+
+// Convert converts the configuration to the gofernext format.
+//
+// Origins are converted to their generic_jq, generic_evm or DEX equivalents
+// where possible, and price models are converted to gofernext node blocks.
+// Origins that cannot be converted are listed in the report, but nodes that
+// use them are still converted, so the configuration will not be valid until
+// these origins are configured manually.
+func (c *Config) Convert() Conversion {
+	cv := &converter{
+		origins: make(map[string]configOrigin),
+		used:    make(map[string]bool),
+	}
+	for _, o := range c.Origins {
+		cv.origins[o.Origin] = o
+	}
+
+	// Price models are converted first to find out which origins are used.
+	models := hclwrite.NewEmptyFile()
+	for _, pm := range c.PriceModels {
+		body := models.Body().AppendNewBlock("price_model", []string{pm.Pair.String(), pm.Pair.String()}).Body()
+		cv.convertSource(body, pm)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	block := f.Body().AppendNewBlock("gofernext", nil).Body()
+	for _, name := range cv.order {
+		if b := cv.convertOrigin(name); b != nil {
+			if len(block.Blocks()) > 0 {
+				block.AppendNewline()
+			}
+			block.AppendBlock(b)
+		}
+	}
+	for _, o := range c.Origins {
+		if !cv.used[o.Origin] {
+			cv.report("Origin %s is not used by any price model and was skipped", o.Origin)
+		}
+	}
+	for _, b := range models.Body().Blocks() {
+		block.AppendNewline()
+		block.AppendBlock(b)
+	}
+	for _, h := range c.Hooks {
+		cv.report("Hook for %s was skipped: hooks are not supported by gofernext", h.Pair)
+	}
+	for _, client := range cv.clients {
+		cv.report("Ethereum client %s must be configured in the ethereum block", client)
+	}
+	return Conversion{Config: f.Bytes(), Report: cv.messages}
+}
+
+type converter struct {
+	origins  map[string]configOrigin // configured origins by name
+	used     map[string]bool         // origins used by price models
+	order    []string                // used origins in the order of use
+	clients  []string                // Ethereum clients used by converted origins
+	messages []string
+}
+
+func (c *converter) report(format string, args ...any) {
+	c.messages = append(c.messages, fmt.Sprintf(format, args...))
+}
+
+// originType returns the type and params of the origin with the given name.
+// Origins that are not configured are default origins, whose type is the
+// same as their name.
+func (c *converter) originType(name string) (string, map[string]any) {
+	if o, ok := c.origins[name]; ok {
+		return o.Type, o.Params
+	}
+	return name, nil
+}
+
+func (c *converter) useOrigin(name string) {
+	if !c.used[name] {
+		c.used[name] = true
+		c.order = append(c.order, name)
+	}
+}
+
+func (c *converter) useClient(name string) {
+	for _, n := range c.clients {
+		if n == name {
+			return
+		}
+	}
+	c.clients = append(c.clients, name)
+}
+
+func (c *converter) convertSource(body *hclwrite.Body, source configSource) {
+	label := []string{source.Pair.String()}
+	switch source.Type {
+	case "origin":
+		name := source.Origin.Origin
+		if name == "." {
+			b := body.AppendNewBlock("reference", label).Body()
+			b.SetAttributeValue("price_model", cty.StringVal(source.Pair.String()))
+			return
+		}
+		c.useOrigin(name)
+		b := body.AppendNewBlock("origin", []string{name, source.Pair.String()}).Body()
+		_, params := c.originType(name)
+		if aliases := parseParamsSymbolAliases(params); aliases != nil {
+			fetchPair := source.Pair
+			if s, ok := aliases[fetchPair.Base]; ok {
+				fetchPair.Base = s
+			}
+			if s, ok := aliases[fetchPair.Quote]; ok {
+				fetchPair.Quote = s
+			}
+			if fetchPair != source.Pair {
+				b.SetAttributeValue("fetch_pair", cty.StringVal(fetchPair.String()))
+			}
+		}
+	case "median", "indirect":
+		// Legacy aggregators with a single child return the price of that
+		// child directly.
+		if len(source.Sources) == 1 {
+			c.convertSource(body, source.Sources[0])
+			return
+		}
+		b := body.AppendNewBlock(source.Type, label).Body()
+		for _, s := range source.Sources {
+			c.convertSource(b, s)
+		}
+		if source.Type == "median" {
+			b.SetAttributeValue("min_sources", cty.NumberIntVal(int64(source.Median.MinSources)))
+		}
+	default:
+		c.report("Node %s of unknown type %s was skipped", source.Pair, source.Type)
+	}
+}
+
+// convertOrigin returns the converted origin block or nil if the origin
+// cannot be converted.
+func (c *converter) convertOrigin(name string) *hclwrite.Block {
+	typ, params := c.originType(name)
+	if o, ok := jqOrigins[typ]; ok {
+		baseURL := parseSingleParam(params, "url")
+		if baseURL == "" {
+			baseURL = o.baseURL
+		}
+		ob := hclwrite.NewBlock("origin", []string{name})
+		b := ob.Body()
+		b.SetAttributeValue("origin", cty.StringVal("generic_jq"))
+		b.SetAttributeValue("url", cty.StringVal(strings.TrimSuffix(baseURL, "/")+o.path))
+		b.SetAttributeValue("jq", cty.StringVal(o.jq))
+		return ob
+	}
+	if abi, ok := evmOrigins[typ]; ok {
+		client := c.ethereumClient(params)
+		ob := hclwrite.NewBlock("origin", []string{name})
+		b := ob.Body()
+		b.SetAttributeValue("origin", cty.StringVal("generic_evm"))
+		b.SetAttributeValue("ethereum_client", cty.StringVal(client))
+		for _, pair := range c.contracts(name, params) {
+			for _, invert := range []bool{false, true} {
+				p := pair.pair
+				if invert {
+					p = provider.Pair{Base: p.Quote, Quote: p.Base}
+				}
+				pb := b.AppendNewBlock("pair", []string{p.String()}).Body()
+				pb.SetAttributeValue("contract", cty.StringVal(pair.address))
+				pb.SetAttributeValue("abi", cty.StringVal(abi))
+				pb.SetAttributeValue("arguments", cty.EmptyTupleVal)
+				pb.SetAttributeValue("decimals", cty.NumberIntVal(18))
+				if invert {
+					pb.SetAttributeValue("invert", cty.True)
+				}
+			}
+		}
+		c.report("Origin %s averaged prices from several blocks, the converted origin uses only the latest block", name)
+		return ob
+	}
+	if dex, ok := dexOrigins[typ]; ok {
+		client := c.ethereumClient(params)
+		ob := hclwrite.NewBlock("origin", []string{name})
+		b := ob.Body()
+		b.SetAttributeValue("origin", cty.StringVal(dex))
+		b.SetAttributeValue("ethereum_client", cty.StringVal(client))
+		for _, pair := range c.contracts(name, params) {
+			pb := b.AppendNewBlock("pool", []string{pair.pair.String()}).Body()
+			pb.SetAttributeValue("address", cty.StringVal(pair.address))
+		}
+		if typ != "balancerV2" {
+			c.report("Origin %s used the subgraph API, the converted origin reads pools directly from the blockchain", name)
+		}
+		return ob
+	}
+	c.report("Origin %s of type %s cannot be converted automatically and must be configured manually", name, typ)
+	return nil
+}
+
+func (c *converter) ethereumClient(params map[string]any) string {
+	client := parseSingleParam(params, "ethereum_client")
+	if client == "" {
+		client = "default"
+	}
+	c.useClient(client)
+	return client
+}
+
+type contract struct {
+	pair    provider.Pair
+	address string
+}
+
+// contracts returns contracts configured for the origin, sorted by pair.
+func (c *converter) contracts(name string, params map[string]any) []contract {
+	var contracts []contract
+	addrs := parseParamsContracts(params)
+	for _, key := range maputil.SortKeys(addrs, sort.Strings) {
+		if strings.HasPrefix(key, "Ref:") {
+			c.report("Rate provider %s of origin %s was skipped", key, name)
+			continue
+		}
+		pair, err := provider.NewPair(key)
+		if err != nil {
+			c.report("Contract %s of origin %s was skipped: %s", key, name, err)
+			continue
+		}
+		contracts = append(contracts, contract{pair: pair, address: addrs[key]})
+	}
+	if len(contracts) == 0 {
+		c.report("Origin %s has no contracts configured", name)
+	}
+	return contracts
+}
@@ -0,0 +1,47 @@
+package priceprovider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	nextConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/priceprovidernext"
+)
+
+func TestConfig_Convert(t *testing.T) {
+	cfg := &Config{}
+	require.NoError(t, config.LoadFiles(cfg, []string{"./testdata/convert/legacy.hcl"}))
+
+	expected, err := os.ReadFile("./testdata/convert/expected.hcl")
+	require.NoError(t, err)
+
+	conv := cfg.Convert()
+	assert.Equal(t, string(expected), string(conv.Config))
+	assert.Equal(t, []string{
+		"Origin uniswapV3 used the subgraph API, the converted origin reads pools directly from the blockchain",
+		"Origin wsteth averaged prices from several blocks, the converted origin uses only the latest block",
+		"Origin curve of type curve cannot be converted automatically and must be configured manually",
+		"Origin unused is not used by any price model and was skipped",
+		"Hook for BTC/USD was skipped: hooks are not supported by gofernext",
+		"Ethereum client default must be configured in the ethereum block",
+		"Ethereum client mainnet must be configured in the ethereum block",
+	}, conv.Report)
+
+	// The converted config must be valid except for the origin that could
+	// not be converted.
+	path := filepath.Join(t.TempDir(), "config.hcl")
+	require.NoError(t, os.WriteFile(path, conv.Config, 0600))
+	next := struct {
+		Gofer   nextConfig.Config `hcl:"gofernext,block"`
+		Content hcl.BodyContent   `hcl:",content"`
+	}{}
+	require.NoError(t, config.LoadFiles(&next, []string{path}))
+	diags := next.Gofer.Validate()
+	require.Len(t, diags, 1)
+	assert.Equal(t, "Unknown origin: curve", diags[0].Detail)
+}
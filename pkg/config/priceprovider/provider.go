@@ -138,7 +138,8 @@ type configHook struct {
 	// Pair is the pair of the hook in the form of "base/quote".
 	Pair provider.Pair `hcl:",label"`
 
-	// PostPriceHook is the configuration of the post price hook.
+	// PostPriceHook is the configuration of the post price hook. The "type"
+	// parameter specifies the check to perform.
 	PostPriceHook map[string]any `hcl:"post_price,optional"`
 }
 
@@ -249,7 +250,7 @@ func (c *Config) PriceHook(d HookDependencies) (provider.PriceHook, error) {
 	params := provider.NewHookParams()
 	for _, hook := range c.Hooks {
 		if len(hook.PostPriceHook) > 0 {
-			params[hook.Pair.String()] = hook.PostPriceHook
+			params[hook.Pair.String()] = append(params[hook.Pair.String()], hook.PostPriceHook)
 		}
 	}
 	priceHook, err := provider.NewPostPriceHook(d.Context, d.Clients, params)
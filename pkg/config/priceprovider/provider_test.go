@@ -3,6 +3,7 @@ package priceprovider
 import (
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -76,6 +77,29 @@ func TestConfig(t *testing.T) {
 				assert.NotNil(t, priceProvider)
 			},
 		},
+		{
+			name: "validate",
+			path: "config.hcl",
+			test: func(t *testing.T, cfg *Config) {
+				var errors, warnings []string
+				for _, diag := range cfg.Validate() {
+					require.NotNil(t, diag.Subject)
+					switch diag.Severity {
+					case hcl.DiagError:
+						errors = append(errors, diag.Detail)
+					case hcl.DiagWarning:
+						warnings = append(warnings, diag.Detail)
+					}
+				}
+				assert.Equal(t, []string{
+					"Minimum number of sources (3) is greater than the number of sources (2)",
+					"Unknown origin: origin1",
+					"Unknown origin: origin2",
+					"Unknown origin: origin3",
+				}, errors)
+				assert.Equal(t, []string{"Origin origin is not used by any price model"}, warnings)
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,84 @@
+gofernext {
+  origin "coinbase" {
+    origin = "generic_jq"
+    url    = "https://api.pro.coinbase.com/products/$${ucbase}-$${ucquote}/ticker"
+    jq     = "{price: .price, time: .time, volume: .volume}"
+  }
+
+  origin "kraken_usdt" {
+    origin = "generic_jq"
+    url    = "https://kraken.example.com/0/public/Ticker?pair=$${ucbase}/$${ucquote}"
+    jq     = ".result[$ucbase + \"/\" + $ucquote] | {price: .c[0], volume: .v[0]}"
+  }
+
+  origin "binance" {
+    origin = "generic_jq"
+    url    = "https://api.binance.com/api/v3/ticker/24hr"
+    jq     = ".[] | select(.symbol == ($ucbase + $ucquote)) | {price: .lastPrice, volume: .volume, time: (.closeTime / 1000)}"
+  }
+
+  origin "uniswapV3" {
+    origin          = "uniswap_v3"
+    ethereum_client = "default"
+    pool "USDC/WETH" {
+      address = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+    }
+  }
+
+  origin "wsteth" {
+    origin          = "generic_evm"
+    ethereum_client = "mainnet"
+    pair "WSTETH/STETH" {
+      contract  = "0x7f39c581f595b53c5cb19bd0b3f8da6c935e2ca0"
+      abi       = "stEthPerToken() view returns (uint256)"
+      arguments = []
+      decimals  = 18
+    }
+    pair "STETH/WSTETH" {
+      contract  = "0x7f39c581f595b53c5cb19bd0b3f8da6c935e2ca0"
+      abi       = "stEthPerToken() view returns (uint256)"
+      arguments = []
+      decimals  = 18
+      invert    = true
+    }
+  }
+
+  price_model "BTC/USD" "BTC/USD" {
+    median "BTC/USD" {
+      origin "coinbase" "BTC/USD" {
+      }
+      origin "kraken_usdt" "BTC/USD" {
+        fetch_pair = "BTC/USDT"
+      }
+      indirect "BTC/USD" {
+        origin "binance" "BTC/ETH" {
+        }
+        reference "ETH/USD" {
+          price_model = "ETH/USD"
+        }
+      }
+      min_sources = 2
+    }
+  }
+
+  price_model "ETH/USD" "ETH/USD" {
+    indirect "ETH/USD" {
+      origin "uniswapV3" "ETH/USDC" {
+      }
+      origin "coinbase" "USDC/USD" {
+      }
+    }
+  }
+
+  price_model "WSTETH/USD" "WSTETH/USD" {
+    indirect "WSTETH/USD" {
+      origin "wsteth" "WSTETH/STETH" {
+      }
+      origin "curve" "STETH/ETH" {
+      }
+      reference "ETH/USD" {
+        price_model = "ETH/USD"
+      }
+    }
+  }
+}
@@ -0,0 +1,71 @@
+origin "kraken_usdt" {
+  type   = "kraken"
+  params = {
+    url            = "https://kraken.example.com/"
+    symbol_aliases = {
+      "USD" = "USDT"
+    }
+  }
+}
+
+origin "wsteth" {
+  type   = "wsteth"
+  params = {
+    ethereum_client = "mainnet"
+    contracts       = {
+      "WSTETH/STETH" = "0x7f39c581f595b53c5cb19bd0b3f8da6c935e2ca0"
+    }
+  }
+}
+
+origin "uniswapV3" {
+  type   = "uniswapV3"
+  params = {
+    contracts = {
+      "USDC/WETH" = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+    }
+  }
+}
+
+origin "curve" {
+  type   = "curve"
+  params = {
+    contracts = {
+      "STETH/ETH" = "0xdc24316b9ae028f1497c275eb9192a3ea0f67022"
+    }
+  }
+}
+
+origin "unused" {
+  type = "gemini"
+}
+
+price_model "BTC/USD" "median" {
+  source "BTC/USD" "origin" { origin = "coinbase" }
+  source "BTC/USD" "origin" { origin = "kraken_usdt" }
+  source "BTC/USD" "indirect" {
+    source "BTC/ETH" "origin" { origin = "binance" }
+    source "ETH/USD" "origin" { origin = "." }
+  }
+  min_sources = 2
+}
+
+price_model "ETH/USD" "median" {
+  source "ETH/USD" "indirect" {
+    source "ETH/USDC" "origin" { origin = "uniswapV3" }
+    source "USDC/USD" "origin" { origin = "coinbase" }
+  }
+  min_sources = 1
+}
+
+price_model "WSTETH/USD" "indirect" {
+  source "WSTETH/STETH" "origin" { origin = "wsteth" }
+  source "STETH/ETH" "origin" { origin = "curve" }
+  source "ETH/USD" "origin" { origin = "." }
+}
+
+hook "BTC/USD" {
+  post_price = {
+    ethereum_client = "mainnet"
+  }
+}
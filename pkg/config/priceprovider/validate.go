@@ -0,0 +1,143 @@
+package priceprovider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/graph/nodes"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/origins"
+)
+
+// Validate checks the price models without creating origins, so no network
+// access is needed.
+//
+// Unlike PriceProvider, Validate does not stop at the first problem and
+// reports all of them. Problems that do not prevent prices from being
+// calculated, like unused origins, are reported as warnings.
+func (c *Config) Validate() hcl.Diagnostics {
+	v := &validator{
+		origins:     make(map[string]bool),
+		priceModels: make(map[provider.Pair]bool),
+	}
+	for name := range origins.DefaultOriginSet(nil).Handlers() {
+		v.origins[name] = false
+	}
+	for _, o := range c.Origins {
+		v.origins[o.Origin] = false
+	}
+	for _, pm := range c.PriceModels {
+		v.priceModels[pm.Pair] = true
+	}
+	for _, pm := range c.PriceModels {
+		v.validateSource(pm)
+	}
+	for _, o := range c.Origins {
+		if !v.origins[o.Origin] {
+			v.diags = append(v.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Validation warning",
+				Detail:   fmt.Sprintf("Origin %s is not used by any price model", o.Origin),
+				Subject:  o.Range.Ptr(),
+			})
+		}
+	}
+
+	// Build the graphs to detect cycles. If there are already errors,
+	// building the graphs would most likely report one of them again.
+	if !v.diags.HasErrors() {
+		if _, err := c.buildGraphs(); err != nil {
+			v.diags = append(v.diags, toDiagnostics(err)...)
+		}
+	}
+	return v.diags
+}
+
+type validator struct {
+	origins     map[string]bool // origin name -> used by a price model
+	priceModels map[provider.Pair]bool
+	diags       hcl.Diagnostics
+}
+
+func (v *validator) validateSource(source configSource) {
+	switch source.Type {
+	case "origin":
+		name := source.Origin.Origin
+		switch {
+		case name == ".":
+			if !v.priceModels[source.Pair] {
+				v.error(source, "Reference to unknown pair %s", source.Pair)
+			}
+		case !v.isKnownOrigin(name):
+			v.error(source, "Unknown origin: %s", name)
+		default:
+			v.origins[name] = true
+		}
+	case "median":
+		if len(source.Sources) == 0 {
+			v.error(source, "Median aggregator must have at least one child")
+		}
+		if source.Median.MinSources > len(source.Sources) {
+			v.error(
+				source,
+				"Minimum number of sources (%d) is greater than the number of sources (%d)",
+				source.Median.MinSources,
+				len(source.Sources),
+			)
+		}
+	case "indirect":
+		if len(source.Sources) == 0 {
+			v.error(source, "Indirect aggregator must have at least one child")
+			break
+		}
+		pairs := make([]provider.Pair, len(source.Sources))
+		for i, s := range source.Sources {
+			pairs[i] = s.Pair
+		}
+		pair, err := nodes.IndirectPair(pairs)
+		if err != nil {
+			v.error(source, "Pairs of sources do not connect: %s", err)
+			break
+		}
+		if pair != source.Pair {
+			v.error(source, "Sources resolve to pair %s, expected %s", pair, source.Pair)
+		}
+	default:
+		v.error(source, "Unknown node type: %s", source.Type)
+	}
+	for _, s := range source.Sources {
+		v.validateSource(s)
+	}
+}
+
+func (v *validator) isKnownOrigin(name string) bool {
+	_, ok := v.origins[name]
+	return ok
+}
+
+func (v *validator) error(source configSource, format string, args ...any) {
+	v.diags = append(v.diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Validation error",
+		Detail:   fmt.Sprintf(format, args...),
+		Subject:  source.Range.Ptr(),
+	})
+}
+
+// toDiagnostics converts an error returned while building the graphs to
+// diagnostics.
+func toDiagnostics(err error) hcl.Diagnostics {
+	switch err := err.(type) {
+	case hcl.Diagnostics:
+		return err
+	case *hcl.Diagnostic:
+		return hcl.Diagnostics{err}
+	default:
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   err.Error(),
+		}}
+	}
+}
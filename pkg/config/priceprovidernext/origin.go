@@ -2,13 +2,17 @@ package priceprovider
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
 	"github.com/defiweb/go-eth/types"
 	"github.com/hashicorp/hcl/v2"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/origin"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
 
 	utilHCL "github.com/chronicleprotocol/oracle-suite/pkg/util/hcl"
 )
@@ -20,6 +24,10 @@ type configOrigin struct {
 	// Origin is the name of the origin provider.
 	Origin string `hcl:"origin"`
 
+	// HTTP configures rate limiting, retries and caching of HTTP requests
+	// sent by the origin.
+	HTTP *configOriginHTTP `hcl:"http,block,optional"`
+
 	OriginConfig any // Handled by PostDecodeBlock method.
 
 	// HCL fields:
@@ -28,11 +36,37 @@ type configOrigin struct {
 	Range   hcl.Range       `hcl:",range"`
 }
 
+type configOriginHTTP struct {
+	// RateLimit is the maximum number of requests per second sent to
+	// a single host.
+	RateLimit float64 `hcl:"rate_limit,optional"`
+
+	// Burst is the maximum number of requests sent to a single host at once.
+	Burst int `hcl:"burst,optional"`
+
+	// MaxRetries is the maximum number of retries of a failed request.
+	MaxRetries int `hcl:"max_retries,optional"`
+
+	// MinBackoff and MaxBackoff are bounds of the delay between retries,
+	// in seconds.
+	MinBackoff int `hcl:"min_backoff,optional"`
+	MaxBackoff int `hcl:"max_backoff,optional"`
+
+	// CacheTTL is the time in seconds for which responses are cached.
+	CacheTTL int `hcl:"cache_ttl,optional"`
+}
+
 type configOriginGenericJQ struct {
 	URL string `hcl:"url"` // Do not use config.URL because it encode $ sign
 	JQ  string `hcl:"jq"`
 }
 
+type configOriginGenericWebSocket struct {
+	URL           string   `hcl:"url"` // Do not use config.URL because it encode $ sign
+	Subscriptions []string `hcl:"subscriptions,optional"`
+	JQ            string   `hcl:"jq"`
+}
+
 type configOriginGenericEVM struct {
 	EthereumClient string                       `hcl:"ethereum_client"`
 	Pairs          []configOriginGenericEVMPair `hcl:"pair,block"`
@@ -48,10 +82,60 @@ type configOriginGenericEVMPair struct {
 	Arguments []any         `hcl:"arguments"`
 	Decimals  uint8         `hcl:"decimals"`
 
+	// PriceIndex is the index of the return value that contains the price.
+	PriceIndex int `hcl:"price_index,optional"`
+
+	// TimestampIndex is the index of the return value that contains the
+	// Unix timestamp of the price. If not set, the fetch time is used.
+	TimestampIndex *int `hcl:"timestamp_index,optional"`
+
+	// Scale is a factor by which the price is multiplied.
+	Scale *float64 `hcl:"scale,optional"`
+
+	// Invert indicates that the price must be inverted after scaling.
+	Invert bool `hcl:"invert,optional"`
+
+	// HCL fields:
+	Content hcl.BodyContent `hcl:",content"`
+}
+
+type configOriginDEX struct {
+	EthereumClient string                `hcl:"ethereum_client"`
+	BlockOffset    uint64                `hcl:"block_offset,optional"`
+	Pools          []configOriginDEXPool `hcl:"pool,block"`
+
+	// HCL fields:
+	Content hcl.BodyContent `hcl:",content"`
+}
+
+type configOriginDEXPool struct {
+	// Pair is the pair of tokens in the same order as in the pool.
+	Pair    provider.Pair `hcl:"pair,label"`
+	Address types.Address `hcl:"address"`
+}
+
+type configOriginUniswapV2 configOriginDEX
+
+type configOriginUniswapV3 configOriginDEX
+
+type configOriginBalancerV2 configOriginDEX
+
+type configOriginCurve struct {
+	EthereumClient string                  `hcl:"ethereum_client"`
+	BlockOffset    uint64                  `hcl:"block_offset,optional"`
+	Pools          []configOriginCurvePool `hcl:"pool,block"`
+
 	// HCL fields:
 	Content hcl.BodyContent `hcl:",content"`
 }
 
+type configOriginCurvePool struct {
+	Pair       provider.Pair `hcl:"pair,label"`
+	Address    types.Address `hcl:"address"`
+	BaseIndex  int           `hcl:"base_index"`
+	QuoteIndex int           `hcl:"quote_index"`
+}
+
 func (c *configOrigin) PostDecodeBlock(
 	ctx *hcl.EvalContext,
 	_ *hcl.BodySchema,
@@ -62,8 +146,18 @@ func (c *configOrigin) PostDecodeBlock(
 	switch c.Origin {
 	case "generic_jq":
 		config = &configOriginGenericJQ{}
+	case "generic_websocket":
+		config = &configOriginGenericWebSocket{}
 	case "generic_evm":
 		config = &configOriginGenericEVM{}
+	case "uniswap_v2":
+		config = &configOriginUniswapV2{}
+	case "uniswap_v3":
+		config = &configOriginUniswapV3{}
+	case "curve":
+		config = &configOriginCurve{}
+	case "balancer_v2":
+		config = &configOriginBalancerV2{}
 	default:
 		return hcl.Diagnostics{{
 			Severity: hcl.DiagError,
@@ -82,12 +176,34 @@ func (c *configOrigin) PostDecodeBlock(
 func (c *configOrigin) ConfigureOrigin(d Dependencies) (origin.Origin, error) {
 	switch o := c.OriginConfig.(type) {
 	case *configOriginGenericJQ:
+		client, err := c.httpClient(d)
+		if err != nil {
+			return nil, err
+		}
 		origin, err := origin.NewGenericJQ(origin.GenericJQOptions{
 			URL:     o.URL,
 			Query:   o.JQ,
 			Headers: nil,
-			Client:  d.HTTPClient,
+			Client:  client,
 			Logger:  d.Logger,
+			Now:     d.Clock,
+		})
+		if err != nil {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Runtime error",
+				Detail:   fmt.Sprintf("Failed to create origin: %s", err),
+				Subject:  c.Range.Ptr(),
+			}
+		}
+		return origin, nil
+	case *configOriginGenericWebSocket:
+		origin, err := origin.NewGenericWebSocket(origin.GenericWebSocketOptions{
+			URL:           o.URL,
+			Subscriptions: o.Subscriptions,
+			Query:         o.JQ,
+			Logger:        d.Logger,
+			Now:           d.Clock,
 		})
 		if err != nil {
 			return nil, &hcl.Diagnostic{
@@ -119,14 +235,26 @@ func (c *configOrigin) ConfigureOrigin(d Dependencies) (origin.Origin, error) {
 					Subject:  p.Content.Attributes["abi"].Range.Ptr(),
 				}
 			}
+			var scale *bn.FloatNumber
+			if p.Scale != nil {
+				scale = bn.Float(*p.Scale)
+			}
 			pairs[p.Pair] = origin.GenericETHContract{
-				Method:    method,
-				Contract:  p.Contract,
-				Arguments: p.Arguments,
-				Decimals:  p.Decimals,
+				Method:         method,
+				Contract:       p.Contract,
+				Arguments:      p.Arguments,
+				Decimals:       p.Decimals,
+				PriceIndex:     p.PriceIndex,
+				TimestampIndex: p.TimestampIndex,
+				Scale:          scale,
+				Invert:         p.Invert,
 			}
 		}
-		origin, err := origin.NewGenericEVM(client, pairs, 0)
+		origin, err := origin.NewGenericEVM(origin.GenericEVMOptions{
+			Client:    client,
+			Contracts: pairs,
+			Now:       d.Clock,
+		})
 		if err != nil {
 			return nil, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -136,6 +264,139 @@ func (c *configOrigin) ConfigureOrigin(d Dependencies) (origin.Origin, error) {
 			}
 		}
 		return origin, nil
+	case *configOriginUniswapV2:
+		client, err := ethereumClient(d, o.EthereumClient, o.Content)
+		if err != nil {
+			return nil, err
+		}
+		origin, err := origin.NewUniswapV2(origin.UniswapV2Options{
+			Client:      client,
+			Pools:       dexPools(o.Pools),
+			BlockOffset: o.BlockOffset,
+			Now:         d.Clock,
+		})
+		if err != nil {
+			return nil, c.runtimeError(err)
+		}
+		return origin, nil
+	case *configOriginUniswapV3:
+		client, err := ethereumClient(d, o.EthereumClient, o.Content)
+		if err != nil {
+			return nil, err
+		}
+		origin, err := origin.NewUniswapV3(origin.UniswapV3Options{
+			Client:      client,
+			Pools:       dexPools(o.Pools),
+			BlockOffset: o.BlockOffset,
+			Now:         d.Clock,
+		})
+		if err != nil {
+			return nil, c.runtimeError(err)
+		}
+		return origin, nil
+	case *configOriginBalancerV2:
+		client, err := ethereumClient(d, o.EthereumClient, o.Content)
+		if err != nil {
+			return nil, err
+		}
+		origin, err := origin.NewBalancerV2(origin.BalancerV2Options{
+			Client:      client,
+			Pools:       dexPools(o.Pools),
+			BlockOffset: o.BlockOffset,
+			Now:         d.Clock,
+		})
+		if err != nil {
+			return nil, c.runtimeError(err)
+		}
+		return origin, nil
+	case *configOriginCurve:
+		client, err := ethereumClient(d, o.EthereumClient, o.Content)
+		if err != nil {
+			return nil, err
+		}
+		pools := make([]origin.CurvePool, len(o.Pools))
+		for i, p := range o.Pools {
+			pools[i] = origin.CurvePool{
+				Pair:       p.Pair,
+				Address:    p.Address,
+				BaseIndex:  p.BaseIndex,
+				QuoteIndex: p.QuoteIndex,
+			}
+		}
+		origin, err := origin.NewCurve(origin.CurveOptions{
+			Client:      client,
+			Pools:       pools,
+			BlockOffset: o.BlockOffset,
+			Now:         d.Clock,
+		})
+		if err != nil {
+			return nil, c.runtimeError(err)
+		}
+		return origin, nil
 	}
 	return nil, fmt.Errorf("unknown origin %s", c.Origin)
 }
+
+// httpClient returns an HTTP client for the origin. If the http block is
+// defined, requests are sent through an origin.HTTPTransport.
+func (c *configOrigin) httpClient(d Dependencies) (*http.Client, error) {
+	if c.HTTP == nil {
+		return d.HTTPClient, nil
+	}
+	client := &http.Client{}
+	if d.HTTPClient != nil {
+		*client = *d.HTTPClient
+	}
+	transport, err := origin.NewHTTPTransport(origin.HTTPTransportOptions{
+		Transport:  client.Transport,
+		RateLimit:  c.HTTP.RateLimit,
+		Burst:      c.HTTP.Burst,
+		MaxRetries: c.HTTP.MaxRetries,
+		MinBackoff: time.Duration(c.HTTP.MinBackoff) * time.Second,
+		MaxBackoff: time.Duration(c.HTTP.MaxBackoff) * time.Second,
+		CacheTTL:   time.Duration(c.HTTP.CacheTTL) * time.Second,
+		Logger:     d.Logger,
+	})
+	if err != nil {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   fmt.Sprintf("Invalid HTTP configuration: %s", err),
+			Subject:  c.Range.Ptr(),
+		}
+	}
+	client.Transport = transport
+	return client, nil
+}
+
+func (c *configOrigin) runtimeError(err error) error {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Runtime error",
+		Detail:   fmt.Sprintf("Failed to create origin: %s", err),
+		Subject:  c.Range.Ptr(),
+	}
+}
+
+// ethereumClient returns the Ethereum client with the given name or
+// a diagnostic pointing to the ethereum_client attribute if it does not exist.
+func ethereumClient(d Dependencies, name string, content hcl.BodyContent) (rpc.RPC, error) {
+	client, ok := d.Clients[name]
+	if !ok {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   fmt.Sprintf("Ethereum client %q is not configured", name),
+			Subject:  content.Attributes["ethereum_client"].Range.Ptr(),
+		}
+	}
+	return client, nil
+}
+
+func dexPools(pools []configOriginDEXPool) []origin.DEXPool {
+	ps := make([]origin.DEXPool, len(pools))
+	for i, p := range pools {
+		ps[i] = origin.DEXPool{Pair: p.Pair, Address: p.Address}
+	}
+	return ps
+}
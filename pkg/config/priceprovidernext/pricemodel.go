@@ -8,12 +8,14 @@ import (
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
 	utilHCL "github.com/chronicleprotocol/oracle-suite/pkg/util/hcl"
 )
 
 const (
 	defaultFreshnessThreshold = time.Minute
 	defaultExpiryThreshold    = time.Minute * 5
+	defaultTWAPMaxSamples     = 1000
 )
 
 type configPriceModel struct {
@@ -28,6 +30,8 @@ type configPriceModel struct {
 type configDynamicNode interface {
 	buildGraph(roots map[string]graph.Node) ([]graph.Node, error)
 	hclRange() hcl.Range
+	pair() provider.Pair
+	nodes() []configDynamicNode
 }
 
 type configNode struct {
@@ -66,10 +70,59 @@ type configNodeIndirect struct {
 	configNode
 }
 
+type configNodeExpression struct {
+	configNode
+
+	// Expression is an arithmetic expression used to calculate the price,
+	// e.g. "a * b * 1.1".
+	Expression string `hcl:"expression"`
+
+	// Variables is a list of variable names used in the expression. Nested
+	// nodes are assigned to variables in the order in which they are
+	// defined.
+	Variables []string `hcl:"variables,optional"`
+}
+
+type configNodeFallback struct {
+	configNode
+}
+
 type configNodeMedian struct {
 	configNode
 
 	MinSources int `hcl:"min_sources"`
+
+	// OutlierMethod is the method used to reject outliers before calculating
+	// the median. It can be "mad" or "percent". If empty, outliers are not
+	// rejected.
+	OutlierMethod string `hcl:"outlier_method,optional"`
+
+	// OutlierThreshold is the maximum allowed deviation from the median,
+	// in median absolute deviations or in percent, depending on the method.
+	OutlierThreshold float64 `hcl:"outlier_threshold,optional"`
+}
+
+type configNodeVolumeWeighted struct {
+	configNode
+
+	MinSources int `hcl:"min_sources"`
+
+	// FallbackWeight is a weight used for ticks without volume. If zero,
+	// such ticks are ignored.
+	FallbackWeight float64 `hcl:"fallback_weight,optional"`
+
+	method graph.VolumeWeightedMethod
+}
+
+type configNodeTWAP struct {
+	configNode
+
+	// Window is the duration in seconds over which the average is
+	// calculated.
+	Window int `hcl:"window"`
+
+	// MaxSamples is the maximum number of observations kept in the history.
+	MaxSamples int `hcl:"max_samples,optional"`
 }
 
 type DeviationCircuitBreaker struct {
@@ -101,7 +154,12 @@ var nodeSchema = &hcl.BodySchema{
 		{Type: "invert", LabelNames: []string{"pair"}},
 		{Type: "indirect", LabelNames: []string{"pair"}},
 		{Type: "median", LabelNames: []string{"pair"}},
+		{Type: "fallback", LabelNames: []string{"pair"}},
+		{Type: "expression", LabelNames: []string{"pair"}},
 		{Type: "deviation_circuit_breaker", LabelNames: []string{"pair"}},
+		{Type: "volume_weighted_median", LabelNames: []string{"pair"}},
+		{Type: "vwap", LabelNames: []string{"pair"}},
+		{Type: "twap", LabelNames: []string{"pair"}},
 	},
 }
 
@@ -128,8 +186,18 @@ func (c *configNode) PostDecodeBlock(
 			node = &configNodeIndirect{}
 		case "median":
 			node = &configNodeMedian{}
+		case "fallback":
+			node = &configNodeFallback{}
+		case "expression":
+			node = &configNodeExpression{}
 		case "deviation_circuit_breaker":
 			node = &DeviationCircuitBreaker{}
+		case "volume_weighted_median":
+			node = &configNodeVolumeWeighted{method: graph.VolumeWeightedMedian}
+		case "vwap":
+			node = &configNodeVolumeWeighted{method: graph.VolumeWeightedAverage}
+		case "twap":
+			node = &configNodeTWAP{}
 		}
 		if diags := utilHCL.DecodeBlock(ctx, block, node); diags.HasErrors() {
 			return diags
@@ -143,6 +211,14 @@ func (c *configNode) hclRange() hcl.Range {
 	return c.Range
 }
 
+func (c *configNode) pair() provider.Pair {
+	return c.Pair
+}
+
+func (c *configNode) nodes() []configDynamicNode {
+	return c.Nodes
+}
+
 func (c *configNode) buildGraph(roots map[string]graph.Node) ([]graph.Node, error) {
 	nodes := make([]graph.Node, len(c.Nodes))
 	for i, node := range c.Nodes {
@@ -178,23 +254,38 @@ func buildNode(node configDynamicNode, roots map[string]graph.Node) (graph.Node,
 	case *configNodeIndirect:
 		return graph.NewIndirectNode(node.Pair), nil
 	case *configNodeMedian:
-		return graph.NewMedianNode(node.Pair, node.MinSources), nil
+		return buildMedianNode(node)
+	case *configNodeFallback:
+		return graph.NewFallbackNode(node.Pair), nil
+	case *configNodeExpression:
+		return buildExpressionNode(node)
 	case *DeviationCircuitBreaker:
 		return graph.NewDevCircuitBreakerNode(node.Pair, node.Threshold), nil
+	case *configNodeVolumeWeighted:
+		return buildVolumeWeightedNode(node)
+	case *configNodeTWAP:
+		return buildTWAPNode(node)
 	default:
 		return nil, fmt.Errorf("unsupported node type")
 	}
 }
 
-func buildOriginNode(node *configNodeOrigin) (graph.Node, error) {
-	freshnessThreshold := time.Duration(node.FreshnessThreshold)
-	expiryThreshold := time.Duration(node.ExpiryThreshold)
+// thresholds returns the freshness and expiry thresholds of the origin node,
+// using default values if they are not set.
+func (c *configNodeOrigin) thresholds() (freshnessThreshold, expiryThreshold time.Duration) {
+	freshnessThreshold = time.Duration(c.FreshnessThreshold)
+	expiryThreshold = time.Duration(c.ExpiryThreshold)
 	if freshnessThreshold == 0 {
 		freshnessThreshold = defaultFreshnessThreshold
 	}
 	if expiryThreshold == 0 {
 		expiryThreshold = defaultExpiryThreshold
 	}
+	return freshnessThreshold, expiryThreshold
+}
+
+func buildOriginNode(node *configNodeOrigin) (graph.Node, error) {
+	freshnessThreshold, expiryThreshold := node.thresholds()
 	if freshnessThreshold <= 0 {
 		return nil, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -232,6 +323,98 @@ func buildOriginNode(node *configNodeOrigin) (graph.Node, error) {
 	), nil
 }
 
+func buildExpressionNode(node *configNodeExpression) (graph.Node, error) {
+	if len(node.Variables) != len(node.Nodes) {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   fmt.Sprintf("Expected %d nested nodes for variables, got %d", len(node.Variables), len(node.Nodes)),
+			Subject:  node.hclRange().Ptr(),
+		}
+	}
+	expr, err := graph.NewExpressionNode(node.Pair, node.Expression, node.Variables)
+	if err != nil {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   err.Error(),
+			Subject:  node.hclRange().Ptr(),
+		}
+	}
+	return expr, nil
+}
+
+func buildMedianNode(node *configNodeMedian) (graph.Node, error) {
+	var method graph.OutlierRejectionMethod
+	switch node.OutlierMethod {
+	case "":
+		return graph.NewMedianNode(node.Pair, node.MinSources), nil
+	case "mad":
+		method = graph.MADOutlierRejection
+	case "percent":
+		method = graph.PercentOutlierRejection
+	default:
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   fmt.Sprintf("Unknown outlier method: %s", node.OutlierMethod),
+			Subject:  node.hclRange().Ptr(),
+		}
+	}
+	if node.OutlierThreshold <= 0 {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   "Outlier threshold must be greater than zero",
+			Subject:  node.hclRange().Ptr(),
+		}
+	}
+	return graph.NewMedianNodeWithOutlierRejection(node.Pair, node.MinSources, graph.OutlierRejection{
+		Method:    method,
+		Threshold: node.OutlierThreshold,
+	}), nil
+}
+
+func buildVolumeWeightedNode(node *configNodeVolumeWeighted) (graph.Node, error) {
+	if node.FallbackWeight < 0 {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   "Fallback weight must not be negative",
+			Subject:  node.hclRange().Ptr(),
+		}
+	}
+	var fallbackWeight *bn.FloatNumber
+	if node.FallbackWeight > 0 {
+		fallbackWeight = bn.Float(node.FallbackWeight)
+	}
+	return graph.NewVolumeWeightedNode(node.Pair, node.method, node.MinSources, fallbackWeight), nil
+}
+
+func buildTWAPNode(node *configNodeTWAP) (graph.Node, error) {
+	if node.Window <= 0 {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   "Window must be greater than zero",
+			Subject:  node.hclRange().Ptr(),
+		}
+	}
+	if node.MaxSamples < 0 {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   "Max samples must not be negative",
+			Subject:  node.hclRange().Ptr(),
+		}
+	}
+	maxSamples := node.MaxSamples
+	if maxSamples == 0 {
+		maxSamples = defaultTWAPMaxSamples
+	}
+	return graph.NewTWAPNode(node.Pair, time.Duration(node.Window)*time.Second, maxSamples), nil
+}
+
 func buildReferenceNode(node *configNodeReference, roots map[string]graph.Node) (graph.Node, error) {
 	priceModel, ok := roots[node.PriceModel]
 	if !ok {
@@ -1,17 +1,23 @@
 package priceprovider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/api"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/origin"
+	pkgSupervisor "github.com/chronicleprotocol/oracle-suite/pkg/supervisor"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/sliceutil"
 )
 
@@ -19,18 +25,234 @@ type Dependencies struct {
 	HTTPClient *http.Client
 	Clients    ethereum.ClientRegistry
 	Logger     log.Logger
+
+	// Clock, if set, returns the time used by origins and price model nodes
+	// as the current time, e.g. the time at which replayed responses were
+	// recorded.
+	Clock func() time.Time
+}
+
+type AgentDependencies struct {
+	Provider provider.Provider
+	Logger   log.Logger
 }
 
 type Config struct {
+	// AgentListenAddr is the address on which the agent API server will
+	// listen.
+	AgentListenAddr string `hcl:"agent_listen_addr,optional"`
+
+	// AgentAddr is the address of the agent API server. If set, prices are
+	// obtained from the agent instead of local price models.
+	AgentAddr string `hcl:"agent_addr,optional"`
+
 	Origins     []configOrigin     `hcl:"origin,block"`
 	PriceModels []configPriceModel `hcl:"price_model,block"`
 
+	// History enables recording of origin and price model ticks.
+	History *configHistory `hcl:"history,block,optional"`
+
 	// HCL fields:
 	Range   hcl.Range       `hcl:",range"`
 	Content hcl.BodyContent `hcl:",content"`
 }
 
-func (c *Config) PriceProvider(d Dependencies) (provider.Provider, error) {
+// defaultHistorySize is the default number of ticks kept in the history for
+// every origin pair and price model.
+const defaultHistorySize = 1000
+
+type configHistory struct {
+	// Size is the number of ticks kept for every origin pair and price model.
+	Size int `hcl:"size,optional"`
+
+	// Path is the path to a file in which ticks are persisted. If empty,
+	// ticks are kept only in memory.
+	Path string `hcl:"path,optional"`
+
+	// HCL fields:
+	Range hcl.Range `hcl:",range"`
+}
+
+// PriceProvider returns a price provider that fetches ticks from origins
+// on demand.
+//
+// If any of the origins must be started, e.g. streaming origins, or the tick
+// history is enabled, the returned provider implements the
+// supervisor.Service interface.
+//
+// If the agent address is set, the returned provider is a client for the
+// agent API, unless noAgent is true.
+func (c *Config) PriceProvider(d Dependencies, noAgent bool) (provider.Provider, error) {
+	if c.AgentAddr != "" && !noAgent {
+		client, err := api.NewClient(api.ClientConfig{
+			Address:    c.AgentAddr,
+			HTTPClient: d.HTTPClient,
+		})
+		if err != nil {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Runtime error",
+				Detail:   fmt.Sprintf("Failed to create the agent API client: %v", err),
+				Subject:  c.Content.Attributes["agent_addr"].Range.Ptr(),
+			}
+		}
+		return client, nil
+	}
+	priceModels, origins, err := c.buildGraphs(d)
+	if err != nil {
+		return nil, err
+	}
+	history, err := c.tickHistory()
+	if err != nil {
+		return nil, err
+	}
+	updater := graph.NewUpdater(origins, d.Logger)
+	priceProvider := graph.NewProvider(priceModels, updater)
+	if history != nil {
+		updater.SetHistory(history)
+		priceProvider = priceProvider.WithHistory(history)
+	}
+	return withServices(priceProvider, origins, history, d.Logger), nil
+}
+
+// AsyncPriceProvider returns a price provider that reads ticks from the
+// price models without fetching them from origins, and a service that
+// updates the price models in the background.
+//
+// If any of the origins must be started, e.g. streaming origins, or the tick
+// history is enabled, the returned provider implements the
+// supervisor.Service interface.
+func (c *Config) AsyncPriceProvider(d Dependencies) (provider.Provider, *graph.UpdaterService, error) {
+	priceModels, origins, err := c.buildGraphs(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := c.tickHistory()
+	if err != nil {
+		return nil, nil, err
+	}
+	updater := graph.NewUpdater(origins, d.Logger)
+	if history != nil {
+		updater.SetHistory(history)
+	}
+	updaterService, err := graph.NewUpdaterService(graph.UpdaterServiceConfig{
+		Updater: updater,
+		Graphs:  maputil.Values(priceModels),
+		Logger:  d.Logger,
+	})
+	if err != nil {
+		return nil, nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Runtime error",
+			Detail:   fmt.Sprintf("Failed to create the updater service: %v", err),
+			Subject:  c.Range.Ptr(),
+		}
+	}
+	priceProvider := graph.NewProvider(priceModels, nil).WithHealthReporter(updaterService)
+	if history != nil {
+		priceProvider = priceProvider.WithHistory(history)
+	}
+	return withServices(priceProvider, origins, history, d.Logger), updaterService, nil
+}
+
+// Agent returns an API server that exposes the given price provider.
+func (c *Config) Agent(d AgentDependencies) (*api.Server, error) {
+	srv, err := api.NewServer(api.ServerConfig{
+		Provider: d.Provider,
+		Address:  c.AgentListenAddr,
+		Logger:   d.Logger,
+	})
+	if err != nil {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Runtime error",
+			Detail:   fmt.Sprintf("Failed to create the agent API server: %v", err),
+			Subject:  c.Range.Ptr(),
+		}
+	}
+	return srv, nil
+}
+
+// servicesProvider is a price provider that supervises origins that
+// implement the supervisor.Service interface and the tick history.
+type servicesProvider struct {
+	provider.Provider
+	*pkgSupervisor.Supervisor
+}
+
+// TickAt implements the provider.HistoryProvider interface.
+func (p *servicesProvider) TickAt(ctx context.Context, model string, at time.Time) (provider.Tick, error) {
+	h, ok := p.Provider.(provider.HistoryProvider)
+	if !ok {
+		return provider.Tick{}, errors.New("tick history is not supported")
+	}
+	return h.TickAt(ctx, model, at)
+}
+
+// ModelTicks implements the provider.HistoryProvider interface.
+func (p *servicesProvider) ModelTicks(
+	ctx context.Context,
+	model string,
+	from, to time.Time,
+) ([]provider.Tick, error) {
+	h, ok := p.Provider.(provider.HistoryProvider)
+	if !ok {
+		return nil, errors.New("tick history is not supported")
+	}
+	return h.ModelTicks(ctx, model, from, to)
+}
+
+// withServices wraps the given provider if any of the origins implements
+// the supervisor.Service interface or the tick history is enabled, so they
+// are started and stopped together with the provider. Otherwise, the
+// provider is returned unchanged.
+func withServices(
+	p provider.Provider,
+	origins map[string]origin.Origin,
+	history *graph.TickHistory,
+	logger log.Logger,
+) provider.Provider {
+
+	var services []pkgSupervisor.Service
+	for _, o := range origins {
+		if s, ok := o.(pkgSupervisor.Service); ok {
+			services = append(services, s)
+		}
+	}
+	if history != nil {
+		services = append(services, history)
+	}
+	if len(services) == 0 {
+		return p
+	}
+	s := pkgSupervisor.New(logger)
+	s.Watch(services...)
+	return &servicesProvider{Provider: p, Supervisor: s}
+}
+
+// tickHistory returns the tick history configured in the history block, or
+// nil if the block is not present.
+func (c *Config) tickHistory() (*graph.TickHistory, error) {
+	if c.History == nil {
+		return nil, nil
+	}
+	size := c.History.Size
+	if size == 0 {
+		size = defaultHistorySize
+	}
+	history, err := graph.NewTickHistory(size, c.History.Path)
+	if err != nil {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Runtime error",
+			Detail:   fmt.Sprintf("Failed to create the tick history: %v", err),
+			Subject:  c.History.Range.Ptr(),
+		}
+	}
+	return history, nil
+}
+
+func (c *Config) buildGraphs(d Dependencies) (map[string]graph.Node, map[string]origin.Origin, error) {
 	var err error
 
 	// Configure origins.
@@ -38,11 +260,33 @@ func (c *Config) PriceProvider(d Dependencies) (provider.Provider, error) {
 	for _, o := range c.Origins {
 		origins[o.Name], err = o.ConfigureOrigin(d)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	// Configure price models.
+	priceModels, err := c.buildPriceModels()
+	if err != nil {
+		return nil, nil, err
+	}
+	if diags := c.detectCycles(priceModels); diags.HasErrors() {
+		return nil, nil, diags
+	}
+	graph.AttachObservers(maputil.Values(priceModels)...)
+	if d.Clock != nil {
+		graph.Walk(func(n graph.Node) {
+			if c, ok := n.(interface{ SetClock(func() time.Time) }); ok {
+				c.SetClock(d.Clock)
+			}
+		}, maputil.Values(priceModels)...)
+	}
+
+	return priceModels, origins, nil
+}
+
+// buildPriceModels builds graphs for all price models. Graphs are not
+// checked for cycles.
+func (c *Config) buildPriceModels() (map[string]graph.Node, error) {
 	priceModels := map[string]graph.Node{}
 	for _, pm := range c.PriceModels {
 		priceModels[pm.Name] = graph.NewReferenceNode(pm.Pair)
@@ -60,8 +304,17 @@ func (c *Config) PriceProvider(d Dependencies) (provider.Provider, error) {
 				Subject:  pm.Range.Ptr(),
 			}
 		}
+	}
+	return priceModels, nil
+}
+
+// detectCycles returns a diagnostic for every price model that contains
+// a cycle.
+func (c *Config) detectCycles(priceModels map[string]graph.Node) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, pm := range c.PriceModels {
 		if nodes := graph.DetectCycle(priceModels[pm.Name]); len(nodes) > 0 {
-			return nil, &hcl.Diagnostic{
+			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Runtime error",
 				Detail: fmt.Sprintf(
@@ -71,9 +324,8 @@ func (c *Config) PriceProvider(d Dependencies) (provider.Provider, error) {
 					strings.Join(sliceutil.Map(nodes, func(n graph.Node) string { return n.Pair().String() }), " -> "),
 				),
 				Subject: pm.Range.Ptr(),
-			}
+			})
 		}
 	}
-
-	return graph.NewProvider(priceModels, graph.NewUpdater(origins, d.Logger)), nil
+	return diags
 }
@@ -3,9 +3,11 @@ package priceprovider
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
 )
 
 func TestConfig(t *testing.T) {
@@ -18,7 +20,60 @@ func TestConfig(t *testing.T) {
 			name: "valid",
 			path: "config.hcl",
 			test: func(t *testing.T, cfg *Config) {
-				cfg.PriceModels[0].ConfigurePriceModel(nil)
+				node, err := cfg.PriceModels[0].ConfigurePriceModel(nil)
+				require.NoError(t, err)
+				require.Len(t, node.Branches(), 5)
+				assert.IsType(t, &graph.TWAPNode{}, node.Branches()[4])
+				assert.Equal(t, "mad", node.Meta().Meta()["outlier_method"])
+				node, err = cfg.PriceModels[1].ConfigurePriceModel(nil)
+				require.NoError(t, err)
+				assert.Equal(t, "volume_weighted_median", node.Meta().Meta()["type"])
+				require.Len(t, node.Branches(), 3)
+				assert.Equal(t, "vwap", node.Branches()[2].Meta().Meta()["type"])
+				node, err = cfg.PriceModels[2].ConfigurePriceModel(nil)
+				require.NoError(t, err)
+				assert.IsType(t, &graph.FallbackNode{}, node)
+				require.Len(t, node.Branches(), 2)
+				assert.IsType(t, &graph.OriginNode{}, node.Branches()[0])
+				assert.IsType(t, &graph.MedianNode{}, node.Branches()[1])
+				node, err = cfg.PriceModels[3].ConfigurePriceModel(nil)
+				require.NoError(t, err)
+				assert.IsType(t, &graph.ExpressionNode{}, node)
+				assert.Len(t, node.Branches(), 2)
+				require.Len(t, cfg.Origins, 8)
+				require.NotNil(t, cfg.Origins[0].HTTP)
+				assert.Equal(t, float64(5), cfg.Origins[0].HTTP.RateLimit)
+				assert.Equal(t, 2, cfg.Origins[0].HTTP.Burst)
+				assert.Equal(t, 3, cfg.Origins[0].HTTP.MaxRetries)
+				assert.Equal(t, 2, cfg.Origins[0].HTTP.CacheTTL)
+				assert.Nil(t, cfg.Origins[1].HTTP)
+				ws, ok := cfg.Origins[2].OriginConfig.(*configOriginGenericWebSocket)
+				require.True(t, ok)
+				assert.Equal(t, "wss://ws.kraken.com", ws.URL)
+				assert.Equal(t, []string{`{"event":"subscribe","pair":["${ucbase}/${ucquote}"],"subscription":{"name":"ticker"}}`}, ws.Subscriptions)
+				uniswapV2, ok := cfg.Origins[3].OriginConfig.(*configOriginUniswapV2)
+				require.True(t, ok)
+				assert.Equal(t, "default", uniswapV2.EthereumClient)
+				assert.Equal(t, uint64(2), uniswapV2.BlockOffset)
+				require.Len(t, uniswapV2.Pools, 1)
+				assert.Equal(t, "USDC/WETH", uniswapV2.Pools[0].Pair.String())
+				assert.IsType(t, &configOriginUniswapV3{}, cfg.Origins[4].OriginConfig)
+				curve, ok := cfg.Origins[5].OriginConfig.(*configOriginCurve)
+				require.True(t, ok)
+				require.Len(t, curve.Pools, 1)
+				assert.Equal(t, 1, curve.Pools[0].BaseIndex)
+				assert.Equal(t, 0, curve.Pools[0].QuoteIndex)
+				assert.IsType(t, &configOriginBalancerV2{}, cfg.Origins[6].OriginConfig)
+				evm, ok := cfg.Origins[7].OriginConfig.(*configOriginGenericEVM)
+				require.True(t, ok)
+				require.Len(t, evm.Pairs, 1)
+				assert.Equal(t, 1, evm.Pairs[0].PriceIndex)
+				require.NotNil(t, evm.Pairs[0].TimestampIndex)
+				assert.Equal(t, 3, *evm.Pairs[0].TimestampIndex)
+				assert.Nil(t, evm.Pairs[0].Scale)
+				require.NotNil(t, cfg.History)
+				assert.Equal(t, 500, cfg.History.Size)
+				assert.Empty(t, cfg.History.Path)
 			},
 		},
 	}
@@ -2,6 +2,12 @@ origin "coinbase" {
   origin = "generic_jq"
   url    = "https://api.pro.coinbase.com/products/$${ucbase}-$${ucquote}/ticker"
   jq     = "{price: .price, time: .time, volume: .volume}"
+  http {
+    rate_limit  = 5
+    burst       = 2
+    max_retries = 3
+    cache_ttl   = 2
+  }
 }
 
 origin "binance" {
@@ -10,6 +16,65 @@ origin "binance" {
   jq     = ".[] | select(.symbol == ($$ucbase + $$ucquote)) | {price: .lastPrice, volume: .volume, time: (.closeTime / 1000)}"
 }
 
+origin "kraken" {
+  origin        = "generic_websocket"
+  url           = "wss://ws.kraken.com"
+  subscriptions = ["{\"event\":\"subscribe\",\"pair\":[\"$${ucbase}/$${ucquote}\"],\"subscription\":{\"name\":\"ticker\"}}"]
+  jq            = "select(type == \"array\" and .[3] == ($$ucbase + \"/\" + $$ucquote)) | {price: .[1].c[0] | tonumber}"
+}
+
+origin "uniswap_v2" {
+  origin          = "uniswap_v2"
+  ethereum_client = "default"
+  block_offset    = 2
+  pool "USDC/WETH" {
+    address = "0xb4e16d0168e52d35cacd2c6185b44281ec28c9dc"
+  }
+}
+
+origin "uniswap_v3" {
+  origin          = "uniswap_v3"
+  ethereum_client = "default"
+  pool "USDC/WETH" {
+    address = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+  }
+}
+
+origin "curve" {
+  origin          = "curve"
+  ethereum_client = "default"
+  pool "STETH/ETH" {
+    address     = "0xdc24316b9ae028f1497c275eb9192a3ea0f67022"
+    base_index  = 1
+    quote_index = 0
+  }
+}
+
+origin "balancer_v2" {
+  origin          = "balancer_v2"
+  ethereum_client = "default"
+  pool "WETH/GNO" {
+    address = "0xf4c0dd9b82da36c07605df83c8a416f11724d88b"
+  }
+}
+
+origin "chainlink" {
+  origin          = "generic_evm"
+  ethereum_client = "default"
+  pair "ETH/USD" {
+    contract        = "0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419"
+    abi             = "latestRoundData() view returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)"
+    arguments       = []
+    decimals        = 8
+    price_index     = 1
+    timestamp_index = 3
+  }
+}
+
+history {
+  size = 500
+}
+
 price_model "primary" "BTC/USD" {
   median "BTC/USD" {
     origin "coinbase" "BTC/USD" { }
@@ -19,7 +84,48 @@ price_model "primary" "BTC/USD" {
       origin "coinbase" "USDC/USD" { }
     }
     origin "coinbase" "BTC/USD" { }
+    twap "BTC/USD" {
+      window      = 300
+      max_samples = 100
+      origin "kraken" "BTC/USD" { }
+    }
+    min_sources       = 2
+    outlier_method    = "mad"
+    outlier_threshold = 3
+  }
+}
+
+
+price_model "weighted" "ETH/USD" {
+  volume_weighted_median "ETH/USD" {
+    origin "coinbase" "ETH/USD" { }
+    origin "binance" "ETH/USD" { }
+    vwap "ETH/USD" {
+      origin "coinbase" "ETH/USD" { }
+      origin "binance" "ETH/USD" { }
+      min_sources     = 1
+      fallback_weight = 1
+    }
     min_sources = 2
   }
 }
 
+price_model "fallback" "USDC/USD" {
+  fallback "USDC/USD" {
+    origin "binance" "USDC/USD" { }
+    median "USDC/USD" {
+      origin "coinbase" "USDC/USD" { }
+      origin "kraken" "USDC/USD" { }
+      min_sources = 1
+    }
+  }
+}
+
+price_model "expression" "WSTETH/USD" {
+  expression "WSTETH/USD" {
+    expression = "steth_eth * eth_usd * 1.1"
+    variables  = ["steth_eth", "eth_usd"]
+    origin "coinbase" "STETH/ETH" { }
+    origin "coinbase" "ETH/USD" { }
+  }
+}
@@ -0,0 +1,23 @@
+origin "a" {
+  origin = "generic_jq"
+  url    = "https://example.com/"
+  jq     = "{price: .price}"
+}
+
+price_model "btc" "BTC/USD" {
+  indirect "BTC/USD" {
+    origin "a" "BTC/ETH" {}
+    reference "ETH/USD" {
+      price_model = "eth"
+    }
+  }
+}
+
+price_model "eth" "ETH/USD" {
+  indirect "ETH/USD" {
+    origin "a" "ETH/BTC" {}
+    reference "BTC/USD" {
+      price_model = "btc"
+    }
+  }
+}
@@ -0,0 +1,32 @@
+origin "a" {
+  origin = "generic_jq"
+  url    = "https://example.com/"
+  jq     = "{price: .price}"
+}
+
+origin "unused" {
+  origin = "generic_jq"
+  url    = "https://example.com/"
+  jq     = "{price: .price}"
+}
+
+price_model "median" "BTC/USD" {
+  median "BTC/USD" {
+    origin "a" "BTC/USD" {
+      freshness_threshold = 600
+      expiry_threshold    = 300
+    }
+    origin "unknown" "BTC/USD" {}
+    min_sources = 3
+  }
+}
+
+price_model "indirect" "ETH/USD" {
+  indirect "ETH/USD" {
+    origin "a" "ETH/BTC" {}
+    reference "BTC/USD" {
+      price_model = "unknown"
+    }
+    origin "a" "USDC/DAI" {}
+  }
+}
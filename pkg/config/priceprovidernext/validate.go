@@ -0,0 +1,150 @@
+package priceprovider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
+)
+
+// Validate checks the price models without creating origins, so no network
+// access is needed.
+//
+// Unlike PriceProvider, Validate does not stop at the first problem and
+// reports all of them. Problems that do not prevent prices from being
+// calculated, like unused origins, are reported as warnings.
+func (c *Config) Validate() hcl.Diagnostics {
+	v := &validator{
+		origins:     make(map[string]bool),
+		priceModels: make(map[string]*configPriceModel),
+	}
+	for _, o := range c.Origins {
+		v.origins[o.Name] = false
+	}
+	for i, pm := range c.PriceModels {
+		v.priceModels[pm.Name] = &c.PriceModels[i]
+	}
+	for _, pm := range c.PriceModels {
+		v.validateNodes(pm.Nodes)
+	}
+	for _, o := range c.Origins {
+		if !v.origins[o.Name] {
+			v.diags = append(v.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Validation warning",
+				Detail:   fmt.Sprintf("Origin %s is not used by any price model", o.Name),
+				Subject:  o.Range.Ptr(),
+			})
+		}
+	}
+
+	// Build the graphs to detect cycles and problems not covered by the
+	// checks above. If there are already errors, building the graphs would
+	// most likely report one of them again.
+	priceModels, err := c.buildPriceModels()
+	switch {
+	case err == nil:
+		v.diags = append(v.diags, c.detectCycles(priceModels)...)
+	case !v.diags.HasErrors():
+		v.diags = append(v.diags, toDiagnostics(err)...)
+	}
+	return v.diags
+}
+
+type validator struct {
+	origins     map[string]bool // origin name -> used by a price model
+	priceModels map[string]*configPriceModel
+	diags       hcl.Diagnostics
+}
+
+func (v *validator) validateNodes(nodes []configDynamicNode) {
+	for _, node := range nodes {
+		v.validateNode(node)
+		v.validateNodes(node.nodes())
+	}
+}
+
+func (v *validator) validateNode(node configDynamicNode) {
+	switch node := node.(type) {
+	case *configNodeOrigin:
+		if _, ok := v.origins[node.Origin]; !ok {
+			v.error(node, "Unknown origin: %s", node.Origin)
+		} else {
+			v.origins[node.Origin] = true
+		}
+		freshnessThreshold, expiryThreshold := node.thresholds()
+		if freshnessThreshold > expiryThreshold {
+			v.error(
+				node,
+				"Freshness threshold (%s) must be less than expiry threshold (%s)",
+				freshnessThreshold,
+				expiryThreshold,
+			)
+		}
+	case *configNodeReference:
+		pm, ok := v.priceModels[node.PriceModel]
+		if !ok {
+			v.error(node, "Unknown price model: %s", node.PriceModel)
+			return
+		}
+		if !pm.Pair.Equal(node.Pair) {
+			v.error(node, "Price model %s has pair %s, expected %s", pm.Name, pm.Pair, node.Pair)
+		}
+	case *configNodeMedian:
+		v.validateMinSources(node, node.MinSources)
+	case *configNodeVolumeWeighted:
+		v.validateMinSources(node, node.MinSources)
+	case *configNodeIndirect:
+		pairs := make([]provider.Pair, len(node.Nodes))
+		for i, n := range node.Nodes {
+			pairs[i] = n.pair()
+		}
+		pair, err := graph.IndirectPair(pairs)
+		if err != nil {
+			v.error(node, "Pairs of nested nodes do not connect: %s", err)
+			return
+		}
+		if !pair.Equal(node.Pair) {
+			v.error(node, "Nested nodes resolve to pair %s, expected %s", pair, node.Pair)
+		}
+	}
+}
+
+func (v *validator) validateMinSources(node configDynamicNode, minSources int) {
+	if minSources > len(node.nodes()) {
+		v.error(
+			node,
+			"Minimum number of sources (%d) is greater than the number of nested nodes (%d)",
+			minSources,
+			len(node.nodes()),
+		)
+	}
+}
+
+func (v *validator) error(node configDynamicNode, format string, args ...any) {
+	v.diags = append(v.diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Validation error",
+		Detail:   fmt.Sprintf(format, args...),
+		Subject:  node.hclRange().Ptr(),
+	})
+}
+
+// toDiagnostics converts an error returned while building the price models
+// to diagnostics.
+func toDiagnostics(err error) hcl.Diagnostics {
+	switch err := err.(type) {
+	case hcl.Diagnostics:
+		return err
+	case *hcl.Diagnostic:
+		return hcl.Diagnostics{err}
+	default:
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   err.Error(),
+		}}
+	}
+}
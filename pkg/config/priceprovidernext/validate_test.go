@@ -0,0 +1,68 @@
+package priceprovider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		errors   []string
+		warnings []string
+	}{
+		{
+			name: "valid",
+			path: "config.hcl",
+		},
+		{
+			name: "invalid",
+			path: "invalid.hcl",
+			errors: []string{
+				"Minimum number of sources (3) is greater than the number of nested nodes (2)",
+				"Freshness threshold (600ns) must be less than expiry threshold (300ns)",
+				"Unknown origin: unknown",
+				"Pairs of nested nodes do not connect: unable to calculate cross rate for ETH/USD and USDC/DAI",
+				"Unknown price model: unknown",
+			},
+			warnings: []string{
+				"Origin unused is not used by any price model",
+			},
+		},
+		{
+			name: "cycle",
+			path: "cycle.hcl",
+			errors: []string{
+				"Cycle detected in price model BTC/USD btc: BTC/USD -> BTC/USD -> ETH/USD -> ETH/USD",
+				"Cycle detected in price model ETH/USD eth: ETH/USD -> ETH/USD -> BTC/USD -> BTC/USD",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var cfg Config
+			err := config.LoadFiles(&cfg, []string{"./testdata/" + test.path})
+			require.NoError(t, err)
+			var errors, warnings []string
+			for _, diag := range cfg.Validate() {
+				require.NotNil(t, diag.Subject)
+				switch diag.Severity {
+				case hcl.DiagError:
+					errors = append(errors, diag.Detail)
+				case hcl.DiagWarning:
+					warnings = append(warnings, diag.Detail)
+				}
+			}
+			assert.Equal(t, test.errors, errors)
+			if test.warnings != nil {
+				assert.Equal(t, test.warnings, warnings)
+			}
+		})
+	}
+}
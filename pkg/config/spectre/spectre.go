@@ -83,6 +83,7 @@ func (c *Config) Services(baseLogger log.Logger) (*Services, error) {
 		Messages: map[string]pkgTransport.Message{
 			messages.PriceV0MessageName: (*messages.Price)(nil),
 			messages.PriceV1MessageName: (*messages.Price)(nil),
+			messages.PriceV2MessageName: (*messages.PriceBatch)(nil),
 		},
 		Logger: logger,
 	})
@@ -181,6 +181,7 @@ func (c *Config) AgentServices(baseLogger log.Logger) (*AgentServices, error) {
 		Messages: map[string]pkgTransport.Message{
 			messages.PriceV0MessageName: (*messages.Price)(nil),
 			messages.PriceV1MessageName: (*messages.Price)(nil),
+			messages.PriceV2MessageName: (*messages.PriceBatch)(nil),
 		},
 		Logger: logger,
 	})
@@ -226,6 +227,7 @@ func (c *Config) StreamServices(baseLogger log.Logger) (*StreamServices, error)
 		Messages: map[string]pkgTransport.Message{
 			messages.PriceV0MessageName: (*messages.Price)(nil),
 			messages.PriceV1MessageName: (*messages.Price)(nil),
+			messages.PriceV2MessageName: (*messages.PriceBatch)(nil),
 		},
 		Logger: logger,
 	})
@@ -0,0 +1,15 @@
+libp2p {
+  feeds        = ["0x1234567890123456789012345678901234567890"]
+  listen_addrs = ["/ip4/0.0.0.0/tcp/6000"]
+  ethereum_key = "key"
+}
+
+webapi {
+  feeds        = ["0x3456789012345678901234567890123456789012"]
+  listen_addr  = "localhost:8080"
+  ethereum_key = "key"
+
+  static_address_book {
+    addresses = ["https://example.com/api/v1/endpoint"]
+  }
+}
@@ -11,9 +11,25 @@ libp2p {
 
 webapi {
   feeds             = ["0x3456789012345678901234567890123456789012", "0x4567890123456789012345678901234567890123"]
-  listen_addr       = "localhost:8080"
-  socks5_proxy_addr = "localhost:9050"
-  ethereum_key      = "key"
+  listen_addr          = "localhost:8080"
+  socks5_proxy_addr    = "localhost:9050"
+  ethereum_key         = "key"
+  tls_cert_file        = "./tls_cert.pem"
+  tls_key_file         = "./tls_key.pem"
+  tls_client_ca_file   = "./tls_client_ca.pem"
+  tls_client_cert_file = "./tls_client_cert.pem"
+  tls_client_key_file  = "./tls_client_key.pem"
+  tls_root_ca_file     = "./tls_root_ca.pem"
+
+  feeds_file {
+    path            = "/etc/feeds"
+    reload_interval = 30
+  }
+
+  ethereum_feeds {
+    contract_addr   = "0x6789012345678901234567890123456789012345"
+    ethereum_client = "client"
+  }
 
   ethereum_address_book {
     contract_addr   = "0x5678901234567890123456789012345678901234"
@@ -24,3 +40,9 @@ webapi {
     addresses = ["https://example.com/api/v1/endpoint"]
   }
 }
+
+tap {
+  path      = "/var/lib/tap/messages.jsonl"
+  max_size  = 104857600
+  max_files = 3
+}
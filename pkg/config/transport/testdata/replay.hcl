@@ -0,0 +1,5 @@
+replay {
+  path  = "/var/lib/tap/messages.jsonl"
+  speed = 10
+  delay = 1
+}
@@ -5,10 +5,13 @@ import (
 	"context"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/defiweb/go-eth/types"
@@ -21,14 +24,23 @@ import (
 	suite "github.com/chronicleprotocol/oracle-suite"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/allowlist"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/chain"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p/crypto/ethkey"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/recoverer"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/tap"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/webapi"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/timeutil"
 )
 
+const (
+	defaultFeedsFileReloadInterval      = time.Minute
+	defaultEthereumFeedsRefreshInterval = 10 * time.Minute
+	defaultReplayDelay                  = 5 * time.Second
+	defaultChainStatsInterval           = time.Minute
+)
+
 type Dependencies struct {
 	Keys     ethereum.KeyRegistry
 	Clients  ethereum.ClientRegistry
@@ -44,6 +56,14 @@ type Config struct {
 	LibP2P *libP2PConfig `hcl:"libp2p,block,optional"`
 	WebAPI *webAPIConfig `hcl:"webapi,block,optional"`
 
+	// Tap is the configuration for recording messages to a file.
+	Tap *tapConfig `hcl:"tap,block,optional"`
+
+	// Replay is the configuration for replaying messages recorded by the tap.
+	// If set, messages are read from the file instead of the libp2p and
+	// webapi transports.
+	Replay *replayConfig `hcl:"replay,block,optional"`
+
 	// HCL fields:
 	Range   hcl.Range       `hcl:",range"`
 	Content hcl.BodyContent `hcl:",content"`
@@ -55,7 +75,15 @@ type Config struct {
 type libP2PConfig struct {
 	// Feeds is a list of Ethereum addresses that are allowed to send messages
 	// to the node.
-	Feeds []types.Address `hcl:"feeds"`
+	Feeds []types.Address `hcl:"feeds,optional"`
+
+	// FeedsFile is the configuration for the list of feeds read from a file.
+	// The list is merged with the Feeds list.
+	FeedsFile *feedsFileConfig `hcl:"feeds_file,block,optional"`
+
+	// EthereumFeeds is the configuration for the list of feeds read from
+	// an Ethereum contract. The list is merged with the Feeds list.
+	EthereumFeeds *ethereumFeedsConfig `hcl:"ethereum_feeds,block,optional"`
 
 	// ListenAddrs is the list of listening addresses for libp2p node encoded
 	// using the multiaddress format.
@@ -85,6 +113,11 @@ type libP2PConfig struct {
 	// together with `directPeersAddrs`.
 	DisableDiscovery bool `hcl:"disable_discovery,optional"`
 
+	// PriceBatchScoring enables peer scoring for the price/v2 topic. It
+	// should be enabled only after feeders start to broadcast price batches,
+	// otherwise peers are penalized for not delivering price/v2 messages.
+	PriceBatchScoring bool `hcl:"price_batch_scoring,optional"`
+
 	// EthereumKey is the name of the Ethereum key to use for signing messages.
 	// Required if the transport is used for sending messages.
 	EthereumKey string `hcl:"ethereum_key,optional"`
@@ -97,7 +130,15 @@ type libP2PConfig struct {
 type webAPIConfig struct {
 	// Feeds is a list of Ethereum addresses that are allowed to send messages
 	// to the node.
-	Feeds []types.Address `hcl:"feeds"`
+	Feeds []types.Address `hcl:"feeds,optional"`
+
+	// FeedsFile is the configuration for the list of feeds read from a file.
+	// The list is merged with the Feeds list.
+	FeedsFile *feedsFileConfig `hcl:"feeds_file,block,optional"`
+
+	// EthereumFeeds is the configuration for the list of feeds read from
+	// an Ethereum contract. The list is merged with the Feeds list.
+	EthereumFeeds *ethereumFeedsConfig `hcl:"ethereum_feeds,block,optional"`
 
 	// ListenAddr is the address on which the WebAPI server will listen for
 	// incoming connections. The address must be in the format `host:port`.
@@ -109,6 +150,34 @@ type webAPIConfig struct {
 	// must be in the format `host:port`.
 	Socks5ProxyAddr string `hcl:"socks5_proxy_addr,optional"`
 
+	// TLSCertFile is the path to the PEM encoded certificate file used by the
+	// WebAPI server. If set, together with TLSKeyFile, the server accepts
+	// only TLS connections.
+	TLSCertFile string `hcl:"tls_cert_file,optional"`
+
+	// TLSKeyFile is the path to the PEM encoded private key file for the
+	// TLSCertFile certificate.
+	TLSKeyFile string `hcl:"tls_key_file,optional"`
+
+	// TLSClientCAFile is the path to the PEM encoded CA certificate file used
+	// to verify client certificates. If set, the WebAPI server requires
+	// producers to present a valid client certificate. Requires TLSCertFile
+	// and TLSKeyFile.
+	TLSClientCAFile string `hcl:"tls_client_ca_file,optional"`
+
+	// TLSClientCertFile is the path to the PEM encoded client certificate
+	// file that is presented to consumers when sending messages.
+	TLSClientCertFile string `hcl:"tls_client_cert_file,optional"`
+
+	// TLSClientKeyFile is the path to the PEM encoded private key file for
+	// the TLSClientCertFile certificate.
+	TLSClientKeyFile string `hcl:"tls_client_key_file,optional"`
+
+	// TLSRootCAFile is the path to the PEM encoded CA certificate file used
+	// to verify consumers' certificates. If not set, the system CA pool is
+	// used.
+	TLSRootCAFile string `hcl:"tls_root_ca_file,optional"`
+
 	// EthereumKey is the name of the Ethereum key to use for signing messages.
 	// Required if the transport is used for sending messages.
 	EthereumKey string `hcl:"ethereum_key"`
@@ -127,6 +196,39 @@ type webAPIConfig struct {
 	Content hcl.BodyContent `hcl:",content"`
 }
 
+type feedsFileConfig struct {
+	// Path is the path to the file with the list of feeds. The file contains
+	// hex-encoded addresses separated by new lines, spaces or commas. Lines
+	// starting with "#" are ignored.
+	Path string `hcl:"path"`
+
+	// ReloadInterval is the interval in seconds at which the file is read
+	// again. The default is 60 seconds.
+	ReloadInterval uint32 `hcl:"reload_interval,optional"`
+
+	// HCL fields:
+	Range   hcl.Range       `hcl:",range"`
+	Content hcl.BodyContent `hcl:",content"`
+}
+
+type ethereumFeedsConfig struct {
+	// ContractAddr is the Ethereum address of the contract that provides
+	// the list of feeds using the "feeds() returns (address[])" method.
+	ContractAddr types.Address `hcl:"contract_addr"`
+
+	// EthereumClient is the name of the Ethereum client to use for reading
+	// the list of feeds.
+	EthereumClient string `hcl:"ethereum_client"`
+
+	// RefreshInterval is the interval in seconds at which the list of feeds
+	// is read again from the contract. The default is 600 seconds.
+	RefreshInterval uint32 `hcl:"refresh_interval,optional"`
+
+	// HCL fields:
+	Range   hcl.Range       `hcl:",range"`
+	Content hcl.BodyContent `hcl:",content"`
+}
+
 type webAPIEthereumAddressBook struct {
 	// ContractAddr is the Ethereum address of the address book contract.
 	ContractAddr types.Address `hcl:"contract_addr"`
@@ -140,6 +242,41 @@ type webAPIEthereumAddressBook struct {
 	Content hcl.BodyContent `hcl:",content"`
 }
 
+type tapConfig struct {
+	// Path is the path to the file to which all received and broadcast
+	// messages are appended.
+	Path string `hcl:"path"`
+
+	// MaxSize is the maximum size of the file in bytes. When the size is
+	// exceeded, the file is rotated. If zero, the file is never rotated.
+	MaxSize uint64 `hcl:"max_size,optional"`
+
+	// MaxFiles is the number of rotated files to keep. The default is 5.
+	MaxFiles uint32 `hcl:"max_files,optional"`
+
+	// HCL fields:
+	Range   hcl.Range       `hcl:",range"`
+	Content hcl.BodyContent `hcl:",content"`
+}
+
+type replayConfig struct {
+	// Path is the path to the file recorded by the tap.
+	Path string `hcl:"path"`
+
+	// Speed is the replay speed multiplier. For example, 1 replays messages
+	// with the original timing and 10 replays them ten times faster.
+	// The default is 1.
+	Speed float64 `hcl:"speed,optional"`
+
+	// Delay is the time in seconds to wait before the first message is
+	// replayed. The default is 5 seconds.
+	Delay uint32 `hcl:"delay,optional"`
+
+	// HCL fields:
+	Range   hcl.Range       `hcl:",range"`
+	Content hcl.BodyContent `hcl:",content"`
+}
+
 type webAPIStaticAddressBook struct {
 	// Addresses is the list of static addresses to which messages will be
 	// sent.
@@ -154,20 +291,29 @@ func (c *Config) Transport(d Dependencies) (transport.Transport, error) {
 	if c.transport != nil {
 		return c.transport, nil
 	}
-	var transports []transport.Transport
-	switch {
-	case c.LibP2P != nil:
-		t, err := c.configureLibP2P(d)
+	transports := make(map[string]transport.Transport)
+	if c.Replay != nil {
+		// Replayed messages replace messages from other transports.
+		t, err := c.configureReplay(d)
 		if err != nil {
 			return nil, err
 		}
-		transports = append(transports, t)
-	case c.WebAPI != nil:
-		t, err := c.configureWebAPI(d)
-		if err != nil {
-			return nil, err
+		transports["replay"] = t
+	} else {
+		if c.LibP2P != nil {
+			t, err := c.configureLibP2P(d)
+			if err != nil {
+				return nil, err
+			}
+			transports["libp2p"] = t
+		}
+		if c.WebAPI != nil {
+			t, err := c.configureWebAPI(d)
+			if err != nil {
+				return nil, err
+			}
+			transports["webapi"] = t
 		}
-		transports = append(transports, t)
 	}
 	switch {
 	case len(transports) == 0:
@@ -178,9 +324,22 @@ func (c *Config) Transport(d Dependencies) (transport.Transport, error) {
 			Subject:  &c.Range,
 		}
 	case len(transports) == 1:
-		c.transport = transports[0]
+		for _, t := range transports {
+			c.transport = t
+		}
 	default:
-		c.transport = chain.New(transports...)
+		c.transport = chain.NewWithConfig(chain.Config{
+			Transports:    transports,
+			StatsInterval: defaultChainStatsInterval,
+			Logger:        d.Logger,
+		})
+	}
+	if c.Tap != nil {
+		t, err := c.configureTap(d, c.transport)
+		if err != nil {
+			return nil, err
+		}
+		c.transport = t
 	}
 	return c.transport, nil
 }
@@ -222,23 +381,39 @@ func (c *Config) LibP2PBootstrap(d BootstrapDependencies) (transport.Transport,
 }
 
 func (c *Config) configureWebAPI(d Dependencies) (transport.Transport, error) {
+	// Configure TLS:
+	serverTLS, err := c.webAPIServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientTLS, err := c.webAPIClientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure HTTP client:
 	httpClient := http.DefaultClient
-	if len(c.WebAPI.Socks5ProxyAddr) != 0 {
-		dialer, err := proxy.SOCKS5("tcp", c.WebAPI.Socks5ProxyAddr, nil, proxy.Direct)
-		if err != nil {
-			return nil, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Runtime error",
-				Detail:   fmt.Sprintf("Cannot create SOCKS5 proxy: %v", err),
-				Subject:  &c.WebAPI.Content.Attributes["socks5_proxy_addr"].Range,
+	if len(c.WebAPI.Socks5ProxyAddr) != 0 || clientTLS != nil {
+		httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+		if len(c.WebAPI.Socks5ProxyAddr) != 0 {
+			dialer, err := proxy.SOCKS5("tcp", c.WebAPI.Socks5ProxyAddr, nil, proxy.Direct)
+			if err != nil {
+				return nil, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Runtime error",
+					Detail:   fmt.Sprintf("Cannot create SOCKS5 proxy: %v", err),
+					Subject:  &c.WebAPI.Content.Attributes["socks5_proxy_addr"].Range,
+				}
 			}
-		}
-		httpClient.Transport = &http.Transport{
-			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			httpTransport.Proxy = nil
+			httpTransport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
 				return dialer.Dial(network, address)
-			},
+			}
+		}
+		if clientTLS != nil {
+			httpTransport.TLSClientConfig = clientTLS
 		}
+		httpClient = &http.Client{Transport: httpTransport}
 	}
 
 	// Configure address book:
@@ -293,15 +468,24 @@ func (c *Config) configureWebAPI(d Dependencies) (transport.Transport, error) {
 		}
 	}
 
+	// Configure allowlist:
+	feeds, err := configureAllowlist(d, c.WebAPI.Feeds, c.WebAPI.FeedsFile, c.WebAPI.EthereumFeeds)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure transport:
 	webapiTransport, err := webapi.New(webapi.Config{
 		ListenAddr:      c.WebAPI.ListenAddr,
+		TLSConfig:       serverTLS,
 		AddressBook:     addressBook,
 		Topics:          d.Messages,
 		AuthorAllowlist: c.WebAPI.Feeds,
+		Allowlist:       feeds,
 		FlushTicker:     timeutil.NewTicker(time.Minute),
 		Signer:          key,
 		Client:          httpClient,
+		UseHTTPS:        clientTLS != nil,
 		Logger:          d.Logger,
 	})
 	if err != nil {
@@ -315,6 +499,127 @@ func (c *Config) configureWebAPI(d Dependencies) (transport.Transport, error) {
 	return recoverer.New(webapiTransport, d.Logger), nil
 }
 
+// webAPIServerTLSConfig returns the TLS configuration for the WebAPI server
+// or nil if TLS is not enabled.
+func (c *Config) webAPIServerTLSConfig() (*tls.Config, error) {
+	if c.WebAPI.TLSCertFile == "" && c.WebAPI.TLSKeyFile == "" {
+		if c.WebAPI.TLSClientCAFile != "" {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Validation error",
+				Detail:   "The tls_client_ca_file requires tls_cert_file and tls_key_file to be set.",
+				Subject:  c.webAPIAttrRange("tls_client_ca_file"),
+			}
+		}
+		return nil, nil
+	}
+	cert, err := c.loadWebAPIKeyPair(
+		"tls_cert_file", c.WebAPI.TLSCertFile,
+		"tls_key_file", c.WebAPI.TLSKeyFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if c.WebAPI.TLSClientCAFile != "" {
+		pool, err := c.loadWebAPICertPool("tls_client_ca_file", c.WebAPI.TLSClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// webAPIClientTLSConfig returns the TLS configuration for the HTTP client
+// used to send messages or nil if the default configuration should be used.
+func (c *Config) webAPIClientTLSConfig() (*tls.Config, error) {
+	if c.WebAPI.TLSClientCertFile == "" && c.WebAPI.TLSClientKeyFile == "" && c.WebAPI.TLSRootCAFile == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if c.WebAPI.TLSClientCertFile != "" || c.WebAPI.TLSClientKeyFile != "" {
+		cert, err := c.loadWebAPIKeyPair(
+			"tls_client_cert_file", c.WebAPI.TLSClientCertFile,
+			"tls_client_key_file", c.WebAPI.TLSClientKeyFile,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if c.WebAPI.TLSRootCAFile != "" {
+		pool, err := c.loadWebAPICertPool("tls_root_ca_file", c.WebAPI.TLSRootCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+func (c *Config) loadWebAPIKeyPair(certAttr, certFile, keyAttr, keyFile string) (tls.Certificate, error) {
+	if certFile == "" || keyFile == "" {
+		subject := c.webAPIAttrRange(certAttr)
+		if certFile == "" {
+			subject = c.webAPIAttrRange(keyAttr)
+		}
+		return tls.Certificate{}, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   fmt.Sprintf("Both %s and %s must be set.", certAttr, keyAttr),
+			Subject:  subject,
+		}
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Runtime error",
+			Detail:   fmt.Sprintf("Cannot load the TLS certificate: %v", err),
+			Subject:  c.webAPIAttrRange(certAttr),
+		}
+	}
+	return cert, nil
+}
+
+func (c *Config) loadWebAPICertPool(attr, file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Runtime error",
+			Detail:   fmt.Sprintf("Cannot read the CA certificate file: %v", err),
+			Subject:  c.webAPIAttrRange(attr),
+		}
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Validation error",
+			Detail:   "The CA certificate file does not contain any valid PEM encoded certificates.",
+			Subject:  c.webAPIAttrRange(attr),
+		}
+	}
+	return pool, nil
+}
+
+// webAPIAttrRange returns the range of the given attribute of the webapi
+// block or the range of the whole block if the attribute is not set.
+func (c *Config) webAPIAttrRange(name string) *hcl.Range {
+	if attr, ok := c.WebAPI.Content.Attributes[name]; ok {
+		return attr.Range.Ptr()
+	}
+	return c.WebAPI.Range.Ptr()
+}
+
 func (c *Config) configureLibP2P(d Dependencies) (transport.Transport, error) {
 	// Configure signer:
 	key := d.Keys[c.LibP2P.EthereumKey]
@@ -337,22 +642,30 @@ func (c *Config) configureLibP2P(d Dependencies) (transport.Transport, error) {
 		messagePrivKey = ethkey.NewPrivKey(key)
 	}
 
+	// Configure allowlist:
+	feeds, err := configureAllowlist(d, c.LibP2P.Feeds, c.LibP2P.FeedsFile, c.LibP2P.EthereumFeeds)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure LibP2P transport:
 	cfg := libp2p.Config{
-		Mode:             libp2p.ClientMode,
-		PeerPrivKey:      peerPrivKey,
-		Topics:           d.Messages,
-		MessagePrivKey:   messagePrivKey,
-		ListenAddrs:      c.LibP2P.ListenAddrs,
-		BootstrapAddrs:   c.LibP2P.BootstrapAddrs,
-		DirectPeersAddrs: c.LibP2P.DirectPeersAddrs,
-		BlockedAddrs:     c.LibP2P.BlockedAddrs,
-		AuthorAllowlist:  c.LibP2P.Feeds,
-		Discovery:        !c.LibP2P.DisableDiscovery,
-		Signer:           key,
-		Logger:           d.Logger,
-		AppName:          "spire",
-		AppVersion:       suite.Version,
+		Mode:              libp2p.ClientMode,
+		PeerPrivKey:       peerPrivKey,
+		Topics:            d.Messages,
+		MessagePrivKey:    messagePrivKey,
+		ListenAddrs:       c.LibP2P.ListenAddrs,
+		BootstrapAddrs:    c.LibP2P.BootstrapAddrs,
+		DirectPeersAddrs:  c.LibP2P.DirectPeersAddrs,
+		BlockedAddrs:      c.LibP2P.BlockedAddrs,
+		AuthorAllowlist:   c.LibP2P.Feeds,
+		Allowlist:         feeds,
+		PriceBatchScoring: c.LibP2P.PriceBatchScoring,
+		Discovery:         !c.LibP2P.DisableDiscovery,
+		Signer:            key,
+		Logger:            d.Logger,
+		AppName:           "spire",
+		AppVersion:        suite.Version,
 	}
 	libP2PTransport, err := libp2p.New(cfg)
 	if err != nil {
@@ -366,6 +679,111 @@ func (c *Config) configureLibP2P(d Dependencies) (transport.Transport, error) {
 	return recoverer.New(libP2PTransport, d.Logger), nil
 }
 
+func (c *Config) configureTap(d Dependencies, t transport.Transport) (transport.Transport, error) {
+	tapTransport, err := tap.New(t, tap.Config{
+		Path:     c.Tap.Path,
+		MaxSize:  int64(c.Tap.MaxSize),
+		MaxFiles: int(c.Tap.MaxFiles),
+		Logger:   d.Logger,
+	})
+	if err != nil {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Runtime error",
+			Detail:   fmt.Sprintf("Failed to create the tap transport: %v", err),
+			Subject:  &c.Tap.Range,
+		}
+	}
+	return tapTransport, nil
+}
+
+func (c *Config) configureReplay(d Dependencies) (transport.Transport, error) {
+	delay := defaultReplayDelay
+	if c.Replay.Delay > 0 {
+		delay = time.Duration(c.Replay.Delay) * time.Second
+	}
+	replayTransport, err := tap.NewReplay(tap.ReplayConfig{
+		Path:   c.Replay.Path,
+		Topics: d.Messages,
+		Speed:  c.Replay.Speed,
+		Delay:  delay,
+		Logger: d.Logger,
+	})
+	if err != nil {
+		return nil, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Runtime error",
+			Detail:   fmt.Sprintf("Failed to create the replay transport: %v", err),
+			Subject:  &c.Replay.Range,
+		}
+	}
+	return replayTransport, nil
+}
+
+// configureAllowlist returns the allowlist that merges the static list of
+// feeds with the lists read from a file and from an Ethereum contract.
+//
+// The static list is omitted if it is empty, so that the merged list fails
+// when none of the dynamic lists can be fetched. The dynamic lists are
+// fetched once during configuration to fail early if they are unavailable.
+func configureAllowlist(
+	d Dependencies,
+	feeds []types.Address,
+	file *feedsFileConfig,
+	eth *ethereumFeedsConfig,
+) (allowlist.Allowlist, error) {
+
+	var lists []allowlist.Allowlist
+	if len(feeds) > 0 || (file == nil && eth == nil) {
+		lists = append(lists, allowlist.NewStaticAllowlist(feeds))
+	}
+	if file != nil {
+		interval := defaultFeedsFileReloadInterval
+		if file.ReloadInterval > 0 {
+			interval = time.Duration(file.ReloadInterval) * time.Second
+		}
+		list := allowlist.NewFileAllowlist(file.Path, interval, d.Logger)
+		if _, err := list.Feeds(context.Background()); err != nil {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Runtime error",
+				Detail:   fmt.Sprintf("Unable to read the initial feed allowlist from the file: %v", err),
+				Subject:  &file.Range,
+			}
+		}
+		lists = append(lists, list)
+	}
+	if eth != nil {
+		rpcClient := d.Clients[eth.EthereumClient]
+		if rpcClient == nil {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Validation error",
+				Detail:   fmt.Sprintf("Ethereum client %q is not configured", eth.EthereumClient),
+				Subject:  eth.Content.Attributes["ethereum_client"].Range.Ptr(),
+			}
+		}
+		interval := defaultEthereumFeedsRefreshInterval
+		if eth.RefreshInterval > 0 {
+			interval = time.Duration(eth.RefreshInterval) * time.Second
+		}
+		list := allowlist.NewEthereumAllowlist(rpcClient, eth.ContractAddr, interval, d.Logger)
+		if _, err := list.Feeds(context.Background()); err != nil {
+			return nil, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Runtime error",
+				Detail:   fmt.Sprintf("Unable to fetch the initial feed allowlist from the contract: %v", err),
+				Subject:  &eth.Range,
+			}
+		}
+		lists = append(lists, list)
+	}
+	if len(lists) == 1 {
+		return lists[0], nil
+	}
+	return allowlist.NewMultiAllowlist(lists...), nil
+}
+
 func (c *Config) generatePrivKey() (crypto.PrivKey, error) {
 	seedReader := rand.Reader
 	if len(c.LibP2P.PrivKeySeed) != 0 {
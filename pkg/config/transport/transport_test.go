@@ -1,16 +1,32 @@
 package transport
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/defiweb/go-eth/abi"
 	"github.com/defiweb/go-eth/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/config"
 	"github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/chain"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/errutil"
 )
 
 func TestConfig(t *testing.T) {
@@ -43,15 +59,68 @@ func TestConfig(t *testing.T) {
 				assert.Equal(t, "localhost:8080", cfg.WebAPI.ListenAddr)
 				assert.Equal(t, "localhost:9050", cfg.WebAPI.Socks5ProxyAddr)
 				assert.Equal(t, "key", cfg.WebAPI.EthereumKey)
+				assert.Equal(t, "./tls_cert.pem", cfg.WebAPI.TLSCertFile)
+				assert.Equal(t, "./tls_key.pem", cfg.WebAPI.TLSKeyFile)
+				assert.Equal(t, "./tls_client_ca.pem", cfg.WebAPI.TLSClientCAFile)
+				assert.Equal(t, "./tls_client_cert.pem", cfg.WebAPI.TLSClientCertFile)
+				assert.Equal(t, "./tls_client_key.pem", cfg.WebAPI.TLSClientKeyFile)
+				assert.Equal(t, "./tls_root_ca.pem", cfg.WebAPI.TLSRootCAFile)
 				assert.NotNil(t, cfg.WebAPI.EthereumAddressBook)
 				assert.NotNil(t, cfg.WebAPI.StaticAddressBook)
 
+				// FeedsFile
+				assert.Equal(t, "/etc/feeds", cfg.WebAPI.FeedsFile.Path)
+				assert.Equal(t, uint32(30), cfg.WebAPI.FeedsFile.ReloadInterval)
+
+				// EthereumFeeds
+				assert.Equal(t, "0x6789012345678901234567890123456789012345", cfg.WebAPI.EthereumFeeds.ContractAddr.String())
+				assert.Equal(t, "client", cfg.WebAPI.EthereumFeeds.EthereumClient)
+				assert.Zero(t, cfg.WebAPI.EthereumFeeds.RefreshInterval)
+
 				// EthereumAddressBook
 				assert.Equal(t, "0x5678901234567890123456789012345678901234", cfg.WebAPI.EthereumAddressBook.ContractAddr.String())
 				assert.Equal(t, "client", cfg.WebAPI.EthereumAddressBook.EthereumClient)
 
 				// StaticAddressBook
 				assert.Equal(t, []string{"https://example.com/api/v1/endpoint"}, cfg.WebAPI.StaticAddressBook.Addresses)
+
+				// Tap
+				assert.Equal(t, "/var/lib/tap/messages.jsonl", cfg.Tap.Path)
+				assert.Equal(t, uint64(104857600), cfg.Tap.MaxSize)
+				assert.Equal(t, uint32(3), cfg.Tap.MaxFiles)
+				assert.Nil(t, cfg.Replay)
+			},
+		},
+		{
+			name: "replay",
+			path: "replay.hcl",
+			test: func(t *testing.T, cfg *Config) {
+				assert.Nil(t, cfg.LibP2P)
+				assert.Nil(t, cfg.WebAPI)
+				assert.Equal(t, "/var/lib/tap/messages.jsonl", cfg.Replay.Path)
+				assert.Equal(t, float64(10), cfg.Replay.Speed)
+				assert.Equal(t, uint32(1), cfg.Replay.Delay)
+
+				transport, err := cfg.Transport(Dependencies{Logger: null.New()})
+				require.NoError(t, err)
+				assert.NotNil(t, transport)
+			},
+		},
+		{
+			name: "chain",
+			path: "chain.hcl",
+			test: func(t *testing.T, cfg *Config) {
+				key := &mocks.Key{}
+				key.On("Address").Return(types.AddressFromHex("0x1234567890123456789012345678901234567890"))
+				transport, err := cfg.Transport(Dependencies{
+					Keys:   ethereum.KeyRegistry{"key": key},
+					Logger: null.New(),
+				})
+				require.NoError(t, err)
+
+				// Both transports must be used.
+				require.IsType(t, &chain.Chain{}, transport)
+				assert.Len(t, transport.(*chain.Chain).Stats(), 2)
 			},
 		},
 		{
@@ -63,9 +132,14 @@ func TestConfig(t *testing.T) {
 				keyRegistry := ethereum.KeyRegistry{
 					"key": key,
 				}
+				rpc := &mocks.RPC{}
+				rpc.On("Call", mock.Anything, mock.Anything, types.LatestBlockNumber).
+					Return(errutil.Must(abi.EncodeValues(feedsMethod.Outputs(), []types.Address{feed})), nil)
 				clientRegistry := ethereum.ClientRegistry{
-					"client": &mocks.RPC{},
+					"client": rpc,
 				}
+				setTestTLSFiles(cfg, writeTestCerts(t))
+				cfg.WebAPI.FeedsFile.Path = writeTestFeeds(t)
 				transport, err := cfg.Transport(Dependencies{
 					Keys:     keyRegistry,
 					Clients:  clientRegistry,
@@ -76,6 +150,33 @@ func TestConfig(t *testing.T) {
 				assert.NotNil(t, transport)
 			},
 		},
+		{
+			name: "service with unavailable feeds",
+			path: "config.hcl",
+			test: func(t *testing.T, cfg *Config) {
+				key := &mocks.Key{}
+				key.On("Address").Return(types.AddressFromHex("0x1234567890123456789012345678901234567890"))
+				keyRegistry := ethereum.KeyRegistry{
+					"key": key,
+				}
+				rpc := &mocks.RPC{}
+				rpc.On("Call", mock.Anything, mock.Anything, types.LatestBlockNumber).
+					Return([]byte(nil), errors.New("error"))
+				clientRegistry := ethereum.ClientRegistry{
+					"client": rpc,
+				}
+				setTestTLSFiles(cfg, writeTestCerts(t))
+				cfg.WebAPI.FeedsFile.Path = writeTestFeeds(t)
+				_, err := cfg.Transport(Dependencies{
+					Keys:     keyRegistry,
+					Clients:  clientRegistry,
+					Messages: nil,
+					Logger:   null.New(),
+				})
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "Unable to fetch the initial feed allowlist from the contract")
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.path, func(t *testing.T) {
@@ -86,3 +187,153 @@ func TestConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_WebAPITLS(t *testing.T) {
+	files := writeTestCerts(t)
+	tests := []struct {
+		name    string
+		modify  func(*webAPIConfig)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(*webAPIConfig) {},
+		},
+		{
+			name:    "missing key file",
+			modify:  func(c *webAPIConfig) { c.TLSKeyFile = "" },
+			wantErr: "Both tls_cert_file and tls_key_file must be set.",
+		},
+		{
+			name:    "missing client key file",
+			modify:  func(c *webAPIConfig) { c.TLSClientKeyFile = "" },
+			wantErr: "Both tls_client_cert_file and tls_client_key_file must be set.",
+		},
+		{
+			name:    "nonexistent cert file",
+			modify:  func(c *webAPIConfig) { c.TLSCertFile = filepath.Join(t.TempDir(), "missing.pem") },
+			wantErr: "Cannot load the TLS certificate",
+		},
+		{
+			name: "client CA without server cert",
+			modify: func(c *webAPIConfig) {
+				c.TLSCertFile = ""
+				c.TLSKeyFile = ""
+			},
+			wantErr: "The tls_client_ca_file requires tls_cert_file and tls_key_file to be set.",
+		},
+		{
+			name:    "nonexistent root CA file",
+			modify:  func(c *webAPIConfig) { c.TLSRootCAFile = filepath.Join(t.TempDir(), "missing.pem") },
+			wantErr: "Cannot read the CA certificate file",
+		},
+		{
+			name:    "invalid client CA file",
+			modify:  func(c *webAPIConfig) { c.TLSClientCAFile = files.key },
+			wantErr: "The CA certificate file does not contain any valid PEM encoded certificates.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			require.NoError(t, config.LoadFiles(&cfg, []string{"./testdata/config.hcl"}))
+			setTestTLSFiles(&cfg, files)
+			tt.modify(cfg.WebAPI)
+
+			serverTLS, serverErr := cfg.webAPIServerTLSConfig()
+			clientTLS, clientErr := cfg.webAPIClientTLSConfig()
+			if tt.wantErr != "" {
+				err := errors.Join(serverErr, clientErr)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, serverErr)
+			require.NoError(t, clientErr)
+
+			// Server:
+			assert.Len(t, serverTLS.Certificates, 1)
+			assert.Equal(t, tls.RequireAndVerifyClientCert, serverTLS.ClientAuth)
+			assert.NotNil(t, serverTLS.ClientCAs)
+
+			// Client:
+			assert.Len(t, clientTLS.Certificates, 1)
+			assert.NotNil(t, clientTLS.RootCAs)
+		})
+	}
+}
+
+var (
+	feed        = types.MustAddressFromHex("0x5678901234567890123456789012345678901234")
+	feedsMethod = abi.MustParseMethod("function feeds() returns (address[])")
+)
+
+// writeTestFeeds writes a list of feeds to a temporary file.
+func writeTestFeeds(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "feeds")
+	require.NoError(t, os.WriteFile(path, []byte(feed.String()), 0600))
+	return path
+}
+
+type testCertFiles struct {
+	ca   string
+	cert string
+	key  string
+}
+
+func setTestTLSFiles(cfg *Config, files testCertFiles) {
+	cfg.WebAPI.TLSCertFile = files.cert
+	cfg.WebAPI.TLSKeyFile = files.key
+	cfg.WebAPI.TLSClientCAFile = files.ca
+	cfg.WebAPI.TLSClientCertFile = files.cert
+	cfg.WebAPI.TLSClientKeyFile = files.key
+	cfg.WebAPI.TLSRootCAFile = files.ca
+}
+
+// writeTestCerts writes a CA certificate and a certificate signed by the CA
+// to a temporary directory.
+func writeTestCerts(t *testing.T) testCertFiles {
+	dir := t.TempDir()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTpl, caTpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tpl, caTpl, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	files := testCertFiles{
+		ca:   filepath.Join(dir, "ca.pem"),
+		cert: filepath.Join(dir, "cert.pem"),
+		key:  filepath.Join(dir, "key.pem"),
+	}
+	writePEM(t, files.ca, "CERTIFICATE", caDER)
+	writePEM(t, files.cert, "CERTIFICATE", certDER)
+	writePEM(t, files.key, "EC PRIVATE KEY", keyDER)
+	return files
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600))
+}
@@ -50,6 +50,10 @@ type HTTPServer struct {
 }
 
 // New creates a new HTTPServer instance.
+//
+// If the TLSConfig field of the given server is set, the server accepts only
+// TLS connections. The server certificate must be provided in the
+// Certificates or GetCertificate fields of the TLSConfig.
 func New(srv *http.Server) *HTTPServer {
 	s := &HTTPServer{
 		serveCh: make(chan error),
@@ -115,6 +119,10 @@ func (s *HTTPServer) Addr() net.Addr {
 }
 
 func (s *HTTPServer) serve() {
+	if s.srv.TLSConfig != nil {
+		s.serveCh <- s.srv.ServeTLS(s.ln, "", "")
+		return
+	}
 	s.serveCh <- s.srv.Serve(s.ln)
 }
 
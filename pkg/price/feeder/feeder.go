@@ -17,6 +17,7 @@ package feeder
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/defiweb/go-eth/wallet"
@@ -25,6 +26,7 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/median"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/marshal"
+	nextProvider "github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/timeutil"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
@@ -42,10 +44,13 @@ type Feeder struct {
 	waitCh chan error
 
 	priceProvider provider.Provider
+	tickProvider  nextProvider.Provider
 	signer        wallet.Key
 	transport     transport.Transport
 	interval      *timeutil.Ticker
 	pairs         []provider.Pair
+	priceBatch    bool
+	batchOnly     bool
 	log           log.Logger
 }
 
@@ -57,6 +62,11 @@ type Config struct {
 	// PriceProvider is a price provider which is used to fetch prices.
 	PriceProvider provider.Provider
 
+	// TickProvider is a next-gen price provider which is used to fetch
+	// prices. If set, it is used instead of PriceProvider. Prices are
+	// fetched from price models named after pairs, e.g. "BTC/USD".
+	TickProvider nextProvider.Provider
+
 	// Signer is a wallet used to sign prices.
 	Signer wallet.Key
 
@@ -67,13 +77,24 @@ type Config struct {
 	// Interval describes how often we should send prices to the network.
 	Interval *timeutil.Ticker
 
+	// PriceBatch enables broadcasting of the price/v2 message, which contains
+	// prices for all pairs in a single message. Until all consumers support
+	// the price/v2 message, prices are still broadcast as price/v0 and
+	// price/v1 messages as well, unless PriceBatchOnly is set.
+	PriceBatch bool
+
+	// PriceBatchOnly disables broadcasting of price/v0 and price/v1 messages,
+	// so prices are broadcast only in the price/v2 message. Implies
+	// PriceBatch.
+	PriceBatchOnly bool
+
 	// Logger is a current logger interface used by the Feeder.
 	Logger log.Logger
 }
 
 // New creates a new instance of the Feeder.
 func New(cfg Config) (*Feeder, error) {
-	if cfg.PriceProvider == nil {
+	if cfg.PriceProvider == nil && cfg.TickProvider == nil {
 		return nil, errors.New("price provider must not be nil")
 	}
 	if cfg.Signer == nil {
@@ -92,10 +113,13 @@ func New(cfg Config) (*Feeder, error) {
 	g := &Feeder{
 		waitCh:        make(chan error),
 		priceProvider: cfg.PriceProvider,
+		tickProvider:  cfg.TickProvider,
 		signer:        cfg.Signer,
 		transport:     cfg.Transport,
 		interval:      cfg.Interval,
 		pairs:         pairs,
+		priceBatch:    cfg.PriceBatch || cfg.PriceBatchOnly,
+		batchOnly:     cfg.PriceBatchOnly,
 		log:           cfg.Logger.WithField("tag", LoggerTag),
 	}
 	return g, nil
@@ -124,38 +148,83 @@ func (g *Feeder) Wait() <-chan error {
 
 // broadcast sends price for single pair to the network. This method uses
 // current price from the Provider, so it must be updated beforehand.
-func (g *Feeder) broadcast(pair provider.Pair) error {
-	var err error
+// It returns the signed price message. If only the price/v2 message is
+// enabled, the price is not broadcast and must be sent in a batch.
+func (g *Feeder) broadcast(pair provider.Pair) (*messages.Price, error) {
+	var (
+		msg *messages.Price
+		err error
+	)
+	if g.tickProvider != nil {
+		msg, err = g.tickPriceMessage(pair)
+	} else {
+		msg, err = g.priceMessage(pair)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if g.batchOnly {
+		return msg, nil
+	}
+	if err := g.broadcastMessage(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
 
+// priceMessage creates a signed price message for single pair using the
+// price from the PriceProvider.
+func (g *Feeder) priceMessage(pair provider.Pair) (*messages.Price, error) {
 	// Create price.
 	tick, err := g.priceProvider.Price(pair)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if tick.Error != "" {
-		return errors.New(tick.Error)
+		return nil, errors.New(tick.Error)
 	}
 	price := &median.Price{Wat: pair.Base + pair.Quote, Age: tick.Time}
 	price.SetFloat64Price(tick.Price)
 
 	// Sign price.
-	err = price.Sign(g.signer)
-	if err != nil {
-		return err
+	if err := price.Sign(g.signer); err != nil {
+		return nil, err
 	}
+	return toPriceMessage(price, tick)
+}
 
-	// Broadcast price to P2P network.
-	msg, err := toPriceMessage(price, tick)
+// tickPriceMessage creates a signed price message for single pair using the
+// tick from the TickProvider.
+func (g *Feeder) tickPriceMessage(pair provider.Pair) (*messages.Price, error) {
+	// Create price.
+	tick, err := g.tickProvider.Tick(g.ctx, pair.String())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := g.transport.Broadcast(messages.PriceV0MessageName, msg.AsV0()); err != nil {
-		return err
+	if err := tick.Validate(); err != nil {
+		return nil, err
+	}
+	price := &median.Price{Wat: pair.Base + pair.Quote, Age: tick.Time}
+	price.SetBigFloatPrice(tick.Price.BigFloat())
+
+	// Sign price.
+	if err := price.Sign(g.signer); err != nil {
+		return nil, err
 	}
-	if err := g.transport.Broadcast(messages.PriceV1MessageName, msg.AsV1()); err != nil {
+	return tickToPriceMessage(price, tick)
+}
+
+func (g *Feeder) broadcastMessage(msg *messages.Price) error {
+	if err := g.transport.Broadcast(messages.PriceV0MessageName, msg.AsV0()); err != nil {
 		return err
 	}
-	return err
+	return g.transport.Broadcast(messages.PriceV1MessageName, msg.AsV1())
+}
+
+// broadcastBatch sends prices for all pairs to the network in a single
+// price/v2 message. Prices are already signed, the batch itself is not.
+func (g *Feeder) broadcastBatch(msgs []*messages.Price) error {
+	return g.transport.Broadcast(messages.PriceV2MessageName, &messages.PriceBatch{Prices: msgs})
 }
 
 func (g *Feeder) broadcasterRoutine() {
@@ -165,17 +234,33 @@ func (g *Feeder) broadcasterRoutine() {
 			return
 		case <-g.interval.TickCh():
 			// Send prices to the network.
+			var msgs []*messages.Price
 			for _, pair := range g.pairs {
-				if err := g.broadcast(pair); err != nil {
+				msg, err := g.broadcast(pair)
+				if err != nil {
 					g.log.
 						WithField("assetPair", pair).
 						WithError(err).
 						Warn("Unable to broadcast price")
 					continue
 				}
+				msgs = append(msgs, msg)
+				if !g.batchOnly {
+					g.log.
+						WithField("assetPair", pair).
+						Info("Price broadcast")
+				}
+			}
+			if g.priceBatch && len(msgs) > 0 {
+				if err := g.broadcastBatch(msgs); err != nil {
+					g.log.
+						WithError(err).
+						Warn("Unable to broadcast price batch")
+					continue
+				}
 				g.log.
-					WithField("assetPair", pair).
-					Info("Price broadcast")
+					WithField("prices", len(msgs)).
+					Info("Price batch broadcast")
 			}
 		}
 	}
@@ -197,3 +282,14 @@ func toPriceMessage(price *median.Price, provider *provider.Price) (*messages.Pr
 		Trace: trace,
 	}, nil
 }
+
+func tickToPriceMessage(price *median.Price, tick nextProvider.Tick) (*messages.Price, error) {
+	trace, err := json.Marshal(tick)
+	if err != nil {
+		return nil, err
+	}
+	return &messages.Price{
+		Price: price,
+		Trace: trace,
+	}, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
@@ -34,9 +35,12 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/median"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 	priceMocks "github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+	nextProvider "github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/timeutil"
 )
 
@@ -225,6 +229,169 @@ func TestFeeder_Broadcast(t *testing.T) {
 	}
 }
 
+func TestFeeder_BroadcastTick(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer ctxCancel()
+
+	// Prepare the next-gen price provider with a single price model.
+	now := time.Now()
+	pair := nextProvider.Pair{Base: "AAA", Quote: "BBB"}
+	node := graph.NewOriginNode("origin", pair, pair, time.Minute, time.Minute)
+	require.NoError(t, node.SetTick(nextProvider.Tick{
+		Pair:  pair,
+		Price: bn.Float("110.5"),
+		Time:  now,
+	}))
+	tickProvider := graph.NewProvider(map[string]graph.Node{"AAA/BBB": node}, nil)
+
+	signer := &ethereumMocks.Key{}
+	signer.On("SignMessage", mock.Anything).Return(types.MustSignatureFromBytesPtr(bytes.Repeat([]byte{0xAA}, 65)), nil)
+
+	ticker := timeutil.NewTicker(0)
+	localTransport := local.New([]byte("test"), 0, map[string]transport.Message{
+		messages.PriceV0MessageName: (*messages.Price)(nil),
+		messages.PriceV1MessageName: (*messages.Price)(nil),
+	})
+
+	// Start feeder.
+	feeder, err := New(Config{
+		Pairs:        []string{"AAA/BBB"},
+		TickProvider: tickProvider,
+		Signer:       signer,
+		Transport:    localTransport,
+		Interval:     ticker,
+	})
+	require.NoError(t, err)
+	require.NoError(t, localTransport.Start(ctx))
+	require.NoError(t, feeder.Start(ctx))
+	defer func() {
+		ctxCancel()
+		<-feeder.Wait()
+		<-localTransport.Wait()
+	}()
+
+	// Wait for service to start.
+	time.Sleep(time.Millisecond * 100)
+
+	ticker.Tick()
+
+	msg := <-localTransport.Messages(messages.PriceV1MessageName)
+	price := msg.Message.(*messages.Price)
+	assert.Equal(t, "AAABBB", price.Price.Wat)
+	assert.Equal(t, now.Unix(), price.Price.Age.Unix())
+	assert.Equal(t, "110500000000000000000", price.Price.Val.String())
+	assert.Contains(t, string(price.Trace), `"base":"AAA"`)
+}
+
+func TestFeeder_BroadcastBatch(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer ctxCancel()
+
+	priceProvider := &priceMocks.Provider{}
+	priceProvider.On("Price", provider.Pair{Base: "AAA", Quote: "BBB"}).Return(PriceAAABBB, nil)
+	priceProvider.On("Price", provider.Pair{Base: "XXX", Quote: "YYY"}).Return(PriceXXXYYY, nil)
+
+	signer := &ethereumMocks.Key{}
+	signer.On("SignMessage", mock.Anything).Return(types.MustSignatureFromBytesPtr(bytes.Repeat([]byte{0xAA}, 65)), nil)
+
+	ticker := timeutil.NewTicker(0)
+	localTransport := local.New([]byte("test"), 0, map[string]transport.Message{
+		messages.PriceV0MessageName: (*messages.Price)(nil),
+		messages.PriceV1MessageName: (*messages.Price)(nil),
+		messages.PriceV2MessageName: (*messages.PriceBatch)(nil),
+	})
+
+	// Start feeder.
+	feeder, err := New(Config{
+		Pairs:         []string{"AAA/BBB", "XXX/YYY"},
+		PriceProvider: priceProvider,
+		Signer:        signer,
+		Transport:     localTransport,
+		Interval:      ticker,
+		PriceBatch:    true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, localTransport.Start(ctx))
+	require.NoError(t, feeder.Start(ctx))
+	defer func() {
+		ctxCancel()
+		<-feeder.Wait()
+		<-localTransport.Wait()
+	}()
+
+	// Wait for service to start.
+	time.Sleep(time.Millisecond * 100)
+
+	v2ch := localTransport.Messages(messages.PriceV2MessageName)
+	ticker.Tick()
+
+	msg := <-v2ch
+	batch := msg.Message.(*messages.PriceBatch)
+	require.Len(t, batch.Prices, 2)
+	assert.Equal(t, "AAABBB", batch.Prices[0].Price.Wat)
+	assert.Equal(t, "XXXYYY", batch.Prices[1].Price.Wat)
+	assert.Equal(t, big.NewInt(0xAA), batch.Prices[0].Price.Sig.V)
+	assert.Equal(t, big.NewInt(0xAA), batch.Prices[1].Price.Sig.V)
+}
+
+func TestFeeder_BroadcastBatchOnly(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer ctxCancel()
+
+	priceProvider := &priceMocks.Provider{}
+	priceProvider.On("Price", provider.Pair{Base: "AAA", Quote: "BBB"}).Return(PriceAAABBB, nil)
+	priceProvider.On("Price", provider.Pair{Base: "XXX", Quote: "YYY"}).Return(PriceXXXYYY, nil)
+
+	signer := &ethereumMocks.Key{}
+	signer.On("SignMessage", mock.Anything).Return(types.MustSignatureFromBytesPtr(bytes.Repeat([]byte{0xAA}, 65)), nil)
+
+	ticker := timeutil.NewTicker(0)
+	localTransport := local.New([]byte("test"), 0, map[string]transport.Message{
+		messages.PriceV0MessageName: (*messages.Price)(nil),
+		messages.PriceV1MessageName: (*messages.Price)(nil),
+		messages.PriceV2MessageName: (*messages.PriceBatch)(nil),
+	})
+
+	// Start feeder.
+	feeder, err := New(Config{
+		Pairs:          []string{"AAA/BBB", "XXX/YYY"},
+		PriceProvider:  priceProvider,
+		Signer:         signer,
+		Transport:      localTransport,
+		Interval:       ticker,
+		PriceBatchOnly: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, localTransport.Start(ctx))
+	require.NoError(t, feeder.Start(ctx))
+	defer func() {
+		ctxCancel()
+		<-feeder.Wait()
+		<-localTransport.Wait()
+	}()
+
+	// Wait for service to start.
+	time.Sleep(time.Millisecond * 100)
+
+	v1ch := localTransport.Messages(messages.PriceV1MessageName)
+	v2ch := localTransport.Messages(messages.PriceV2MessageName)
+	ticker.Tick()
+
+	// Prices in the batch must be signed individually.
+	msg := <-v2ch
+	batch := msg.Message.(*messages.PriceBatch)
+	require.Len(t, batch.Prices, 2)
+	assertPrice(t, PriceAAABBB, batch.Prices[0])
+	assertPrice(t, PriceXXXYYY, batch.Prices[1])
+
+	// The price/v1 messages must not be broadcast.
+	select {
+	case <-v1ch:
+		assert.Fail(t, "unexpected price/v1 message")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func TestFeeder_InvalidConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -65,6 +65,14 @@ func (p *Price) SetFloat64Price(price float64) {
 	p.Val = pi
 }
 
+// SetBigFloatPrice sets the price from a big.Float without losing precision
+// due to float64 conversion.
+func (p *Price) SetBigFloatPrice(price *big.Float) {
+	pf := new(big.Float).SetPrec(256).Mul(price, new(big.Float).SetFloat64(PriceMultiplier))
+	pi, _ := pf.Int(nil)
+	p.Val = pi
+}
+
 func (p *Price) Float64Price() float64 {
 	x := new(big.Float).SetInt(p.Val)
 	x = new(big.Float).Quo(x, new(big.Float).SetFloat64(PriceMultiplier))
@@ -73,7 +81,7 @@ func (p *Price) Float64Price() float64 {
 }
 
 func (p *Price) From(r crypto.Recoverer) (*types.Address, error) {
-	from, err := r.RecoverMessage(p.hash().Bytes(), p.Sig)
+	from, err := r.RecoverMessage(p.Hash().Bytes(), p.Sig)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +92,7 @@ func (p *Price) Sign(signer wallet.Key) error {
 	if p.Val == nil {
 		return ErrPriceNotSet
 	}
-	signature, err := signer.SignMessage(p.hash().Bytes())
+	signature, err := signer.SignMessage(p.Hash().Bytes())
 	if err != nil {
 		return err
 	}
@@ -102,7 +110,7 @@ func (p *Price) Fields(r crypto.Recoverer) log.Fields {
 		"wat":  p.Wat,
 		"age":  p.Age.UTC().Format(time.RFC3339),
 		"val":  p.Val.String(),
-		"hash": hex.EncodeToString(p.hash().Bytes()),
+		"hash": hex.EncodeToString(p.Hash().Bytes()),
 		"V":    hex.EncodeToString(p.Sig.V.Bytes()),
 		"R":    hex.EncodeToString(p.Sig.R.Bytes()),
 		"S":    hex.EncodeToString(p.Sig.S.Bytes()),
@@ -165,8 +173,8 @@ func (p *Price) UnmarshalJSON(bytes []byte) error {
 	return nil
 }
 
-// hash is an equivalent of keccak256(abi.encodePacked(val_, age_, wat))) in Solidity.
-func (p *Price) hash() types.Hash {
+// Hash is an equivalent of keccak256(abi.encodePacked(val_, age_, wat))) in Solidity.
+func (p *Price) Hash() types.Hash {
 	// Median:
 	median := make([]byte, 32)
 	p.Val.FillBytes(median)
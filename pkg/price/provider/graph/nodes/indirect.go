@@ -200,6 +200,21 @@ func (n *IndirectAggregatorNode) Price() AggregatorPrice {
 	}
 }
 
+// IndirectPair returns the pair to which the cross rate is resolved for
+// children with the given pairs. It returns an error if adjacent pairs do not
+// have a common part.
+func IndirectPair(pairs []provider.Pair) (provider.Pair, error) {
+	prices := make([]PairPrice, len(pairs))
+	for i, pair := range pairs {
+		prices[i] = PairPrice{Pair: pair, Price: 1, Bid: 1, Ask: 1}
+	}
+	price, err := crossRate(prices)
+	if err != nil {
+		return provider.Pair{}, err
+	}
+	return price.Pair, nil
+}
+
 // crossRate returns a calculated price from the list of prices. Prices order
 // is important because prices are calculated from first to last.
 //
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
 )
@@ -798,3 +799,18 @@ func Test_crossRate(t *testing.T) {
 		})
 	}
 }
+
+func TestIndirectPair(t *testing.T) {
+	pair, err := IndirectPair([]provider.Pair{
+		{Base: "A", Quote: "B"},
+		{Base: "B", Quote: "C"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, provider.Pair{Base: "A", Quote: "C"}, pair)
+
+	_, err = IndirectPair([]provider.Pair{
+		{Base: "A", Quote: "B"},
+		{Base: "X", Quote: "Y"},
+	})
+	assert.True(t, errors.As(err, &ErrNoCommonPart{}))
+}
@@ -18,26 +18,66 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math"
+	"time"
 
 	ethereumConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/price/provider/hooks"
 )
 
-type PostPriceHook struct {
-	ctx      context.Context
-	clients  ethereumConfig.ClientRegistry
-	handlers map[string]any
-}
-
 const RocketPoolPairETH = "RETH/ETH"
 const RocketPoolPairUSD = "RETH/USD"
 
-type HookParams map[string]map[string]any
+// Types of price checks available by default.
+const (
+	CircuitBreakerCheck = "circuit_breaker"
+	MaxDeviationCheck   = "max_deviation"
+	MaxAgeCheck         = "max_age"
+	MinSourcesCheck     = "min_sources"
+)
+
+// PriceCheck verifies a price calculated for a pair. If the check fails, the
+// returned error is used to mark the price as invalid.
+type PriceCheck interface {
+	// Check checks the price. The prices map contains all prices checked
+	// together with the price.
+	Check(ctx context.Context, price *Price, prices map[Pair]*Price) error
+}
+
+// PriceCheckFactory creates a price check for the given pair using parameters
+// from the hook configuration.
+type PriceCheckFactory func(clients ethereumConfig.ClientRegistry, pair Pair, params map[string]any) (PriceCheck, error)
+
+var priceChecks = map[string]PriceCheckFactory{
+	CircuitBreakerCheck: newCircuitBreakerCheck,
+	MaxDeviationCheck:   newMaxDeviationCheck,
+	MaxAgeCheck:         newMaxAgeCheck,
+	MinSourcesCheck:     newMinSourcesCheck,
+}
+
+// RegisterPriceCheck registers a price check type, so it can be used in the
+// hook configuration. It replaces a previously registered check of the same
+// type.
+//
+// This function is not safe for concurrent use. It should be called during
+// initialization, before any hooks are created.
+func RegisterPriceCheck(typ string, factory PriceCheckFactory) {
+	priceChecks[typ] = factory
+}
+
+// HookParams maps pairs to parameters of checks performed for them. The type
+// of a check is defined by the "type" parameter.
+type HookParams map[string][]map[string]any
 
 func NewHookParams() HookParams {
 	return make(HookParams)
 }
 
+type PostPriceHook struct {
+	ctx    context.Context
+	checks map[Pair][]PriceCheck
+}
+
 func NewPostPriceHook(
 	ctx context.Context,
 	clients ethereumConfig.ClientRegistry,
@@ -47,24 +87,54 @@ func NewPostPriceHook(
 	error,
 ) {
 
-	handlers := make(map[string]any)
+	var (
+		checks       = make(map[Pair][]PriceCheck)
+		rocketParams map[string]any
+	)
+	add := func(pair Pair, typ string, p map[string]any) error {
+		factory, ok := priceChecks[typ]
+		if !ok {
+			return fmt.Errorf("unknown check type %q for %s hook", typ, pair)
+		}
+		check, err := factory(clients, pair, p)
+		if err != nil {
+			return fmt.Errorf("unable to create %s check for %s hook: %w", typ, pair, err)
+		}
+		checks[pair] = append(checks[pair], check)
+		return nil
+	}
 	for k, v := range params {
-		switch k {
-		case RocketPoolPairUSD:
-			fallthrough
-		case RocketPoolPairETH:
-			h, err := hooks.NewRocketPoolCircuitBreaker(clients, v)
-			if err != nil {
+		pair, err := NewPair(k)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range v {
+			typ, ok := p["type"].(string)
+			if !ok {
+				// Hooks for RocketPool pairs were defined before check types
+				// were introduced. Such hooks are used for both pairs.
+				if k != RocketPoolPairETH && k != RocketPoolPairUSD {
+					return nil, fmt.Errorf("type parameter not found for %s hook", k)
+				}
+				rocketParams = p
+				continue
+			}
+			if err := add(pair, typ, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if rocketParams != nil {
+		for _, k := range []string{RocketPoolPairETH, RocketPoolPairUSD} {
+			pair, _ := NewPair(k)
+			if err := add(pair, CircuitBreakerCheck, rocketParams); err != nil {
 				return nil, err
 			}
-			handlers[RocketPoolPairETH] = h
-		default:
 		}
 	}
 	return &PostPriceHook{
-		ctx:      ctx,
-		clients:  clients,
-		handlers: handlers,
+		ctx:    ctx,
+		checks: checks,
 	}, nil
 }
 
@@ -82,56 +152,213 @@ func findPrice(a []*Price, selector func(*Price) bool) *Price {
 	return nil
 }
 
+// Check performs checks defined for the given prices. Prices that fail any
+// of the checks are marked as invalid by setting the Error field.
 func (o *PostPriceHook) Check(prices map[Pair]*Price) error {
 	for pair, price := range prices {
-		switch pair.String() {
-		case RocketPoolPairUSD:
-			fallthrough
-		case RocketPoolPairETH:
-			if _, ok := o.handlers[RocketPoolPairETH]; !ok {
-				return fmt.Errorf("no post price hook handler found for %s", pair.String())
+		for _, check := range o.checks[pair] {
+			if price.Error != "" {
+				break
 			}
-			checkPrice := price.Price
-			refPrice := findPrice(price.Prices,
-				func(p *Price) bool {
-					return p.Parameters["origin"] == "rocketpool"
-				},
-			)
-			if refPrice == nil {
-				prices[pair].Error = fmt.Sprintf("post price hook failed for %s, reference price not found", pair.String())
-				return nil
-			}
-			if refPrice.Price == 0 {
-				prices[pair].Error = fmt.Sprintf("post price hook failed for %s, reference price should be > 0", pair.String())
-				return nil
+			if err := check.Check(o.ctx, price, prices); err != nil {
+				price.Error = err.Error()
 			}
-			if pair.String() == RocketPoolPairUSD {
-				p := findPrice(price.Prices,
-					func(p *Price) bool {
-						return p.Type == "aggregator" && p.Pair.String() == RocketPoolPairETH
-					},
-				)
-				if p == nil {
-					prices[pair].Error = fmt.Sprintf(
-						"post price hook failed for %s, unable to find aggregate %s price",
-						pair.String(),
-						RocketPoolPairETH,
-					)
-					return nil
-				}
-				checkPrice = p.Price
-			}
-			err := o.handlers[RocketPoolPairETH].(*hooks.RocketPoolCircuitBreaker).Check(
-				o.ctx,
-				checkPrice,
-				refPrice.Price,
+		}
+	}
+	return nil
+}
+
+// circuitBreakerCheck compares the price with a reference price from the
+// given origin. The maximum allowed deviation is read from the circuit
+// breaker contract.
+//
+// Params:
+//   - ethereum_client - name of the Ethereum client
+//   - circuit_contract - address of the circuit breaker contract
+//   - reference_origin - origin of the reference price, "rocketpool" by default
+//   - price_pair - pair of the aggregated price to be compared with the
+//     reference price, the hook pair by default
+type circuitBreakerCheck struct {
+	pair            Pair
+	pricePair       string
+	referenceOrigin string
+	breaker         *hooks.RocketPoolCircuitBreaker
+}
+
+func newCircuitBreakerCheck(clients ethereumConfig.ClientRegistry, pair Pair, params map[string]any) (PriceCheck, error) {
+	breaker, err := hooks.NewRocketPoolCircuitBreaker(clients, params)
+	if err != nil {
+		return nil, err
+	}
+	c := &circuitBreakerCheck{
+		pair:            pair,
+		pricePair:       pair.String(),
+		referenceOrigin: "rocketpool",
+		breaker:         breaker,
+	}
+	if pair.String() == RocketPoolPairUSD {
+		c.pricePair = RocketPoolPairETH
+	}
+	if v, ok := params["price_pair"].(string); ok {
+		c.pricePair = v
+	}
+	if v, ok := params["reference_origin"].(string); ok {
+		c.referenceOrigin = v
+	}
+	return c, nil
+}
+
+func (c *circuitBreakerCheck) Check(ctx context.Context, price *Price, _ map[Pair]*Price) error {
+	checkPrice := price.Price
+	refPrice := findPrice(price.Prices, func(p *Price) bool {
+		return p.Parameters["origin"] == c.referenceOrigin
+	})
+	if refPrice == nil {
+		return fmt.Errorf("post price hook failed for %s, reference price not found", c.pair)
+	}
+	if refPrice.Price == 0 {
+		return fmt.Errorf("post price hook failed for %s, reference price should be > 0", c.pair)
+	}
+	if c.pricePair != c.pair.String() {
+		p := findPrice(price.Prices, func(p *Price) bool {
+			return p.Type == "aggregator" && p.Pair.String() == c.pricePair
+		})
+		if p == nil {
+			return fmt.Errorf(
+				"post price hook failed for %s, unable to find aggregate %s price",
+				c.pair,
+				c.pricePair,
 			)
-			if err != nil {
-				prices[pair].Error = err.Error()
-			}
-			return nil
-		default:
 		}
+		checkPrice = p.Price
+	}
+	return c.breaker.Check(ctx, checkPrice, refPrice.Price)
+}
+
+// maxDeviationCheck compares the price with a price of another pair.
+//
+// Params:
+//   - reference_pair - pair of the reference price, the price is looked up
+//     among checked prices and then among prices used to calculate the price
+//   - max_deviation - maximum allowed relative deviation, e.g. 0.05 for 5%
+type maxDeviationCheck struct {
+	pair          Pair
+	referencePair Pair
+	maxDeviation  float64
+}
+
+func newMaxDeviationCheck(_ ethereumConfig.ClientRegistry, pair Pair, params map[string]any) (PriceCheck, error) {
+	ref, ok := params["reference_pair"].(string)
+	if !ok {
+		return nil, fmt.Errorf("reference_pair parameter not found")
+	}
+	refPair, err := NewPair(ref)
+	if err != nil {
+		return nil, err
+	}
+	maxDeviation, err := positiveParam(params, "max_deviation")
+	if err != nil {
+		return nil, err
+	}
+	return &maxDeviationCheck{pair: pair, referencePair: refPair, maxDeviation: maxDeviation}, nil
+}
+
+func (c *maxDeviationCheck) Check(_ context.Context, price *Price, prices map[Pair]*Price) error {
+	refPrice, ok := prices[c.referencePair]
+	if !ok {
+		refPrice = findPrice(price.Prices, func(p *Price) bool {
+			return p.Pair == c.referencePair
+		})
+	}
+	if refPrice == nil || refPrice.Error != "" || refPrice.Price == 0 {
+		return fmt.Errorf("post price hook failed for %s, reference %s price not found", c.pair, c.referencePair)
+	}
+	deviation := math.Abs(price.Price-refPrice.Price) / refPrice.Price
+	if deviation > c.maxDeviation {
+		return fmt.Errorf(
+			"post price hook failed for %s, deviation from %s is too high: %f > %f",
+			c.pair,
+			c.referencePair,
+			deviation,
+			c.maxDeviation,
+		)
 	}
 	return nil
 }
+
+// maxAgeCheck verifies that the price is not older than the given age.
+//
+// Params:
+//   - max_age - maximum age of the price in seconds
+type maxAgeCheck struct {
+	pair   Pair
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func newMaxAgeCheck(_ ethereumConfig.ClientRegistry, pair Pair, params map[string]any) (PriceCheck, error) {
+	maxAge, err := positiveParam(params, "max_age")
+	if err != nil {
+		return nil, err
+	}
+	return &maxAgeCheck{pair: pair, maxAge: time.Duration(maxAge * float64(time.Second)), now: time.Now}, nil
+}
+
+func (c *maxAgeCheck) Check(_ context.Context, price *Price, _ map[Pair]*Price) error {
+	if age := c.now().Sub(price.Time); age > c.maxAge {
+		return fmt.Errorf("post price hook failed for %s, price is too old: %s > %s", c.pair, age, c.maxAge)
+	}
+	return nil
+}
+
+// minSourcesCheck verifies that the price is calculated using at least the
+// given number of valid origin prices.
+//
+// Params:
+//   - min_sources - minimum number of valid origin prices
+type minSourcesCheck struct {
+	pair       Pair
+	minSources int
+}
+
+func newMinSourcesCheck(_ ethereumConfig.ClientRegistry, pair Pair, params map[string]any) (PriceCheck, error) {
+	minSources, err := positiveParam(params, "min_sources")
+	if err != nil {
+		return nil, err
+	}
+	return &minSourcesCheck{pair: pair, minSources: int(minSources)}, nil
+}
+
+func (c *minSourcesCheck) Check(_ context.Context, price *Price, _ map[Pair]*Price) error {
+	if n := countSources(price); n < c.minSources {
+		return fmt.Errorf("post price hook failed for %s, not enough sources: %d < %d", c.pair, n, c.minSources)
+	}
+	return nil
+}
+
+// countSources returns the number of valid origin prices used to calculate
+// the price.
+func countSources(price *Price) int {
+	if price.Type == "origin" {
+		if price.Error == "" {
+			return 1
+		}
+		return 0
+	}
+	n := 0
+	for _, p := range price.Prices {
+		n += countSources(p)
+	}
+	return n
+}
+
+func positiveParam(params map[string]any, name string) (float64, error) {
+	v, ok := params[name].(float64)
+	if !ok {
+		return 0, fmt.Errorf("%s parameter not found", name)
+	}
+	if v <= 0 {
+		return 0, fmt.Errorf("%s parameter must be greater than zero", name)
+	}
+	return v, nil
+}
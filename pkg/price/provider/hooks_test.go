@@ -18,7 +18,9 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/defiweb/go-eth/types"
 
@@ -26,6 +28,7 @@ import (
 	ethereumMocks "github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPostPriceHook(t *testing.T) {
@@ -51,7 +54,7 @@ func TestPostPriceHook(t *testing.T) {
 	params["circuit_contract"] = contract
 	params["ethereum_client"] = "default"
 	pairParams := NewHookParams()
-	pairParams["RETH/ETH"] = params
+	pairParams["RETH/ETH"] = []map[string]any{params}
 
 	cli := &ethereumMocks.RPC{}
 	readMethodID := []byte{87, 222, 38, 164}
@@ -96,6 +99,82 @@ func TestPostPriceHook(t *testing.T) {
 	assert.True(t, prices[pair].Error == "")
 }
 
+type staticCheck struct{ err error }
+
+func (c staticCheck) Check(context.Context, *Price, map[Pair]*Price) error {
+	return c.err
+}
+
+func TestPostPriceHook_Checks(t *testing.T) {
+	RegisterPriceCheck("test", func(_ ethereum.ClientRegistry, _ Pair, params map[string]any) (PriceCheck, error) {
+		return staticCheck{err: fmt.Errorf("%v", params["error"])}, nil
+	})
+
+	btcusd := Pair{Base: "BTC", Quote: "USD"}
+	btcusdt := Pair{Base: "BTC", Quote: "USDT"}
+	ethusd := Pair{Base: "ETH", Quote: "USD"}
+	newPrices := func() map[Pair]*Price {
+		return map[Pair]*Price{
+			btcusd: {
+				Type:  "aggregator",
+				Pair:  btcusd,
+				Price: 100,
+				Time:  time.Now().Add(-time.Minute),
+				Prices: []*Price{
+					{Type: "origin", Pair: btcusd, Price: 100},
+					{Type: "origin", Pair: btcusd, Price: 100},
+					{Type: "origin", Pair: btcusd, Error: "failed"},
+				},
+			},
+			btcusdt: {Type: "origin", Pair: btcusdt, Price: 104, Time: time.Now()},
+			ethusd:  {Type: "origin", Pair: ethusd, Price: 10, Time: time.Now()},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		err    string
+	}{
+		{name: "max_age ok", params: map[string]any{"type": "max_age", "max_age": 120.0}},
+		{name: "max_age failed", params: map[string]any{"type": "max_age", "max_age": 30.0}, err: "price is too old"},
+		{name: "min_sources ok", params: map[string]any{"type": "min_sources", "min_sources": 2.0}},
+		{name: "min_sources failed", params: map[string]any{"type": "min_sources", "min_sources": 3.0}, err: "not enough sources: 2 < 3"},
+		{name: "max_deviation ok", params: map[string]any{"type": "max_deviation", "reference_pair": "BTC/USDT", "max_deviation": 0.05}},
+		{name: "max_deviation failed", params: map[string]any{"type": "max_deviation", "reference_pair": "BTC/USDT", "max_deviation": 0.01}, err: "deviation from BTC/USDT is too high"},
+		{name: "max_deviation missing", params: map[string]any{"type": "max_deviation", "reference_pair": "BTC/EUR", "max_deviation": 0.01}, err: "reference BTC/EUR price not found"},
+		{name: "registered", params: map[string]any{"type": "test", "error": "custom"}, err: "custom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewPostPriceHook(context.Background(), nil, HookParams{"BTC/USD": {tt.params}})
+			require.NoError(t, err)
+
+			prices := newPrices()
+			require.NoError(t, hook.Check(prices))
+			if tt.err == "" {
+				assert.Empty(t, prices[btcusd].Error)
+			} else {
+				assert.Contains(t, prices[btcusd].Error, tt.err)
+			}
+			assert.Empty(t, prices[ethusd].Error)
+		})
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := NewPostPriceHook(context.Background(), nil, HookParams{"BTC/USD": {{"type": "unknown"}}})
+		assert.Error(t, err)
+	})
+	t.Run("missing type", func(t *testing.T) {
+		_, err := NewPostPriceHook(context.Background(), nil, HookParams{"BTC/USD": {{"max_age": 10.0}}})
+		assert.Error(t, err)
+	})
+	t.Run("invalid params", func(t *testing.T) {
+		_, err := NewPostPriceHook(context.Background(), nil, HookParams{"BTC/USD": {{"type": "max_age", "max_age": -1.0}}})
+		assert.Error(t, err)
+	})
+}
+
 func TestFindPrices(t *testing.T) {
 	var prices Price
 
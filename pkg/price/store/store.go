@@ -168,6 +168,47 @@ func (p *PriceStore) collectPrice(price *messages.Price) error {
 	return p.Add(p.ctx, *from, price)
 }
 
+// collectPriceBatch adds prices from the price/v2 message to the store.
+//
+// All prices in the batch must be signed by the same feeder, so stored
+// prices can be used to update the Median contract. The whole batch
+// is rejected if any price is invalid. A price from the batch is added only
+// if the store does not already have a price of the same or newer age from
+// the same feeder, because the same price is usually also received in
+// a price/v1 message.
+func (p *PriceStore) collectPriceBatch(batch *messages.PriceBatch) (int, error) {
+	if len(batch.Prices) == 0 {
+		return 0, messages.ErrEmptyPriceBatch
+	}
+	from, err := batch.From(p.recover)
+	if err != nil {
+		return 0, ErrInvalidSignature
+	}
+	for _, price := range batch.Prices {
+		if !p.isPairSupported(price.Price.Wat) {
+			return 0, ErrUnknownPair
+		}
+		if price.Price.Val.Cmp(big.NewInt(0)) <= 0 {
+			return 0, ErrInvalidPrice
+		}
+	}
+	added := 0
+	for _, price := range batch.Prices {
+		prev, err := p.GetByFeeder(p.ctx, price.Price.Wat, *from)
+		if err != nil {
+			return added, err
+		}
+		if prev != nil && !prev.Price.Age.Before(price.Price.Age) {
+			continue
+		}
+		if err := p.Add(p.ctx, *from, price); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
 func (p *PriceStore) isPairSupported(pair string) bool {
 	for _, a := range p.pairs {
 		if a == pair {
@@ -180,6 +221,7 @@ func (p *PriceStore) isPairSupported(pair string) bool {
 func (p *PriceStore) priceCollectorRoutine() {
 	priceV0Ch := p.transport.Messages(messages.PriceV0MessageName)
 	priceV1Ch := p.transport.Messages(messages.PriceV1MessageName)
+	priceV2Ch := p.transport.Messages(messages.PriceV2MessageName)
 	for {
 		select {
 		case <-p.ctx.Done():
@@ -188,6 +230,8 @@ func (p *PriceStore) priceCollectorRoutine() {
 			p.handlePriceMessage(msg)
 		case msg := <-priceV1Ch:
 			p.handlePriceMessage(msg)
+		case msg := <-priceV2Ch:
+			p.handlePriceBatchMessage(msg)
 		}
 	}
 }
@@ -216,6 +260,38 @@ func (p *PriceStore) handlePriceMessage(msg transport.ReceivedMessage) {
 	}
 }
 
+func (p *PriceStore) handlePriceBatchMessage(msg transport.ReceivedMessage) {
+	if msg.Error != nil {
+		p.log.WithError(msg.Error).Error("Unable to read prices from the transport layer")
+		return
+	}
+	batch, ok := msg.Message.(*messages.PriceBatch)
+	if !ok {
+		p.log.Error("Unexpected value returned from the transport layer")
+		return
+	}
+	fields := log.Fields{
+		"from":    "*invalid signature*",
+		"prices":  len(batch.Prices),
+		"version": batch.Version,
+	}
+	if from, err := batch.From(p.recover); err == nil {
+		fields["from"] = from.String()
+	}
+	added, err := p.collectPriceBatch(batch)
+	if err != nil {
+		p.log.
+			WithError(err).
+			WithFields(fields).
+			Warn("Received invalid price batch")
+	} else {
+		p.log.
+			WithFields(fields).
+			WithField("added", added).
+			Info("Price batch received")
+	}
+}
+
 // contextCancelHandler handles context cancellation.
 func (p *PriceStore) contextCancelHandler() {
 	defer func() { close(p.waitCh) }()
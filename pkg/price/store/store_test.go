@@ -17,9 +17,11 @@ package store
 
 import (
 	"context"
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/defiweb/go-eth/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -87,3 +89,93 @@ func toOraclePrices(ps []*messages.Price) []*median.Price {
 	}
 	return r
 }
+
+func TestStore_PriceBatch(t *testing.T) {
+	ctx := context.Background()
+	rec := &mocks.Recoverer{}
+	ps, err := New(Config{
+		Storage:   NewMemoryStorage(),
+		Transport: local.New([]byte("test"), 0, nil),
+		Pairs:     []string{"AAABBB", "XXXYYY"},
+		Recoverer: rec,
+	})
+	require.NoError(t, err)
+	ps.ctx = ctx
+
+	priceSig := types.Signature{V: big.NewInt(27), R: big.NewInt(10), S: big.NewInt(11)}
+	rec.On("RecoverMessage", mock.Anything, priceSig).Return(&testutil.Address1, nil)
+
+	// The batch contains a price with the same age as the AAABBB price
+	// already in the store, so only the XXXYYY price should be added.
+	require.NoError(t, ps.Add(ctx, testutil.Address1, testutil.PriceAAABBB1))
+	batch := &messages.PriceBatch{
+		Prices: []*messages.Price{
+			{Price: &median.Price{Wat: "AAABBB", Val: big.NewInt(11), Age: time.Unix(100, 0), Sig: priceSig}},
+			{Price: &median.Price{Wat: "XXXYYY", Val: big.NewInt(12), Age: time.Unix(100, 0), Sig: priceSig}},
+		},
+	}
+	ps.handlePriceBatchMessage(transport.ReceivedMessage{Message: batch})
+
+	aaabbb := errutil.Must(ps.GetByFeeder(ctx, "AAABBB", testutil.Address1))
+	xxxyyy := errutil.Must(ps.GetByFeeder(ctx, "XXXYYY", testutil.Address1))
+	require.NotNil(t, aaabbb)
+	require.NotNil(t, xxxyyy)
+	assert.Equal(t, testutil.PriceAAABBB1.Price, aaabbb.Price)
+	assert.Equal(t, big.NewInt(12), xxxyyy.Price.Val)
+	assert.Equal(t, priceSig, xxxyyy.Price.Sig)
+
+	// Newer prices from the batch replace older ones.
+	batch.Prices[0].Price.Age = time.Unix(200, 0)
+	ps.handlePriceBatchMessage(transport.ReceivedMessage{Message: batch})
+
+	aaabbb = errutil.Must(ps.GetByFeeder(ctx, "AAABBB", testutil.Address1))
+	assert.Equal(t, big.NewInt(11), aaabbb.Price.Val)
+}
+
+func TestStore_PriceBatchInvalid(t *testing.T) {
+	rec := &mocks.Recoverer{}
+	ps, err := New(Config{
+		Storage:   NewMemoryStorage(),
+		Transport: local.New([]byte("test"), 0, nil),
+		Pairs:     []string{"AAABBB"},
+		Recoverer: rec,
+	})
+	require.NoError(t, err)
+	ps.ctx = context.Background()
+
+	priceSig := types.Signature{V: big.NewInt(27), R: big.NewInt(10), S: big.NewInt(11)}
+	otherSig := types.Signature{V: big.NewInt(27), R: big.NewInt(12), S: big.NewInt(13)}
+	rec.On("RecoverMessage", mock.Anything, priceSig).Return(&testutil.Address1, nil)
+	rec.On("RecoverMessage", mock.Anything, otherSig).Return(&testutil.Address2, nil)
+
+	_, err = ps.collectPriceBatch(&messages.PriceBatch{
+		Prices: []*messages.Price{
+			{Price: &median.Price{Wat: "AAABBB", Val: big.NewInt(11), Age: time.Unix(100, 0), Sig: priceSig}},
+			{Price: &median.Price{Wat: "XXXYYY", Val: big.NewInt(12), Age: time.Unix(100, 0), Sig: priceSig}},
+		},
+	})
+	assert.ErrorIs(t, err, ErrUnknownPair)
+
+	_, err = ps.collectPriceBatch(&messages.PriceBatch{
+		Prices: []*messages.Price{
+			{Price: &median.Price{Wat: "AAABBB", Val: big.NewInt(0), Age: time.Unix(100, 0), Sig: priceSig}},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidPrice)
+
+	// Prices signed by different feeders.
+	_, err = ps.collectPriceBatch(&messages.PriceBatch{
+		Prices: []*messages.Price{
+			{Price: &median.Price{Wat: "AAABBB", Val: big.NewInt(11), Age: time.Unix(100, 0), Sig: priceSig}},
+			{Price: &median.Price{Wat: "AAABBB", Val: big.NewInt(11), Age: time.Unix(100, 0), Sig: otherSig}},
+		},
+	})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = ps.collectPriceBatch(&messages.PriceBatch{})
+	assert.ErrorIs(t, err, messages.ErrEmptyPriceBatch)
+
+	// Nothing should be added if any price in the batch is invalid.
+	prices := errutil.Must(ps.GetAll(context.Background()))
+	assert.Empty(t, prices)
+}
@@ -0,0 +1,258 @@
+// Package compare compares prices calculated by the legacy price provider
+// with prices calculated by the pricenext provider.
+//
+// It is meant to be used during the migration to pricenext to make sure that
+// both providers return the same prices for the same pairs.
+package compare
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	legacyProvider "github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
+)
+
+// Report is a result of comparing prices for all pairs supported by the
+// legacy and pricenext providers.
+type Report struct {
+	// Time is the time when the prices were obtained.
+	Time time.Time `json:"time"`
+
+	// Results contains comparison results for pairs supported by both
+	// providers, sorted by pair.
+	Results []Result `json:"results"`
+
+	// LegacyOnly is a list of pairs supported only by the legacy provider.
+	LegacyOnly []string `json:"legacy_only,omitempty"`
+
+	// NextOnly is a list of pairs supported only by the pricenext provider.
+	NextOnly []string `json:"next_only,omitempty"`
+}
+
+// Result is a result of comparing prices for a single pair.
+type Result struct {
+	// Pair is the compared asset pair.
+	Pair string `json:"pair"`
+
+	// Model is the name of the pricenext price model used for the pair.
+	Model string `json:"model"`
+
+	// LegacyPrice and NextPrice are prices returned by the providers. They
+	// are zero if a provider failed to calculate the price.
+	LegacyPrice float64 `json:"legacy_price"`
+	NextPrice   float64 `json:"next_price"`
+
+	// Deviation is the relative difference between the prices, calculated
+	// as |next - legacy| / legacy. It is zero if any of the prices is
+	// missing.
+	Deviation float64 `json:"deviation"`
+
+	// LegacyError and NextError are errors returned by the providers.
+	LegacyError string `json:"legacy_error,omitempty"`
+	NextError   string `json:"next_error,omitempty"`
+
+	// LegacySources and NextSources are origins that provided valid prices
+	// only to one of the providers.
+	LegacySources []string `json:"legacy_sources,omitempty"`
+	NextSources   []string `json:"next_sources,omitempty"`
+}
+
+// Diverges returns true if the deviation exceeds the given threshold or if
+// only one of the providers was able to calculate the price.
+func (r Result) Diverges(threshold float64) bool {
+	if (r.LegacyError == "") != (r.NextError == "") {
+		return true
+	}
+	return r.Deviation > threshold
+}
+
+// Divergent returns results that diverge more than the given threshold.
+func (r *Report) Divergent(threshold float64) []Result {
+	var results []Result
+	for _, res := range r.Results {
+		if res.Diverges(threshold) {
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// Compare obtains prices for all pairs supported by both providers and
+// compares them.
+//
+// Prices from both providers are requested at the same time, so they are
+// calculated from origin prices available at the same moment. If multiple
+// pricenext models return prices for the same pair, the first one in
+// alphabetical order is used.
+//
+// If hook is not nil, it is used to check the legacy prices before they are
+// compared, the same way the legacy gofer does, so prices rejected by the
+// legacy checks are reported as errors.
+func Compare(
+	ctx context.Context,
+	legacy legacyProvider.Provider,
+	hook legacyProvider.PriceHook,
+	next provider.Provider,
+) (*Report, error) {
+
+	legacyPairs, err := legacy.Pairs()
+	if err != nil {
+		return nil, err
+	}
+	nextModels, err := next.Models(ctx, next.ModelNames(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find common pairs.
+	models := make(map[string]string) // pair -> model name
+	for _, name := range maputil.SortKeys(nextModels, sort.Strings) {
+		pair := nextModels[name].Pair.String()
+		if _, ok := models[pair]; !ok {
+			models[pair] = name
+		}
+	}
+	var (
+		report      = &Report{}
+		commonPairs []legacyProvider.Pair
+		commonNames []string
+		isLegacy    = make(map[string]bool)
+	)
+	for _, pair := range legacyPairs {
+		isLegacy[pair.String()] = true
+		if name, ok := models[pair.String()]; ok {
+			commonPairs = append(commonPairs, pair)
+			commonNames = append(commonNames, name)
+		} else {
+			report.LegacyOnly = append(report.LegacyOnly, pair.String())
+		}
+	}
+	for _, pair := range maputil.SortKeys(models, sort.Strings) {
+		if !isLegacy[pair] {
+			report.NextOnly = append(report.NextOnly, pair)
+		}
+	}
+	sort.Strings(report.LegacyOnly)
+	if len(commonPairs) == 0 {
+		report.Time = time.Now()
+		return report, nil
+	}
+
+	// Obtain prices from both providers at the same time.
+	var (
+		wg                 sync.WaitGroup
+		legacyPrices       map[legacyProvider.Pair]*legacyProvider.Price
+		nextTicks          map[string]provider.Tick
+		legacyErr, nextErr error
+	)
+	report.Time = time.Now()
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		legacyPrices, legacyErr = legacy.Prices(commonPairs...)
+	}()
+	go func() {
+		defer wg.Done()
+		nextTicks, nextErr = next.Ticks(ctx, commonNames...)
+	}()
+	wg.Wait()
+	if legacyErr != nil {
+		return nil, legacyErr
+	}
+	if nextErr != nil {
+		return nil, nextErr
+	}
+	if hook != nil {
+		if err := hook.Check(legacyPrices); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, pair := range commonPairs {
+		report.Results = append(report.Results, compare(pair.String(), commonNames[i], legacyPrices[pair], nextTicks[commonNames[i]]))
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Pair < report.Results[j].Pair
+	})
+	return report, nil
+}
+
+func compare(pair, model string, legacyPrice *legacyProvider.Price, nextTick provider.Tick) Result {
+	r := Result{Pair: pair, Model: model}
+	switch {
+	case legacyPrice == nil:
+		r.LegacyError = "price is missing"
+	case legacyPrice.Error != "":
+		r.LegacyError = legacyPrice.Error
+	default:
+		r.LegacyPrice = legacyPrice.Price
+	}
+	if err := nextTick.Validate(); err != nil {
+		r.NextError = err.Error()
+	} else {
+		r.NextPrice = nextTick.Price.Float64()
+	}
+	if r.LegacyPrice != 0 && r.NextPrice != 0 {
+		r.Deviation = math.Abs(r.NextPrice-r.LegacyPrice) / r.LegacyPrice
+	}
+
+	legacySources := make(map[string]bool)
+	if legacyPrice != nil {
+		legacyOrigins(legacyPrice, legacySources)
+	}
+	nextSources := make(map[string]bool)
+	nextOrigins(nextTick, nextSources)
+	for _, s := range maputil.SortKeys(legacySources, sort.Strings) {
+		if !nextSources[s] {
+			r.LegacySources = append(r.LegacySources, s)
+		}
+	}
+	for _, s := range maputil.SortKeys(nextSources, sort.Strings) {
+		if !legacySources[s] {
+			r.NextSources = append(r.NextSources, s)
+		}
+	}
+	return r
+}
+
+// legacyOrigins adds origins that provided valid prices to the given set.
+// Origins are identified by the origin name and pair.
+func legacyOrigins(price *legacyProvider.Price, origins map[string]bool) {
+	if price.Type == "origin" {
+		if price.Error == "" {
+			origins[price.Parameters["origin"]+" "+price.Pair.String()] = true
+		}
+		return
+	}
+	for _, p := range price.Prices {
+		legacyOrigins(p, origins)
+	}
+}
+
+// nextOrigins adds origins that provided valid ticks to the given set.
+// Origins are identified by the origin name and pair.
+func nextOrigins(tick provider.Tick, origins map[string]bool) {
+	if tick.Meta != nil {
+		if meta := tick.Meta.Meta(); meta["type"] == "origin" {
+			if tick.Validate() == nil {
+				origins[toString(meta["origin"])+" "+tick.Pair.String()] = true
+			}
+			return
+		}
+	}
+	for _, t := range tick.SubTicks {
+		nextOrigins(t, origins)
+	}
+}
+
+func toString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
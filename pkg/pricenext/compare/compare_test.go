@@ -0,0 +1,184 @@
+package compare
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	legacyProvider "github.com/chronicleprotocol/oracle-suite/pkg/price/provider"
+	legacyMocks "github.com/chronicleprotocol/oracle-suite/pkg/price/provider/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+type nextProvider struct {
+	models map[string]provider.Model
+	ticks  map[string]provider.Tick
+}
+
+func (p *nextProvider) ModelNames(_ context.Context) []string {
+	var names []string
+	for name := range p.models {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (p *nextProvider) Tick(_ context.Context, model string) (provider.Tick, error) {
+	return p.ticks[model], nil
+}
+
+func (p *nextProvider) Ticks(_ context.Context, models ...string) (map[string]provider.Tick, error) {
+	ticks := make(map[string]provider.Tick)
+	for _, m := range models {
+		ticks[m] = p.ticks[m]
+	}
+	return ticks, nil
+}
+
+func (p *nextProvider) Model(_ context.Context, model string) (provider.Model, error) {
+	return p.models[model], nil
+}
+
+func (p *nextProvider) Models(_ context.Context, models ...string) (map[string]provider.Model, error) {
+	res := make(map[string]provider.Model)
+	for _, m := range models {
+		res[m] = p.models[m]
+	}
+	return res, nil
+}
+
+// priceHook marks legacy prices of the given pairs as invalid.
+type priceHook struct {
+	reject map[string]string
+}
+
+func (h priceHook) Check(prices map[legacyProvider.Pair]*legacyProvider.Price) error {
+	for pair, price := range prices {
+		if msg, ok := h.reject[pair.String()]; ok {
+			price.Error = msg
+		}
+	}
+	return nil
+}
+
+func originTick(origin string, pair provider.Pair, price float64) provider.Tick {
+	return provider.Tick{
+		Pair:  pair,
+		Price: bn.Float(price),
+		Time:  time.Now(),
+		Meta:  graph.MapMeta{"type": "origin", "origin": origin},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	ethusd := provider.Pair{Base: "ETH", Quote: "USD"}
+	daiusd := provider.Pair{Base: "DAI", Quote: "USD"}
+
+	legacy := &legacyMocks.Provider{}
+	legacyPairs := []legacyProvider.Pair{
+		{Base: "BTC", Quote: "USD"},
+		{Base: "ETH", Quote: "USD"},
+		{Base: "USDC", Quote: "USD"},
+	}
+	legacy.On("Pairs").Return(legacyPairs, nil)
+	legacy.On("Prices", legacyPairs[0], legacyPairs[1]).Return(map[legacyProvider.Pair]*legacyProvider.Price{
+		legacyPairs[0]: {
+			Type:  "median",
+			Pair:  legacyPairs[0],
+			Price: 100,
+			Prices: []*legacyProvider.Price{
+				{Type: "origin", Pair: legacyPairs[0], Price: 100, Parameters: map[string]string{"origin": "a"}},
+				{Type: "origin", Pair: legacyPairs[0], Price: 100, Parameters: map[string]string{"origin": "b"}},
+			},
+		},
+		legacyPairs[1]: {
+			Type:  "origin",
+			Pair:  legacyPairs[1],
+			Error: "origin failed",
+		},
+	}, nil)
+
+	next := &nextProvider{
+		models: map[string]provider.Model{
+			"btc": {Pair: btcusd},
+			"eth": {Pair: ethusd},
+			"dai": {Pair: daiusd},
+		},
+		ticks: map[string]provider.Tick{
+			"btc": {
+				Pair:  btcusd,
+				Price: bn.Float(102),
+				Time:  time.Now(),
+				Meta:  graph.MapMeta{"type": "median"},
+				SubTicks: []provider.Tick{
+					originTick("a", btcusd, 102),
+					originTick("c", btcusd, 102),
+					{Pair: btcusd, Meta: graph.MapMeta{"type": "origin", "origin": "b"}, Error: errors.New("failed")},
+				},
+			},
+			"eth": originTick("a", ethusd, 1000),
+		},
+	}
+
+	report, err := Compare(context.Background(), legacy, nil, next)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"USDC/USD"}, report.LegacyOnly)
+	assert.Equal(t, []string{"DAI/USD"}, report.NextOnly)
+	require.Len(t, report.Results, 2)
+
+	btc := report.Results[0]
+	assert.Equal(t, "BTC/USD", btc.Pair)
+	assert.Equal(t, "btc", btc.Model)
+	assert.Equal(t, 100.0, btc.LegacyPrice)
+	assert.Equal(t, 102.0, btc.NextPrice)
+	assert.InDelta(t, 0.02, btc.Deviation, 1e-9)
+	assert.Equal(t, []string{"b BTC/USD"}, btc.LegacySources)
+	assert.Equal(t, []string{"c BTC/USD"}, btc.NextSources)
+
+	eth := report.Results[1]
+	assert.Equal(t, "ETH/USD", eth.Pair)
+	assert.Equal(t, "origin failed", eth.LegacyError)
+	assert.Empty(t, eth.NextError)
+	assert.Equal(t, 1000.0, eth.NextPrice)
+	assert.Zero(t, eth.Deviation)
+
+	assert.Len(t, report.Divergent(0.05), 1)
+	assert.Len(t, report.Divergent(0.01), 2)
+}
+
+func TestCompare_PriceHook(t *testing.T) {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	legacyPair := legacyProvider.Pair{Base: "BTC", Quote: "USD"}
+
+	legacy := &legacyMocks.Provider{}
+	legacy.On("Pairs").Return([]legacyProvider.Pair{legacyPair}, nil)
+	legacy.On("Prices", legacyPair).Return(map[legacyProvider.Pair]*legacyProvider.Price{
+		legacyPair: {
+			Type:       "origin",
+			Pair:       legacyPair,
+			Price:      100,
+			Parameters: map[string]string{"origin": "a"},
+		},
+	}, nil)
+	next := &nextProvider{
+		models: map[string]provider.Model{"btc": {Pair: btcusd}},
+		ticks:  map[string]provider.Tick{"btc": originTick("a", btcusd, 100)},
+	}
+
+	// Prices rejected by the legacy hook must be reported as errors, even
+	// if they are equal to the pricenext prices.
+	report, err := Compare(context.Background(), legacy, priceHook{reject: map[string]string{"BTC/USD": "circuit breaker"}}, next)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 1)
+	assert.Equal(t, "circuit breaker", report.Results[0].LegacyError)
+	assert.Zero(t, report.Results[0].LegacyPrice)
+	assert.Len(t, report.Divergent(0.01), 1)
+}
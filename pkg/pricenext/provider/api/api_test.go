@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/graph"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+func newTestServer(t *testing.T, ctx context.Context, now time.Time) *Server {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	originA := graph.NewOriginNode("a", btcusd, btcusd, time.Minute, time.Minute*2)
+	originB := graph.NewOriginNode("b", btcusd, btcusd, time.Minute, time.Minute*2)
+	require.NoError(t, originA.SetTick(provider.Tick{
+		Pair:      btcusd,
+		Price:     bn.Float("20000.123456789012345"),
+		Volume24h: bn.Float(10),
+		Time:      now,
+	}))
+	require.NoError(t, originB.SetTick(provider.Tick{
+		Pair:  btcusd,
+		Price: bn.Float("20000.123456789012345"),
+		Time:  now,
+	}))
+	median := graph.NewMedianNode(btcusd, 1)
+	require.NoError(t, median.AddBranch(originA, originB))
+	srv, err := NewServer(ServerConfig{
+		Provider: graph.NewProvider(map[string]graph.Node{"BTC/USD": median}, nil),
+		Address:  "127.0.0.1:0",
+	})
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(ctx))
+	return srv
+}
+
+func TestClient(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	now := time.Now()
+	srv := newTestServer(t, ctx, now)
+	cli, err := NewClient(ClientConfig{Address: srv.Addr()})
+	require.NoError(t, err)
+
+	t.Run("model names", func(t *testing.T) {
+		assert.Equal(t, []string{"BTC/USD"}, cli.ModelNames(ctx))
+	})
+	t.Run("tick", func(t *testing.T) {
+		tick, err := cli.Tick(ctx, "BTC/USD")
+		require.NoError(t, err)
+		assert.Equal(t, provider.Pair{Base: "BTC", Quote: "USD"}, tick.Pair)
+		assert.Equal(t, 0, bn.Float("20000.123456789012345").Cmp(tick.Price))
+		assert.True(t, tick.Time.Equal(now))
+		assert.Equal(t, "median", tick.Meta.Meta()["type"])
+		require.Len(t, tick.SubTicks, 2)
+		assert.Equal(t, "10", tick.SubTicks[0].Volume24h.String())
+		assert.Nil(t, tick.SubTicks[1].Volume24h)
+	})
+	t.Run("ticks", func(t *testing.T) {
+		ticks, err := cli.Ticks(ctx, "BTC/USD")
+		require.NoError(t, err)
+		require.Contains(t, ticks, "BTC/USD")
+		assert.NoError(t, ticks["BTC/USD"].Validate())
+	})
+	t.Run("model", func(t *testing.T) {
+		model, err := cli.Model(ctx, "BTC/USD")
+		require.NoError(t, err)
+		assert.Equal(t, provider.Pair{Base: "BTC", Quote: "USD"}, model.Pair)
+		assert.Equal(t, "median", model.Meta.Meta()["type"])
+		require.Len(t, model.Models, 2)
+		assert.Equal(t, "origin", model.Models[0].Meta.Meta()["type"])
+	})
+	t.Run("unknown model", func(t *testing.T) {
+		_, err := cli.Ticks(ctx, "ETH/USD")
+		assert.EqualError(t, err, "model ETH/USD not found")
+	})
+}
+
+func TestTickJSON(t *testing.T) {
+	tick := provider.Tick{
+		Pair:  provider.Pair{Base: "ETH", Quote: "USD"},
+		Price: bn.Float(1500),
+		Time:  time.Unix(1600000000, 0).In(time.UTC),
+		Meta:  mapMeta{"type": "test"},
+		Error: errors.New("test error"),
+		SubTicks: []provider.Tick{{
+			Pair:  provider.Pair{Base: "ETH", Quote: "USD"},
+			Price: bn.Float(1500),
+			Time:  time.Unix(1600000000, 0).In(time.UTC),
+			Meta:  mapMeta{},
+		}},
+	}
+	decoded := tickFromJSON(tickToJSON(tick))
+	assert.Equal(t, tick.Pair, decoded.Pair)
+	assert.Equal(t, 0, tick.Price.Cmp(decoded.Price))
+	assert.Nil(t, decoded.Volume24h)
+	assert.Equal(t, tick.Time, decoded.Time)
+	assert.Equal(t, tick.Meta, decoded.Meta)
+	assert.EqualError(t, decoded.Error, "test error")
+	require.Len(t, decoded.SubTicks, 1)
+	assert.NoError(t, decoded.SubTicks[0].Validate())
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+)
+
+// Client implements the provider.Provider interface. It uses a remote
+// Server to fetch ticks and models.
+type Client struct {
+	client *http.Client
+	url    *url.URL
+}
+
+// ClientConfig is the configuration for the Client.
+type ClientConfig struct {
+	// Address is the address of the Server in the form "host:port" or
+	// a full URL, e.g. "http://host:port".
+	Address string
+
+	// HTTPClient is the HTTP client used to send requests. If nil, the
+	// default HTTP client is used.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a new Client instance.
+func NewClient(cfg ClientConfig) (*Client, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("address must not be empty")
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	addr := cfg.Address
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	return &Client{
+		client: cfg.HTTPClient,
+		url:    u,
+	}, nil
+}
+
+// ModelNames implements the provider.Provider interface.
+//
+// Because the interface does not allow returning an error, an empty list is
+// returned if the request fails.
+func (c *Client) ModelNames(ctx context.Context) []string {
+	var names []string
+	if err := c.get(ctx, modelNamesPath, nil, &names); err != nil {
+		return nil
+	}
+	return names
+}
+
+// Tick implements the provider.Provider interface.
+func (c *Client) Tick(ctx context.Context, model string) (provider.Tick, error) {
+	ticks, err := c.Ticks(ctx, model)
+	if err != nil {
+		return provider.Tick{}, err
+	}
+	tick, ok := ticks[model]
+	if !ok {
+		return provider.Tick{}, fmt.Errorf("model %s not found", model)
+	}
+	return tick, nil
+}
+
+// Ticks implements the provider.Provider interface.
+func (c *Client) Ticks(ctx context.Context, models ...string) (map[string]provider.Tick, error) {
+	var resp map[string]jsonTick
+	if err := c.get(ctx, ticksPath, models, &resp); err != nil {
+		return nil, err
+	}
+	ticks := make(map[string]provider.Tick, len(resp))
+	for name, tick := range resp {
+		ticks[name] = tickFromJSON(tick)
+	}
+	return ticks, nil
+}
+
+// Model implements the provider.Provider interface.
+func (c *Client) Model(ctx context.Context, model string) (provider.Model, error) {
+	models, err := c.Models(ctx, model)
+	if err != nil {
+		return provider.Model{}, err
+	}
+	m, ok := models[model]
+	if !ok {
+		return provider.Model{}, fmt.Errorf("model %s not found", model)
+	}
+	return m, nil
+}
+
+// Models implements the provider.Provider interface.
+func (c *Client) Models(ctx context.Context, models ...string) (map[string]provider.Model, error) {
+	var resp map[string]jsonModel
+	if err := c.get(ctx, modelsPath, models, &resp); err != nil {
+		return nil, err
+	}
+	res := make(map[string]provider.Model, len(resp))
+	for name, model := range resp {
+		res[name] = modelFromJSON(model)
+	}
+	return res, nil
+}
+
+// get sends a GET request to the given path with the given models as query
+// parameters and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, models []string, v any) error {
+	u := *c.url
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	q := url.Values{}
+	for _, m := range models {
+		q.Add(modelQueryParam, m)
+	}
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		var apiErr jsonError
+		if err := json.NewDecoder(res.Body).Decode(&apiErr); err == nil && apiErr.Error != "" {
+			return errors.New(apiErr.Error)
+		}
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+// jsonTick is a JSON representation of the provider.Tick.
+//
+// Unlike provider.Tick.MarshalJSON, prices are encoded as strings to avoid
+// precision loss, and the original error is preserved.
+type jsonTick struct {
+	Pair      provider.Pair  `json:"pair"`
+	Price     string         `json:"price,omitempty"`
+	Volume24h string         `json:"volume24h,omitempty"`
+	Time      time.Time      `json:"time"`
+	SubTicks  []jsonTick     `json:"sub_ticks,omitempty"`
+	Meta      map[string]any `json:"meta,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// jsonModel is a JSON representation of the provider.Model.
+type jsonModel struct {
+	Pair   provider.Pair  `json:"pair"`
+	Meta   map[string]any `json:"meta,omitempty"`
+	Models []jsonModel    `json:"models,omitempty"`
+}
+
+// jsonError is a JSON representation of an error returned by the API.
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+// mapMeta implements the provider.Meta interface for metadata decoded from
+// JSON.
+type mapMeta map[string]any
+
+// Meta implements the provider.Meta interface.
+func (m mapMeta) Meta() map[string]any {
+	return m
+}
+
+func tickToJSON(t provider.Tick) jsonTick {
+	j := jsonTick{
+		Pair: t.Pair,
+		Time: t.Time.In(time.UTC),
+	}
+	if t.Price != nil {
+		j.Price = t.Price.Text('g', -1)
+	}
+	if t.Volume24h != nil {
+		j.Volume24h = t.Volume24h.Text('g', -1)
+	}
+	if t.Meta != nil {
+		j.Meta = t.Meta.Meta()
+	}
+	if t.Error != nil {
+		j.Error = t.Error.Error()
+	}
+	for _, st := range t.SubTicks {
+		j.SubTicks = append(j.SubTicks, tickToJSON(st))
+	}
+	return j
+}
+
+func tickFromJSON(j jsonTick) provider.Tick {
+	t := provider.Tick{
+		Pair: j.Pair,
+		Time: j.Time,
+		Meta: mapMeta(j.Meta),
+	}
+	if j.Meta == nil {
+		t.Meta = mapMeta{}
+	}
+	if j.Price != "" {
+		t.Price = bn.Float(j.Price)
+	}
+	if j.Volume24h != "" {
+		t.Volume24h = bn.Float(j.Volume24h)
+	}
+	if j.Error != "" {
+		t.Error = errors.New(j.Error)
+	}
+	for _, st := range j.SubTicks {
+		t.SubTicks = append(t.SubTicks, tickFromJSON(st))
+	}
+	return t
+}
+
+func modelToJSON(m provider.Model) jsonModel {
+	j := jsonModel{Pair: m.Pair}
+	if m.Meta != nil {
+		j.Meta = m.Meta.Meta()
+	}
+	for _, sm := range m.Models {
+		j.Models = append(j.Models, modelToJSON(sm))
+	}
+	return j
+}
+
+func modelFromJSON(j jsonModel) provider.Model {
+	m := provider.Model{
+		Pair: j.Pair,
+		Meta: mapMeta(j.Meta),
+	}
+	if j.Meta == nil {
+		m.Meta = mapMeta{}
+	}
+	for _, sm := range j.Models {
+		m.Models = append(m.Models, modelFromJSON(sm))
+	}
+	return m
+}
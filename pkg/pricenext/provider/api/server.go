@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver"
+	"github.com/chronicleprotocol/oracle-suite/pkg/httpserver/middleware"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+)
+
+const LoggerTag = "GOFER_API"
+
+// defaultTimeout is the default timeout for the HTTP server.
+const defaultTimeout = 10 * time.Second
+
+// API paths.
+const (
+	modelNamesPath = "/v1/model_names"
+	modelsPath     = "/v1/models"
+	ticksPath      = "/v1/ticks"
+)
+
+// modelQueryParam is the name of the query parameter used to specify
+// models. It may be repeated to request multiple models.
+const modelQueryParam = "model"
+
+// Server exposes a provider.Provider over an HTTP JSON API.
+//
+// It provides the following GET endpoints:
+//
+//	/v1/model_names             - returns a list of model names
+//	/v1/models?model=A&model=B  - returns the given models
+//	/v1/ticks?model=A&model=B   - returns ticks for the given models
+//
+// Errors are returned as a JSON object with an "error" field.
+type Server struct {
+	ctx context.Context
+
+	srv      *httpserver.HTTPServer
+	provider provider.Provider
+	log      log.Logger
+}
+
+// ServerConfig is the configuration for the Server.
+type ServerConfig struct {
+	// Provider is the price provider to expose.
+	Provider provider.Provider
+
+	// Address specifies the TCP address for the server to listen on in the
+	// form "host:port".
+	Address string
+
+	// Logger is a current logger used by the Server.
+	Logger log.Logger
+}
+
+// NewServer returns a new instance of the Server.
+func NewServer(cfg ServerConfig) (*Server, error) {
+	if cfg.Provider == nil {
+		return nil, errors.New("price provider must not be nil")
+	}
+	if cfg.Address == "" {
+		return nil, errors.New("address must not be empty")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	s := &Server{
+		provider: cfg.Provider,
+		log:      cfg.Logger.WithField("tag", LoggerTag),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(modelNamesPath, s.handleModelNames)
+	mux.HandleFunc(modelsPath, s.handleModels)
+	mux.HandleFunc(ticksPath, s.handleTicks)
+	s.srv = httpserver.New(&http.Server{
+		Addr:              cfg.Address,
+		Handler:           mux,
+		IdleTimeout:       defaultTimeout,
+		ReadTimeout:       defaultTimeout,
+		WriteTimeout:      defaultTimeout,
+		ReadHeaderTimeout: defaultTimeout,
+	})
+	s.srv.Use(&middleware.HealthCheck{
+		Path:  "/health",
+		Check: func(r *http.Request) bool { return true },
+	})
+	s.srv.Use(&middleware.Logger{Log: s.log})
+	return s, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (s *Server) Start(ctx context.Context) error {
+	if s.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	s.log.Infof("Starting")
+	s.ctx = ctx
+	err := s.srv.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to start the HTTP server: %w", err)
+	}
+	go s.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (s *Server) Wait() <-chan error {
+	return s.srv.Wait()
+}
+
+// Addr returns the server's network address.
+func (s *Server) Addr() string {
+	return s.srv.Addr().String()
+}
+
+func (s *Server) handleModelNames(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	s.writeJSON(res, http.StatusOK, s.provider.ModelNames(req.Context()))
+}
+
+func (s *Server) handleModels(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	models, err := s.provider.Models(req.Context(), req.URL.Query()[modelQueryParam]...)
+	if err != nil {
+		s.writeJSON(res, http.StatusBadRequest, jsonError{Error: err.Error()})
+		return
+	}
+	resp := make(map[string]jsonModel, len(models))
+	for name, model := range models {
+		resp[name] = modelToJSON(model)
+	}
+	s.writeJSON(res, http.StatusOK, resp)
+}
+
+func (s *Server) handleTicks(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ticks, err := s.provider.Ticks(req.Context(), req.URL.Query()[modelQueryParam]...)
+	if err != nil {
+		s.writeJSON(res, http.StatusBadRequest, jsonError{Error: err.Error()})
+		return
+	}
+	resp := make(map[string]jsonTick, len(ticks))
+	for name, tick := range ticks {
+		resp[name] = tickToJSON(tick)
+	}
+	s.writeJSON(res, http.StatusOK, resp)
+}
+
+func (s *Server) writeJSON(res http.ResponseWriter, status int, v any) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(status)
+	if err := json.NewEncoder(res).Encode(v); err != nil {
+		s.log.WithError(err).Error("Unable to encode response")
+	}
+}
+
+func (s *Server) contextCancelHandler() {
+	defer s.log.Info("Stopped")
+	<-s.ctx.Done()
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"fmt"
+	"time"
+)
+
+// cloneGraph returns a copy of the graph in which origin nodes are replaced
+// by nodes returned by the given function. Nodes shared by multiple branches
+// are cloned only once, so the structure of the graph is preserved.
+//
+// Only nodes defined in this package that do not keep their own state can be
+// cloned, see isStateful.
+func cloneGraph(node Node, origin func(*OriginNode) Node) (Node, error) {
+	return cloneNode(node, origin, make(map[Node]Node))
+}
+
+func cloneNode(node Node, origin func(*OriginNode) Node, cloned map[Node]Node) (Node, error) {
+	if c, ok := cloned[node]; ok {
+		return c, nil
+	}
+	var (
+		c   Node
+		err error
+	)
+	branches := func(nodes []Node) []Node {
+		if err != nil || nodes == nil {
+			return nil
+		}
+		cs := make([]Node, len(nodes))
+		for i, n := range nodes {
+			if cs[i], err = cloneNode(n, origin, cloned); err != nil {
+				return nil
+			}
+		}
+		return cs
+	}
+	branch := func(node Node) Node {
+		if err != nil || node == nil {
+			return nil
+		}
+		var c Node
+		c, err = cloneNode(node, origin, cloned)
+		return c
+	}
+	switch n := node.(type) {
+	case *OriginNode:
+		c = origin(n)
+	case *MedianNode:
+		cn := *n
+		cn.branches = branches(n.branches)
+		c = &cn
+	case *IndirectNode:
+		cn := *n
+		cn.branches = branches(n.branches)
+		c = &cn
+	case *FallbackNode:
+		cn := *n
+		cn.branches = branches(n.branches)
+		c = &cn
+	case *ExpressionNode:
+		cn := *n
+		cn.branches = branches(n.branches)
+		c = &cn
+	case *VolumeWeightedNode:
+		cn := *n
+		cn.branches = branches(n.branches)
+		c = &cn
+	case *InvertNode:
+		cn := *n
+		cn.branch = branch(n.branch)
+		c = &cn
+	case *ReferenceNode:
+		cn := *n
+		cn.branch = branch(n.branch)
+		c = &cn
+	case *WrapperNode:
+		cn := *n
+		cn.branch = branch(n.branch)
+		c = &cn
+	case *DevCircuitBreakerNode:
+		cn := *n
+		cn.priceBranch = branch(n.priceBranch)
+		cn.referenceBranch = branch(n.referenceBranch)
+		c = &cn
+	default:
+		return nil, fmt.Errorf("unable to clone node of type %T", node)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cloned[node] = c
+	return c, nil
+}
+
+// isStateful returns true if the graph contains nodes whose ticks depend on
+// the ticks they observed before, like TWAP nodes. Such graphs cannot be
+// evaluated as of a past time using only origin ticks.
+func isStateful(node Node) bool {
+	stateful := false
+	Walk(func(n Node) {
+		if _, ok := n.(*TWAPNode); ok {
+			stateful = true
+		}
+	}, node)
+	return stateful
+}
+
+// historicalOriginNode returns a copy of the origin node that holds the tick
+// recorded in the history at the given time, and that considers the given
+// time to be the current time when checking whether the tick is expired.
+func historicalOriginNode(n *OriginNode, history *TickHistory, at time.Time) *OriginNode {
+	c := NewOriginNode(n.origin, n.pair, n.fetchPair, n.freshnessThreshold, n.expiryThreshold)
+	c.now = func() time.Time { return at }
+	if tick, ok := history.OriginTick(n.origin, n.fetchPair, at); ok {
+		_ = c.SetTick(tick)
+	}
+	return c
+}
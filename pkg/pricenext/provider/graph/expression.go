@@ -0,0 +1,245 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+// ExpressionNode is a node that calculates a price using an arithmetic
+// expression over prices of its branches and constants.
+//
+// The expression may contain variables, numeric constants, parentheses and
+// the +, -, *, / operators, e.g. "steth_eth * eth_usd * 1.1". Each variable
+// refers to the price of the branch with the same index as the variable name
+// in the list of variables passed to the constructor.
+//
+// The time of the returned tick is the time of the oldest branch tick, or the
+// current time if the expression has no variables. If any branch tick is
+// invalid, the returned tick is invalid too.
+type ExpressionNode struct {
+	pair       provider.Pair
+	expression string
+	expr       ast.Expr
+	variables  []string
+	branches   []Node
+
+	// now returns the current time, which is used as the tick time if
+	// the expression has no variables.
+	now func() time.Time
+}
+
+// NewExpressionNode creates a new ExpressionNode instance.
+//
+// The variables argument is a list of variable names that can be used in the
+// expression. Branches are assigned to variables in the order in which they
+// are added.
+func NewExpressionNode(pair provider.Pair, expression string, variables []string) (*ExpressionNode, error) {
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	seen := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		if !token.IsIdentifier(v) {
+			return nil, fmt.Errorf("invalid variable name: %q", v)
+		}
+		if seen[v] {
+			return nil, fmt.Errorf("duplicate variable name: %s", v)
+		}
+		seen[v] = true
+	}
+	if err := validateExpression(expr, seen); err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return &ExpressionNode{
+		pair:       pair,
+		expression: expression,
+		expr:       expr,
+		variables:  variables,
+		now:        time.Now,
+	}, nil
+}
+
+// AddBranch implements the Node interface.
+//
+// Node requires exactly one branch for every variable. If more branches are
+// added, an error is returned.
+func (n *ExpressionNode) AddBranch(branch ...Node) error {
+	if len(n.branches)+len(branch) > len(n.variables) {
+		return fmt.Errorf("expected %d branches, got %d", len(n.variables), len(n.branches)+len(branch))
+	}
+	n.branches = append(n.branches, branch...)
+	return nil
+}
+
+// Branches implements the Node interface.
+func (n *ExpressionNode) Branches() []Node {
+	return n.branches
+}
+
+// Pair implements the Node interface.
+func (n *ExpressionNode) Pair() provider.Pair {
+	return n.pair
+}
+
+// Tick implements the Node interface.
+func (n *ExpressionNode) Tick() provider.Tick {
+	meta := n.Meta()
+	if len(n.branches) != len(n.variables) {
+		return provider.Tick{
+			Pair:  n.pair,
+			Meta:  meta,
+			Error: fmt.Errorf("expected %d branches, got %d", len(n.variables), len(n.branches)),
+		}
+	}
+
+	var (
+		tm     time.Time
+		ticks  = make([]provider.Tick, len(n.branches))
+		values = make(map[string]*bn.FloatNumber, len(n.branches))
+	)
+	for i, branch := range n.branches {
+		ticks[i] = branch.Tick()
+	}
+	for i, tick := range ticks {
+		if err := tick.Validate(); err != nil {
+			return provider.Tick{
+				Pair:     n.pair,
+				SubTicks: ticks,
+				Meta:     meta,
+				Error:    fmt.Errorf("invalid tick for %s: %w", n.variables[i], err),
+			}
+		}
+		if tm.IsZero() || tick.Time.Before(tm) {
+			tm = tick.Time
+		}
+		values[n.variables[i]] = tick.Price
+	}
+
+	if tm.IsZero() {
+		tm = n.now()
+	}
+	price, err := evalExpression(n.expr, values)
+	if err != nil {
+		return provider.Tick{
+			Pair:     n.pair,
+			SubTicks: ticks,
+			Meta:     meta,
+			Error:    fmt.Errorf("unable to evaluate expression: %w", err),
+		}
+	}
+	return provider.Tick{
+		Pair:     n.pair,
+		Price:    price,
+		Time:     tm,
+		SubTicks: ticks,
+		Meta:     meta,
+	}
+}
+
+// SetClock sets the function that returns the current time used as the tick
+// time if the expression has no variables. By default, time.Now is used.
+func (n *ExpressionNode) SetClock(now func() time.Time) {
+	n.now = now
+}
+
+// Meta implements the Node interface.
+func (n *ExpressionNode) Meta() provider.Meta {
+	return MapMeta{
+		"type":       "expression",
+		"expression": n.expression,
+		"variables":  strings.Join(n.variables, ","),
+	}
+}
+
+// validateExpression verifies that the expression contains only supported
+// operations and known variables.
+func validateExpression(expr ast.Expr, variables map[string]bool) error {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return fmt.Errorf("unsupported literal: %s", e.Value)
+		}
+		if bn.Float(e.Value) == nil {
+			return fmt.Errorf("invalid number: %s", e.Value)
+		}
+		return nil
+	case *ast.Ident:
+		if !variables[e.Name] {
+			return fmt.Errorf("unknown variable: %s", e.Name)
+		}
+		return nil
+	case *ast.ParenExpr:
+		return validateExpression(e.X, variables)
+	case *ast.UnaryExpr:
+		if e.Op != token.ADD && e.Op != token.SUB {
+			return fmt.Errorf("unsupported operator: %s", e.Op)
+		}
+		return validateExpression(e.X, variables)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+		default:
+			return fmt.Errorf("unsupported operator: %s", e.Op)
+		}
+		if err := validateExpression(e.X, variables); err != nil {
+			return err
+		}
+		return validateExpression(e.Y, variables)
+	default:
+		return errors.New("unsupported expression")
+	}
+}
+
+// evalExpression evaluates an expression previously verified by
+// validateExpression.
+func evalExpression(expr ast.Expr, values map[string]*bn.FloatNumber) (*bn.FloatNumber, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return bn.Float(e.Value), nil
+	case *ast.Ident:
+		return values[e.Name], nil
+	case *ast.ParenExpr:
+		return evalExpression(e.X, values)
+	case *ast.UnaryExpr:
+		x, err := evalExpression(e.X, values)
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == token.SUB {
+			return x.Neg(), nil
+		}
+		return x, nil
+	case *ast.BinaryExpr:
+		x, err := evalExpression(e.X, values)
+		if err != nil {
+			return nil, err
+		}
+		y, err := evalExpression(e.Y, values)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Op {
+		case token.ADD:
+			return x.Add(y), nil
+		case token.SUB:
+			return x.Sub(y), nil
+		case token.MUL:
+			return x.Mul(y), nil
+		case token.QUO:
+			if y.Sign() == 0 {
+				return nil, errors.New("division by zero")
+			}
+			return x.Div(y), nil
+		}
+	}
+	return nil, errors.New("unsupported expression")
+}
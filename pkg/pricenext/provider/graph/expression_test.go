@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+func TestNewExpressionNode(t *testing.T) {
+	pair := provider.Pair{Base: "A", Quote: "B"}
+	tests := []struct {
+		name       string
+		expression string
+		variables  []string
+		wantErr    bool
+	}{
+		{name: "valid", expression: "(a + b) * -2 / 1.5", variables: []string{"a", "b"}},
+		{name: "constant", expression: "1", variables: nil},
+		{name: "syntax error", expression: "a +", variables: []string{"a"}, wantErr: true},
+		{name: "unknown variable", expression: "a * c", variables: []string{"a", "b"}, wantErr: true},
+		{name: "unsupported operator", expression: "a % b", variables: []string{"a", "b"}, wantErr: true},
+		{name: "unsupported expression", expression: "f(a)", variables: []string{"a"}, wantErr: true},
+		{name: "string literal", expression: `"a"`, variables: nil, wantErr: true},
+		{name: "invalid variable name", expression: "1", variables: []string{"a/b"}, wantErr: true},
+		{name: "duplicate variable", expression: "a", variables: []string{"a", "a"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewExpressionNode(pair, tt.expression, tt.variables)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExpressionNode_Tick(t *testing.T) {
+	pair := provider.Pair{Base: "A", Quote: "B"}
+	now := time.Now()
+	tests := []struct {
+		name          string
+		expression    string
+		variables     []string
+		ticks         []provider.Tick
+		expectedPrice float64
+		expectedTime  time.Time
+		wantErr       bool
+	}{
+		{
+			name:       "product with constant",
+			expression: "a * b * 1.5",
+			variables:  []string{"a", "b"},
+			ticks: []provider.Tick{
+				{Price: bn.Float(2), Pair: provider.Pair{Base: "A", Quote: "C"}, Time: now},
+				{Price: bn.Float(3), Pair: provider.Pair{Base: "C", Quote: "B"}, Time: now.Add(-time.Minute)},
+			},
+			expectedPrice: 9,
+			expectedTime:  now.Add(-time.Minute),
+		},
+		{
+			name:          "fixed peg",
+			expression:    "1",
+			expectedPrice: 1,
+		},
+		{
+			name:       "quotient",
+			expression: "(a - 1) / b",
+			variables:  []string{"a", "b"},
+			ticks: []provider.Tick{
+				{Price: bn.Float(5), Pair: pair, Time: now},
+				{Price: bn.Float(2), Pair: pair, Time: now},
+			},
+			expectedPrice: 2,
+			expectedTime:  now,
+		},
+		{
+			name:       "division by zero",
+			expression: "a / (b - 2)",
+			variables:  []string{"a", "b"},
+			ticks: []provider.Tick{
+				{Price: bn.Float(5), Pair: pair, Time: now},
+				{Price: bn.Float(2), Pair: pair, Time: now},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "invalid branch",
+			expression: "a * b",
+			variables:  []string{"a", "b"},
+			ticks: []provider.Tick{
+				{Price: bn.Float(5), Pair: pair, Time: now},
+				{Pair: pair, Time: now, Error: errors.New("err")},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "missing branch",
+			expression: "a * b",
+			variables:  []string{"a", "b"},
+			ticks: []provider.Tick{
+				{Price: bn.Float(5), Pair: pair, Time: now},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := NewExpressionNode(pair, tt.expression, tt.variables)
+			require.NoError(t, err)
+			for _, tick := range tt.ticks {
+				n := new(mockNode)
+				n.On("Tick").Return(tick)
+				require.NoError(t, node.AddBranch(n))
+			}
+			tick := node.Tick()
+			assert.Equal(t, "expression", tick.Meta.Meta()["type"])
+			if tt.wantErr {
+				assert.Error(t, tick.Error)
+				return
+			}
+			require.NoError(t, tick.Validate())
+			assert.Equal(t, pair, tick.Pair)
+			assert.Equal(t, tt.expectedPrice, tick.Price.Float64())
+			if !tt.expectedTime.IsZero() {
+				assert.Equal(t, tt.expectedTime, tick.Time)
+			}
+		})
+	}
+}
+
+func TestExpressionNode_AddBranch(t *testing.T) {
+	node, err := NewExpressionNode(provider.Pair{Base: "A", Quote: "B"}, "a", []string{"a"})
+	require.NoError(t, err)
+	require.NoError(t, node.AddBranch(new(mockNode)))
+	assert.Error(t, node.AddBranch(new(mockNode)))
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+)
+
+// FallbackNode is a node that returns the first valid tick from its
+// branches, evaluated in the order in which they were added.
+//
+// The index of the chosen branch is stored in the tick meta under the
+// "branch" key, and errors from skipped branches are stored under the
+// "skipped" key.
+type FallbackNode struct {
+	pair     provider.Pair
+	branches []Node
+}
+
+// NewFallbackNode creates a new FallbackNode instance.
+func NewFallbackNode(pair provider.Pair) *FallbackNode {
+	return &FallbackNode{pair: pair}
+}
+
+// AddBranch implements the Node interface.
+//
+// Branches are evaluated in the order in which they were added. All branches
+// must have the same pair as the node.
+func (n *FallbackNode) AddBranch(branch ...Node) error {
+	for _, b := range branch {
+		if !b.Pair().Equal(n.pair) {
+			return fmt.Errorf("expected pair %s, got %s", n.pair, b.Pair())
+		}
+	}
+	n.branches = append(n.branches, branch...)
+	return nil
+}
+
+// Branches implements the Node interface.
+func (n *FallbackNode) Branches() []Node {
+	return n.branches
+}
+
+// Pair implements the Node interface.
+func (n *FallbackNode) Pair() provider.Pair {
+	return n.pair
+}
+
+// Tick implements the Node interface.
+func (n *FallbackNode) Tick() provider.Tick {
+	var (
+		ticks   []provider.Tick
+		skipped []MapMeta
+	)
+	meta := n.Meta().(MapMeta)
+	for i, branch := range n.branches {
+		tick := branch.Tick()
+		ticks = append(ticks, tick)
+		if err := tick.Validate(); err != nil {
+			skipped = append(skipped, MapMeta{"index": i, "error": err.Error()})
+			continue
+		}
+		meta["branch"] = i
+		if len(skipped) > 0 {
+			meta["skipped"] = skipped
+		}
+		return provider.Tick{
+			Pair:      n.pair,
+			Price:     tick.Price,
+			Volume24h: tick.Volume24h,
+			Time:      tick.Time,
+			SubTicks:  ticks,
+			Meta:      meta,
+		}
+	}
+	if len(skipped) > 0 {
+		meta["skipped"] = skipped
+	}
+	return provider.Tick{
+		Pair:     n.pair,
+		SubTicks: ticks,
+		Meta:     meta,
+		Error:    fmt.Errorf("no valid tick from any branch"),
+	}
+}
+
+// Meta implements the Node interface.
+func (n *FallbackNode) Meta() provider.Meta {
+	return MapMeta{"type": "fallback"}
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+func TestFallbackNode_AddBranch(t *testing.T) {
+	btcusd := new(mockNode)
+	btcusd.On("Pair").Return(provider.Pair{Base: "BTC", Quote: "USD"})
+	ethusd := new(mockNode)
+	ethusd.On("Pair").Return(provider.Pair{Base: "ETH", Quote: "USD"})
+
+	node := NewFallbackNode(provider.Pair{Base: "BTC", Quote: "USD"})
+	require.NoError(t, node.AddBranch(btcusd, btcusd))
+	assert.Len(t, node.Branches(), 2)
+	assert.Error(t, node.AddBranch(ethusd))
+}
+
+func TestFallbackNode_Tick(t *testing.T) {
+	pair := provider.Pair{Base: "A", Quote: "B"}
+	tests := []struct {
+		name           string
+		ticks          []provider.Tick
+		expectedPrice  float64
+		expectedBranch int
+		expectedSkip   int
+		wantErr        bool
+	}{
+		{
+			name: "first branch valid",
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Pair: pair, Time: time.Now()},
+			},
+			expectedPrice:  1,
+			expectedBranch: 0,
+		},
+		{
+			name: "first branch invalid",
+			ticks: []provider.Tick{
+				{Pair: pair, Time: time.Now(), Error: errors.New("stale")},
+				{Price: bn.Float(2), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(3), Pair: pair, Time: time.Now()},
+			},
+			expectedPrice:  2,
+			expectedBranch: 1,
+			expectedSkip:   1,
+		},
+		{
+			name: "all branches invalid",
+			ticks: []provider.Tick{
+				{Pair: pair, Time: time.Now(), Error: errors.New("stale")},
+				{Pair: pair, Time: time.Now(), Error: errors.New("stale")},
+			},
+			expectedSkip: 2,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := NewFallbackNode(pair)
+			for _, tick := range tt.ticks {
+				n := new(mockNode)
+				n.On("Pair").Return(pair)
+				n.On("Tick").Return(tick)
+				require.NoError(t, node.AddBranch(n))
+			}
+			tick := node.Tick()
+			meta := tick.Meta.Meta()
+			assert.Equal(t, "fallback", meta["type"])
+			if tt.wantErr {
+				assert.Error(t, tick.Validate())
+				assert.NotContains(t, meta, "branch")
+			} else {
+				require.NoError(t, tick.Validate())
+				assert.Equal(t, tt.expectedPrice, tick.Price.Float64())
+				assert.Equal(t, tt.expectedBranch, meta["branch"])
+				assert.Len(t, tick.SubTicks, tt.expectedBranch+1)
+			}
+			if tt.expectedSkip == 0 {
+				assert.NotContains(t, meta, "skipped")
+			} else {
+				assert.Len(t, meta["skipped"], tt.expectedSkip)
+			}
+		})
+	}
+}
@@ -66,6 +66,23 @@ func Walk(fn func(Node), nodes ...Node) {
 	}
 }
 
+// AttachObservers registers every node in the given graphs that implements
+// the TickObserver interface as an observer of the origin nodes in its
+// branches. Graphs must not contain cycles.
+func AttachObservers(nodes ...Node) {
+	Walk(func(n Node) {
+		o, ok := n.(TickObserver)
+		if !ok {
+			return
+		}
+		Walk(func(b Node) {
+			if originNode, ok := b.(*OriginNode); ok {
+				originNode.AddObserver(o)
+			}
+		}, n.Branches()...)
+	}, nodes...)
+}
+
 // DetectCycle returns a cycle path in the given graph if a cycle is detected,
 // otherwise returns an empty slice.
 func DetectCycle(node Node) []Node {
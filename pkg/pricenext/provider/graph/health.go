@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+)
+
+const (
+	// quarantineThreshold is the number of consecutive failed fetches after
+	// which an origin is quarantined.
+	quarantineThreshold = 3
+
+	// minQuarantine is the duration of the first quarantine. It is doubled
+	// after every failed fetch, up to maxQuarantine.
+	minQuarantine = 10 * time.Second
+
+	// maxQuarantine is the maximum duration of a quarantine.
+	maxQuarantine = 10 * time.Minute
+
+	// latencyWeight is the weight of the most recent fetch used to calculate
+	// the moving average of the latency.
+	latencyWeight = 0.2
+)
+
+var errNoValidTicks = errors.New("origin did not return any valid tick")
+
+// HealthReporter reports the health of origins.
+type HealthReporter interface {
+	// OriginHealth returns the health of all origins that were used at least
+	// once, indexed by origin name.
+	OriginHealth() map[string]OriginHealth
+}
+
+// OriginHealth describes the health of an origin.
+type OriginHealth struct {
+	// Requests is the total number of fetches from the origin.
+	Requests uint64
+
+	// Failures is the total number of failed fetches. A fetch is considered
+	// failed if the origin did not return any valid tick.
+	Failures uint64
+
+	// ConsecutiveFailures is the number of failed fetches since the last
+	// successful one.
+	ConsecutiveFailures int
+
+	// Latency is the exponential moving average of the fetch duration.
+	Latency time.Duration
+
+	// LastError is the last error returned by the origin.
+	LastError string
+
+	// QuarantinedUntil is the time until which the origin is not used.
+	QuarantinedUntil time.Time
+}
+
+// SuccessRate returns the ratio of successful fetches to all fetches.
+func (h OriginHealth) SuccessRate() float64 {
+	if h.Requests == 0 {
+		return 1
+	}
+	return float64(h.Requests-h.Failures) / float64(h.Requests)
+}
+
+// Quarantined returns true if the origin is quarantined at the given time.
+func (h OriginHealth) Quarantined(now time.Time) bool {
+	return now.Before(h.QuarantinedUntil)
+}
+
+// Meta returns the health as a metadata map.
+func (h OriginHealth) Meta() MapMeta {
+	meta := MapMeta{
+		"requests":             h.Requests,
+		"failures":             h.Failures,
+		"consecutive_failures": h.ConsecutiveFailures,
+		"success_rate":         h.SuccessRate(),
+		"latency":              h.Latency.String(),
+		"quarantined":          h.Quarantined(time.Now()),
+	}
+	if h.LastError != "" {
+		meta["last_error"] = h.LastError
+	}
+	if !h.QuarantinedUntil.IsZero() {
+		meta["quarantined_until"] = h.QuarantinedUntil
+	}
+	return meta
+}
+
+// healthTracker tracks the health of origins and decides which of them
+// should be quarantined.
+type healthTracker struct {
+	mu     sync.Mutex
+	health map[string]*OriginHealth
+	logger log.Logger
+
+	threshold     int
+	minQuarantine time.Duration
+	maxQuarantine time.Duration
+}
+
+func newHealthTracker(logger log.Logger) *healthTracker {
+	return &healthTracker{
+		health:        make(map[string]*OriginHealth),
+		logger:        logger,
+		threshold:     quarantineThreshold,
+		minQuarantine: minQuarantine,
+		maxQuarantine: maxQuarantine,
+	}
+}
+
+// quarantined returns true if the origin must not be used at the given time.
+func (t *healthTracker) quarantined(origin string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.health[origin]
+	return ok && h.Quarantined(now)
+}
+
+// record updates the origin health using the result of a fetch.
+func (t *healthTracker) record(origin string, now time.Time, latency time.Duration, ticks []provider.Tick, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.health[origin]
+	if !ok {
+		h = &OriginHealth{Latency: latency}
+		t.health[origin] = h
+	}
+	h.Requests++
+	h.Latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(h.Latency))
+
+	// A fetch is successful if at least one tick is valid.
+	if err == nil {
+		err = errNoValidTicks
+		for _, tick := range ticks {
+			if tick.Error == nil {
+				err = nil
+				break
+			}
+			err = tick.Error
+		}
+	}
+	if err == nil {
+		if !h.QuarantinedUntil.IsZero() {
+			t.logger.
+				WithField("origin", origin).
+				Info("Origin recovered, quarantine lifted")
+		}
+		h.ConsecutiveFailures = 0
+		h.QuarantinedUntil = time.Time{}
+		return
+	}
+
+	h.Failures++
+	h.ConsecutiveFailures++
+	h.LastError = err.Error()
+	if h.ConsecutiveFailures < t.threshold {
+		return
+	}
+	quarantine := t.minQuarantine
+	for i := t.threshold; i < h.ConsecutiveFailures && quarantine < t.maxQuarantine; i++ {
+		quarantine *= 2
+	}
+	if quarantine > t.maxQuarantine {
+		quarantine = t.maxQuarantine
+	}
+	h.QuarantinedUntil = now.Add(quarantine)
+	t.logger.
+		WithError(err).
+		WithFields(log.Fields{
+			"origin":              origin,
+			"consecutiveFailures": h.ConsecutiveFailures,
+			"quarantinedUntil":    h.QuarantinedUntil,
+			"quarantineDuration":  quarantine,
+			"successRate":         h.SuccessRate(),
+			"averageLatency":      h.Latency,
+		}).
+		Warn("Origin quarantined")
+}
+
+// snapshot returns a copy of the health of all origins.
+func (t *healthTracker) snapshot() map[string]OriginHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	health := make(map[string]OriginHealth, len(t.health))
+	for origin, h := range t.health {
+		health[origin] = *h
+	}
+	return health
+}
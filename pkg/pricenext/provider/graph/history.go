@@ -0,0 +1,486 @@
+package graph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+// compactFactor determines how many records may be appended to the history
+// file before it is compacted, relative to the number of records kept in
+// memory.
+const compactFactor = 2
+
+// TickHistory stores recent ticks returned by origins and price models.
+//
+// For every origin and pair, and for every price model, the history keeps
+// up to size most recent ticks in a ring buffer. Only the pair, price,
+// volume, time and error of each tick are stored, sub ticks and metadata
+// are discarded.
+//
+// If a path is provided, ticks are also appended to a file, so the history
+// survives restarts. The file is loaded when the history is created and is
+// periodically compacted to keep only records that are held in memory.
+// Compaction runs in the background, so recording ticks is not blocked while
+// the file is rewritten.
+//
+// TickHistory implements the supervisor.Service interface. The file is
+// closed when the context passed to Start is canceled. Ticks recorded after
+// that are kept only in memory.
+type TickHistory struct {
+	mu      sync.RWMutex
+	ctx     context.Context
+	waitCh  chan error
+	size    int
+	origins map[originPairKey]*tickRing
+	models  map[string]*tickRing
+
+	path       string
+	file       *os.File
+	appended   int
+	compacting bool           // compaction is running in the background
+	pending    [][]byte       // records appended during the compaction
+	compactWg  sync.WaitGroup // in-flight compaction
+}
+
+// historyRecord is a single tick stored in the history file.
+type historyRecord struct {
+	Origin string    `json:"origin,omitempty"`
+	Model  string    `json:"model,omitempty"`
+	Pair   string    `json:"pair"`
+	Price  string    `json:"price,omitempty"`
+	Volume string    `json:"volume,omitempty"`
+	Time   time.Time `json:"time"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// NewTickHistory creates a new TickHistory instance.
+//
+// The size argument is the maximum number of ticks kept for every origin
+// and pair and for every model. If path is not empty, ticks are persisted
+// in the file at the given path.
+func NewTickHistory(size int, path string) (*TickHistory, error) {
+	if size <= 0 {
+		return nil, errors.New("history size must be positive")
+	}
+	h := &TickHistory{
+		waitCh:  make(chan error),
+		size:    size,
+		origins: make(map[originPairKey]*tickRing),
+		models:  make(map[string]*tickRing),
+		path:    path,
+	}
+	if path != "" {
+		if err := h.load(); err != nil {
+			return nil, fmt.Errorf("unable to load tick history: %w", err)
+		}
+		tmp, err := h.writeRecords(h.records())
+		if err != nil {
+			return nil, fmt.Errorf("unable to open tick history: %w", err)
+		}
+		if err := h.replaceFile(tmp, nil); err != nil {
+			return nil, fmt.Errorf("unable to open tick history: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// RecordOrigin adds a tick returned by an origin to the history.
+func (h *TickHistory) RecordOrigin(origin string, tick provider.Tick) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	tick = historyTick(tick)
+	key := originPairKey{origin: origin, pair: tick.Pair}
+	h.originRing(key).add(tick)
+	h.persist(historyRecordFromTick(tick, origin, ""))
+}
+
+// RecordModel adds a tick calculated by a price model to the history.
+func (h *TickHistory) RecordModel(model string, tick provider.Tick) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	tick = historyTick(tick)
+	h.modelRing(model).add(tick)
+	h.persist(historyRecordFromTick(tick, "", model))
+}
+
+// OriginTick returns the most recent valid tick returned by the origin for
+// the given pair that is not newer than the given time.
+func (h *TickHistory) OriginTick(origin string, pair provider.Pair, at time.Time) (provider.Tick, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var (
+		found provider.Tick
+		ok    bool
+	)
+	if r, exists := h.origins[originPairKey{origin: origin, pair: pair}]; exists {
+		for _, tick := range r.ticks() {
+			if tick.Validate() != nil || tick.Time.After(at) {
+				continue
+			}
+			if !ok || tick.Time.After(found.Time) {
+				found, ok = tick, true
+			}
+		}
+	}
+	return found, ok
+}
+
+// ModelTick returns the most recent tick calculated by the price model that
+// is not newer than the given time. Unlike OriginTick, invalid ticks are
+// not skipped, because they are what the model returned at that time.
+func (h *TickHistory) ModelTick(model string, at time.Time) (provider.Tick, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var (
+		found provider.Tick
+		ok    bool
+	)
+	if r, exists := h.models[model]; exists {
+		for _, tick := range r.ticks() {
+			if tick.Time.After(at) {
+				continue
+			}
+			if !ok || !tick.Time.Before(found.Time) {
+				found, ok = tick, true
+			}
+		}
+	}
+	return found, ok
+}
+
+// OriginTicks returns ticks returned by the origin for the given pair
+// between the given times, inclusive, ordered by time.
+func (h *TickHistory) OriginTicks(origin string, pair provider.Pair, from, to time.Time) []provider.Tick {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return ticksBetween(h.origins[originPairKey{origin: origin, pair: pair}], from, to)
+}
+
+// ModelTicks returns ticks calculated by the price model between the given
+// times, inclusive, ordered by time.
+func (h *TickHistory) ModelTicks(model string, from, to time.Time) []provider.Tick {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return ticksBetween(h.models[model], from, to)
+}
+
+// Start implements the supervisor.Service interface.
+func (h *TickHistory) Start(ctx context.Context) error {
+	if h.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	h.ctx = ctx
+	go h.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (h *TickHistory) Wait() <-chan error {
+	return h.waitCh
+}
+
+// Close closes the history file and waits for the compaction in progress,
+// if any, to stop. It is called automatically when the service is stopped.
+func (h *TickHistory) Close() error {
+	h.mu.Lock()
+	var err error
+	if h.file != nil {
+		err = h.file.Close()
+		h.file = nil
+	}
+	h.mu.Unlock()
+	h.compactWg.Wait()
+	return err
+}
+
+// contextCancelHandler closes the history file when the context is canceled.
+func (h *TickHistory) contextCancelHandler() {
+	defer close(h.waitCh)
+	<-h.ctx.Done()
+	if err := h.Close(); err != nil {
+		h.waitCh <- err
+	}
+}
+
+func (h *TickHistory) originRing(key originPairKey) *tickRing {
+	if h.origins[key] == nil {
+		h.origins[key] = newTickRing(h.size)
+	}
+	return h.origins[key]
+}
+
+func (h *TickHistory) modelRing(model string) *tickRing {
+	if h.models[model] == nil {
+		h.models[model] = newTickRing(h.size)
+	}
+	return h.models[model]
+}
+
+// persist appends the record to the history file. Must be called with the
+// mutex locked.
+func (h *TickHistory) persist(rec historyRecord) {
+	if h.file == nil {
+		return
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	if _, err := h.file.Write(b); err != nil {
+		return
+	}
+	if h.compacting {
+		h.pending = append(h.pending, b)
+		return
+	}
+	h.appended++
+	if h.appended > compactFactor*h.size*(len(h.origins)+len(h.models)) {
+		h.compacting = true
+		h.compactWg.Add(1)
+		go h.compact(h.records())
+	}
+}
+
+// load reads ticks from the history file.
+func (h *TickHistory) load() error {
+	f, err := os.Open(h.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var rec historyRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// Skip corrupted records, e.g. a partially written last line.
+			continue
+		}
+		tick, err := rec.tick()
+		if err != nil {
+			continue
+		}
+		if rec.Model != "" {
+			h.modelRing(rec.Model).add(tick)
+		} else {
+			h.originRing(originPairKey{origin: rec.Origin, pair: tick.Pair}).add(tick)
+		}
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the history file so it contains only the given records
+// and the records appended in the meantime. Records are appended to the
+// current file until it is replaced, so nothing is lost if the compaction
+// fails.
+func (h *TickHistory) compact(records []historyRecord) {
+	defer h.compactWg.Done()
+	tmp, err := h.writeRecords(records)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	pending := h.pending
+	h.compacting = false
+	h.pending = nil
+	h.appended = 0
+	if err != nil {
+		return
+	}
+	if h.file == nil {
+		// The history was closed during the compaction.
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	_ = h.replaceFile(tmp, pending)
+}
+
+// records returns records for all ticks held in memory. Must be called with
+// the mutex locked.
+func (h *TickHistory) records() []historyRecord {
+	var records []historyRecord
+	for key, r := range h.origins {
+		for _, tick := range r.ticks() {
+			records = append(records, historyRecordFromTick(tick, key.origin, ""))
+		}
+	}
+	for model, r := range h.models {
+		for _, tick := range r.ticks() {
+			records = append(records, historyRecordFromTick(tick, "", model))
+		}
+	}
+	return records
+}
+
+// writeRecords writes the records to a new temporary file in the directory
+// of the history file and returns the file, which is left open.
+func (h *TickHistory) writeRecords(records []historyRecord) (*os.File, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return nil, err
+	}
+	return tmp, nil
+}
+
+// replaceFile appends the pending records to the temporary file, replaces
+// the history file with it and reopens it for appending. Must be called
+// with the mutex locked.
+func (h *TickHistory) replaceFile(tmp *os.File, pending [][]byte) error {
+	for _, b := range pending {
+		if _, err := tmp.Write(b); err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), h.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if h.file != nil {
+		_ = h.file.Close()
+		h.file = nil
+	}
+	var err error
+	h.file, err = os.OpenFile(h.path, os.O_APPEND|os.O_WRONLY, 0o644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	h.appended = len(pending)
+	return nil
+}
+
+// historyTick returns a copy of the tick without sub ticks and metadata.
+// If the tick time is not set, the current time is used.
+func historyTick(tick provider.Tick) provider.Tick {
+	t := provider.Tick{
+		Pair:      tick.Pair,
+		Price:     tick.Price,
+		Volume24h: tick.Volume24h,
+		Time:      tick.Time,
+		Error:     tick.Error,
+	}
+	if t.Time.IsZero() {
+		t.Time = time.Now()
+	}
+	return t
+}
+
+func historyRecordFromTick(tick provider.Tick, origin, model string) historyRecord {
+	rec := historyRecord{
+		Origin: origin,
+		Model:  model,
+		Pair:   tick.Pair.String(),
+		Time:   tick.Time,
+	}
+	if tick.Price != nil {
+		rec.Price = tick.Price.String()
+	}
+	if tick.Volume24h != nil {
+		rec.Volume = tick.Volume24h.String()
+	}
+	if tick.Error != nil {
+		rec.Error = tick.Error.Error()
+	}
+	return rec
+}
+
+func (r historyRecord) tick() (provider.Tick, error) {
+	pair, err := provider.PairFromString(r.Pair)
+	if err != nil {
+		return provider.Tick{}, err
+	}
+	tick := provider.Tick{Pair: pair, Time: r.Time}
+	if r.Price != "" {
+		tick.Price = bn.Float(r.Price)
+	}
+	if r.Volume != "" {
+		tick.Volume24h = bn.Float(r.Volume)
+	}
+	if r.Error != "" {
+		tick.Error = errors.New(r.Error)
+	}
+	return tick, nil
+}
+
+func ticksBetween(r *tickRing, from, to time.Time) []provider.Tick {
+	if r == nil {
+		return nil
+	}
+	var ticks []provider.Tick
+	for _, tick := range r.ticks() {
+		if tick.Time.Before(from) || tick.Time.After(to) {
+			continue
+		}
+		ticks = append(ticks, tick)
+	}
+	sort.SliceStable(ticks, func(i, j int) bool {
+		return ticks[i].Time.Before(ticks[j].Time)
+	})
+	return ticks
+}
+
+// tickRing is a fixed-size ring buffer of ticks.
+type tickRing struct {
+	buf  []provider.Tick
+	next int
+}
+
+func newTickRing(size int) *tickRing {
+	return &tickRing{buf: make([]provider.Tick, 0, size)}
+}
+
+// add adds the tick to the buffer, overwriting the oldest one if the buffer
+// is full.
+func (r *tickRing) add(tick provider.Tick) {
+	if len(r.buf) < cap(r.buf) {
+		r.buf = append(r.buf, tick)
+		return
+	}
+	r.buf[r.next] = tick
+	r.next = (r.next + 1) % len(r.buf)
+}
+
+// ticks returns ticks in the order in which they were added.
+func (r *tickRing) ticks() []provider.Tick {
+	ticks := make([]provider.Tick, 0, len(r.buf))
+	ticks = append(ticks, r.buf[r.next:]...)
+	ticks = append(ticks, r.buf[:r.next]...)
+	return ticks
+}
@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+func TestTickHistory(t *testing.T) {
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	h, err := NewTickHistory(3, "")
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		h.RecordOrigin("a", provider.Tick{
+			Pair:  pair,
+			Price: bn.Float(i),
+			Time:  t0.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	h.RecordOrigin("a", provider.Tick{
+		Pair:  pair,
+		Time:  t0.Add(5 * time.Minute),
+		Error: errors.New("error"),
+	})
+
+	// Only the last 3 ticks are kept.
+	ticks := h.OriginTicks("a", pair, t0, t0.Add(time.Hour))
+	require.Len(t, ticks, 3)
+	assert.Equal(t, "3", ticks[0].Price.String())
+	assert.Equal(t, "4", ticks[1].Price.String())
+	assert.Error(t, ticks[2].Error)
+
+	// Invalid ticks are skipped.
+	tick, ok := h.OriginTick("a", pair, t0.Add(time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, "4", tick.Price.String())
+
+	tick, ok = h.OriginTick("a", pair, t0.Add(3*time.Minute+time.Second))
+	require.True(t, ok)
+	assert.Equal(t, "3", tick.Price.String())
+
+	_, ok = h.OriginTick("a", pair, t0)
+	assert.False(t, ok)
+	_, ok = h.OriginTick("b", pair, t0.Add(time.Hour))
+	assert.False(t, ok)
+
+	_, err = NewTickHistory(0, "")
+	assert.Error(t, err)
+}
+
+func TestTickHistory_Persistence(t *testing.T) {
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	h, err := NewTickHistory(2, path)
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		h.RecordOrigin("a", provider.Tick{
+			Pair:      pair,
+			Price:     bn.Float(i),
+			Volume24h: bn.Float(i * 10),
+			Time:      t0.Add(time.Duration(i) * time.Minute),
+		})
+		h.RecordModel("model", provider.Tick{
+			Pair:  pair,
+			Price: bn.Float(i),
+			Time:  t0.Add(time.Duration(i) * time.Minute),
+		})
+
+		// Compaction runs in the background.
+		h.compactWg.Wait()
+	}
+	require.NoError(t, h.Close())
+
+	// The file must be compacted.
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Less(t, len(b), 20*100)
+
+	h, err = NewTickHistory(2, path)
+	require.NoError(t, err)
+	defer h.Close()
+
+	ticks := h.OriginTicks("a", pair, t0, t0.Add(time.Hour))
+	require.Len(t, ticks, 2)
+	assert.Equal(t, "18", ticks[0].Price.String())
+	assert.Equal(t, "190", ticks[1].Volume24h.String())
+	assert.True(t, t0.Add(19*time.Minute).Equal(ticks[1].Time))
+
+	ticks = h.ModelTicks("model", t0, t0.Add(time.Hour))
+	require.Len(t, ticks, 2)
+	assert.Equal(t, "19", ticks[1].Price.String())
+}
+
+func TestTickHistory_CompactionPending(t *testing.T) {
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	h, err := NewTickHistory(2, path)
+	require.NoError(t, err)
+	h.RecordOrigin("a", provider.Tick{Pair: pair, Price: bn.Float(1), Time: t0})
+
+	// Start the compaction manually, so a tick can be recorded while it is
+	// in progress.
+	h.mu.Lock()
+	h.compacting = true
+	h.compactWg.Add(1)
+	records := h.records()
+	h.mu.Unlock()
+	h.RecordOrigin("a", provider.Tick{Pair: pair, Price: bn.Float(2), Time: t0.Add(time.Minute)})
+	h.compact(records)
+	require.NoError(t, h.Close())
+
+	// The tick recorded during the compaction must not be lost.
+	h, err = NewTickHistory(2, path)
+	require.NoError(t, err)
+	defer h.Close()
+	ticks := h.OriginTicks("a", pair, t0, t0.Add(time.Hour))
+	require.Len(t, ticks, 2)
+	assert.Equal(t, "2", ticks[1].Price.String())
+}
+
+func TestTickHistory_Service(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	h, err := NewTickHistory(2, path)
+	require.NoError(t, err)
+	require.NoError(t, h.Start(ctx))
+	require.Error(t, h.Start(ctx))
+	h.RecordOrigin("a", provider.Tick{Pair: pair, Price: bn.Float(1), Time: time.Now()})
+
+	// The file must be closed after the context is canceled.
+	ctxCancel()
+	require.NoError(t, <-h.Wait())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	h.RecordOrigin("a", provider.Tick{Pair: pair, Price: bn.Float(2), Time: time.Now()})
+	info2, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), info2.Size())
+	assert.Len(t, h.OriginTicks("a", pair, time.Time{}, time.Now()), 2)
+}
@@ -91,6 +91,21 @@ func (n *IndirectNode) Meta() provider.Meta {
 	return MapMeta{"type": "indirect"}
 }
 
+// IndirectPair returns the pair to which the cross rate is resolved for
+// branches with the given pairs. It returns an error if adjacent pairs do not
+// have a common asset.
+func IndirectPair(pairs []provider.Pair) (provider.Pair, error) {
+	ticks := make([]provider.Tick, len(pairs))
+	for i, pair := range pairs {
+		ticks[i] = provider.Tick{Pair: pair, Price: bn.Float(1)}
+	}
+	tick, err := crossRate(ticks)
+	if err != nil {
+		return provider.Pair{}, err
+	}
+	return tick.Pair, nil
+}
+
 // crossRate returns a calculated price from the list of prices. Prices order
 // is important because prices are calculated from first to last.
 func crossRate(t []provider.Tick) (provider.Tick, error) {
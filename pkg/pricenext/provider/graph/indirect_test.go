@@ -93,3 +93,19 @@ func TestIndirectNode(t *testing.T) {
 		})
 	}
 }
+
+func TestIndirectPair(t *testing.T) {
+	pair, err := IndirectPair([]provider.Pair{
+		{Base: "A", Quote: "B"},
+		{Base: "C", Quote: "B"},
+		{Base: "C", Quote: "D"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, provider.Pair{Base: "A", Quote: "D"}, pair)
+
+	_, err = IndirectPair([]provider.Pair{
+		{Base: "A", Quote: "B"},
+		{Base: "C", Quote: "D"},
+	})
+	assert.Error(t, err)
+}
@@ -9,11 +9,61 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
 )
 
+// OutlierRejection describes how MedianNode rejects outliers before
+// calculating the median.
+type OutlierRejection struct {
+	// Method is the method used to detect outliers.
+	Method OutlierRejectionMethod
+
+	// Threshold is the maximum allowed deviation from the median. Its
+	// meaning depends on the method.
+	Threshold float64
+}
+
+// minRelativeMAD is the minimum median absolute deviation, relative to the
+// median, used by MADOutlierRejection. Without it, the deviation is zero
+// when more than half of the prices are equal, and the limit cannot be
+// calculated.
+const minRelativeMAD = 0.001
+
+// OutlierRejectionMethod is a method used to detect outliers.
+type OutlierRejectionMethod int
+
+const (
+	// NoOutlierRejection disables outlier rejection.
+	NoOutlierRejection OutlierRejectionMethod = iota
+
+	// MADOutlierRejection rejects prices that deviate from the median by
+	// more than Threshold median absolute deviations. The median absolute
+	// deviation is at least minRelativeMAD of the median, so a single outlier
+	// is rejected even if most of the prices are equal.
+	MADOutlierRejection
+
+	// PercentOutlierRejection rejects prices that deviate from the median
+	// by more than Threshold percent.
+	PercentOutlierRejection
+)
+
+// String returns the name of the method.
+func (m OutlierRejectionMethod) String() string {
+	switch m {
+	case NoOutlierRejection:
+		return "none"
+	case MADOutlierRejection:
+		return "mad"
+	case PercentOutlierRejection:
+		return "percent"
+	default:
+		return "unknown"
+	}
+}
+
 // MedianNode is a node that calculates median price from its
 // branches.
 type MedianNode struct {
 	pair     provider.Pair
 	min      int
+	outliers OutlierRejection
 	branches []Node
 }
 
@@ -28,6 +78,20 @@ func NewMedianNode(pair provider.Pair, min int) *MedianNode {
 	}
 }
 
+// NewMedianNodeWithOutlierRejection creates a new MedianNode instance that
+// rejects outliers before calculating the median.
+//
+// Prices are compared against the median of all valid prices. Rejected
+// prices are listed in the tick's meta under the "outliers" key, and they
+// are not counted towards the minimum number of prices.
+func NewMedianNodeWithOutlierRejection(pair provider.Pair, min int, outliers OutlierRejection) *MedianNode {
+	return &MedianNode{
+		pair:     pair,
+		min:      min,
+		outliers: outliers,
+	}
+}
+
 // AddBranch implements the Node interface.
 func (n *MedianNode) AddBranch(branch ...Node) error {
 	n.branches = append(n.branches, branch...)
@@ -47,12 +111,13 @@ func (n *MedianNode) Pair() provider.Pair {
 // Tick implements the Node interface.
 func (n *MedianNode) Tick() provider.Tick {
 	var (
-		tm     time.Time
-		ticks  []provider.Tick
-		prices []*bn.FloatNumber
+		tm      time.Time
+		ticks   []provider.Tick
+		prices  []*bn.FloatNumber
+		indices []int // indices of ticks from which prices were taken
 	)
 
-	meta := n.Meta()
+	meta := n.Meta().(MapMeta)
 
 	// Collect all ticks from branches and prices from ticks
 	// that can be used to calculate median.
@@ -72,6 +137,16 @@ func (n *MedianNode) Tick() provider.Tick {
 			continue
 		}
 		prices = append(prices, tick.Price)
+		indices = append(indices, len(ticks)-1)
+	}
+
+	// Reject outliers.
+	if n.outliers.Method != NoOutlierRejection && len(prices) > 0 {
+		var outliers []MapMeta
+		prices, outliers = rejectOutliers(n.outliers, prices, indices)
+		if len(outliers) > 0 {
+			meta["outliers"] = outliers
+		}
 	}
 
 	// Verify that we have enough valid prices to calculate median.
@@ -96,7 +171,60 @@ func (n *MedianNode) Tick() provider.Tick {
 
 // Meta implements the Node interface.
 func (n *MedianNode) Meta() provider.Meta {
-	return MapMeta{"type": "median", "min_sources": n.min}
+	meta := MapMeta{"type": "median", "min_sources": n.min}
+	if n.outliers.Method != NoOutlierRejection {
+		meta["outlier_method"] = n.outliers.Method.String()
+		meta["outlier_threshold"] = n.outliers.Threshold
+	}
+	return meta
+}
+
+// rejectOutliers returns prices that are not outliers and a description of
+// rejected ones. The indices argument contains the sub tick index of every
+// price.
+func rejectOutliers(
+	o OutlierRejection,
+	prices []*bn.FloatNumber,
+	indices []int,
+) ([]*bn.FloatNumber, []MapMeta) {
+
+	m := median(append([]*bn.FloatNumber(nil), prices...))
+	deviations := make([]*bn.FloatNumber, len(prices))
+	for i, p := range prices {
+		deviations[i] = p.Sub(m).Abs()
+	}
+
+	// Calculate the maximum allowed absolute deviation from the median.
+	var limit *bn.FloatNumber
+	switch o.Method {
+	case MADOutlierRejection:
+		mad := median(append([]*bn.FloatNumber(nil), deviations...))
+		if minMAD := m.Abs().Mul(minRelativeMAD); mad.Cmp(minMAD) < 0 {
+			mad = minMAD
+		}
+		limit = mad.Mul(o.Threshold)
+	case PercentOutlierRejection:
+		limit = m.Abs().Mul(o.Threshold / 100)
+	default:
+		return prices, nil
+	}
+
+	var (
+		accepted []*bn.FloatNumber
+		rejected []MapMeta
+	)
+	for i, p := range prices {
+		if deviations[i].Cmp(limit) > 0 {
+			rejected = append(rejected, MapMeta{
+				"index":  indices[i],
+				"price":  p.String(),
+				"reason": fmt.Sprintf("deviation %s from median %s exceeds %s", deviations[i], m, limit),
+			})
+			continue
+		}
+		accepted = append(accepted, p)
+	}
+	return accepted, rejected
 }
 
 func median(xs []*bn.FloatNumber) *bn.FloatNumber {
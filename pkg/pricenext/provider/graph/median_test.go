@@ -79,3 +79,94 @@ func TestMedianNode(t *testing.T) {
 		})
 	}
 }
+
+func TestMedianNode_OutlierRejection(t *testing.T) {
+	pair := provider.Pair{Base: "A", Quote: "B"}
+	tests := []struct {
+		name             string
+		prices           []float64
+		outliers         OutlierRejection
+		min              int
+		expectedPrice    float64
+		expectedRejected []int
+		wantErr          bool
+	}{
+		{
+			name:             "mad",
+			prices:           []float64{100, 101, 99, 100.5, 150},
+			outliers:         OutlierRejection{Method: MADOutlierRejection, Threshold: 3},
+			min:              3,
+			expectedPrice:    100.25,
+			expectedRejected: []int{4},
+		},
+		{
+			name:             "mad equal to zero",
+			prices:           []float64{100, 100, 100, 150},
+			outliers:         OutlierRejection{Method: MADOutlierRejection, Threshold: 3},
+			min:              3,
+			expectedPrice:    100,
+			expectedRejected: []int{3},
+		},
+		{
+			name:          "mad equal to zero, small deviation",
+			prices:        []float64{100, 100, 100, 100.2},
+			outliers:      OutlierRejection{Method: MADOutlierRejection, Threshold: 3},
+			min:           4,
+			expectedPrice: 100,
+		},
+		{
+			name:             "percent",
+			prices:           []float64{100, 104, 90, 100},
+			outliers:         OutlierRejection{Method: PercentOutlierRejection, Threshold: 5},
+			min:              3,
+			expectedPrice:    100,
+			expectedRejected: []int{2},
+		},
+		{
+			name:          "no outliers",
+			prices:        []float64{100, 104, 96},
+			outliers:      OutlierRejection{Method: PercentOutlierRejection, Threshold: 5},
+			min:           3,
+			expectedPrice: 100,
+		},
+		{
+			name:             "not enough prices after rejection",
+			prices:           []float64{100, 120, 80},
+			outliers:         OutlierRejection{Method: PercentOutlierRejection, Threshold: 5},
+			min:              2,
+			expectedRejected: []int{1, 2},
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := NewMedianNodeWithOutlierRejection(pair, tt.min, tt.outliers)
+			for _, price := range tt.prices {
+				n := new(mockNode)
+				n.On("Tick").Return(provider.Tick{Price: bn.Float(price), Pair: pair, Time: time.Now()})
+				require.NoError(t, node.AddBranch(n))
+			}
+			tick := node.Tick()
+			if tt.wantErr {
+				assert.Error(t, tick.Validate())
+			} else {
+				require.NoError(t, tick.Validate())
+				assert.Equal(t, tt.expectedPrice, tick.Price.Float64())
+			}
+			assert.Len(t, tick.SubTicks, len(tt.prices))
+			meta := tick.Meta.Meta()
+			assert.Equal(t, tt.outliers.Method.String(), meta["outlier_method"])
+			if len(tt.expectedRejected) == 0 {
+				assert.NotContains(t, meta, "outliers")
+				return
+			}
+			require.Contains(t, meta, "outliers")
+			var rejected []int
+			for _, o := range meta["outliers"].([]MapMeta) {
+				rejected = append(rejected, o["index"].(int))
+				assert.NotEmpty(t, o["reason"])
+			}
+			assert.Equal(t, tt.expectedRejected, rejected)
+		})
+	}
+}
@@ -25,6 +25,21 @@ type OriginNode struct {
 	// expiryThreshold describes the duration after which the price is
 	// considered expired, and an update is required.
 	expiryThreshold time.Duration
+
+	// now returns the current time. It is used to evaluate price models
+	// as of a past time.
+	now func() time.Time
+
+	// observers are notified by the Updater after the tick is updated.
+	observers []TickObserver
+}
+
+// TickObserver is implemented by nodes that must be notified when ticks of
+// origin nodes in their branches are updated.
+type TickObserver interface {
+	// TicksUpdated is called by the Updater after it updates the ticks of
+	// origin nodes observed by the node.
+	TicksUpdated()
 }
 
 // NewOriginNode creates a new OriginNode instance.
@@ -64,6 +79,7 @@ func NewOriginNode(
 		tick:               provider.Tick{Pair: pair, Error: fmt.Errorf("tick is not set")},
 		freshnessThreshold: freshnessThreshold,
 		expiryThreshold:    expiryThreshold,
+		now:                time.Now,
 	}
 }
 
@@ -95,6 +111,18 @@ func (n *OriginNode) FetchPair() provider.Pair {
 	return n.fetchPair
 }
 
+// FreshnessThreshold returns the duration within which the tick is
+// considered fresh.
+func (n *OriginNode) FreshnessThreshold() time.Duration {
+	return n.freshnessThreshold
+}
+
+// ExpiryThreshold returns the duration after which the tick is considered
+// expired.
+func (n *OriginNode) ExpiryThreshold() time.Duration {
+	return n.expiryThreshold
+}
+
 // Tick implements the Node interface.
 func (n *OriginNode) Tick() provider.Tick {
 	n.mu.RLock()
@@ -142,14 +170,37 @@ func (n *OriginNode) SetTick(tick provider.Tick) error {
 func (n *OriginNode) IsFresh() bool {
 	n.mu.RLock()
 	defer n.mu.RUnlock()
-	return n.tick.Time.Add(n.freshnessThreshold).After(time.Now())
+	return n.tick.Time.Add(n.freshnessThreshold).After(n.now())
 }
 
 // IsExpired returns true if the price is considered expired.
 func (n *OriginNode) IsExpired() bool {
 	n.mu.RLock()
 	defer n.mu.RUnlock()
-	return n.tick.Time.Add(n.expiryThreshold).Before(time.Now())
+	return n.tick.Time.Add(n.expiryThreshold).Before(n.now())
+}
+
+// SetClock sets the function that returns the current time used to check
+// whether the tick is fresh or expired. By default, time.Now is used.
+func (n *OriginNode) SetClock(now func() time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.now = now
+}
+
+// AddObserver registers an observer that is notified by the Updater after
+// the tick of the node is updated.
+func (n *OriginNode) AddObserver(o TickObserver) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.observers = appendIfUnique(n.observers, o)
+}
+
+// Observers returns the observers registered on the node.
+func (n *OriginNode) Observers() []TickObserver {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.observers
 }
 
 // Meta implements the Node interface.
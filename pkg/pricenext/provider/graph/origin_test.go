@@ -88,3 +88,17 @@ func TestOriginNode(t *testing.T) {
 		})
 	}
 }
+
+func TestOriginNode_SetClock(t *testing.T) {
+	tm := time.Now().Add(-time.Hour)
+	pair := provider.Pair{Base: "A", Quote: "B"}
+	node := NewOriginNode("origin", pair, pair, time.Minute, time.Minute*2)
+	require.NoError(t, node.SetTick(provider.Tick{Pair: pair, Price: bn.Float(1), Time: tm}))
+	assert.True(t, node.IsExpired())
+
+	// The tick is evaluated relative to the time returned by the clock.
+	node.SetClock(func() time.Time { return tm.Add(time.Second) })
+	assert.True(t, node.IsFresh())
+	assert.False(t, node.IsExpired())
+	assert.NoError(t, node.Tick().Validate())
+}
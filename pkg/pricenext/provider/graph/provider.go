@@ -2,8 +2,10 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
@@ -21,14 +23,44 @@ func (e ErrModelNotFound) Error() string {
 type Provider struct {
 	models  map[string]Node
 	updater *Updater
+	health  HealthReporter
+	history *TickHistory
 }
 
 // NewProvider creates a new price provider.
+//
+// If updater is nil, origin nodes are not updated by the provider and ticks
+// are read directly from the graph. In this case, origin nodes must be
+// updated by other means, e.g. by the UpdaterService.
+//
+// If updater is not nil, the health of origins reported by the updater is
+// added to the origin models returned by Model and Models.
 func NewProvider(models map[string]Node, updater *Updater) Provider {
-	return Provider{
+	p := Provider{
 		models:  models,
 		updater: updater,
 	}
+	if updater != nil {
+		p.health = updater
+	}
+	return p
+}
+
+// WithHealthReporter returns a copy of the provider that adds the health of
+// origins reported by the given reporter to the origin models. It is useful
+// when origin nodes are updated by other means than the provider.
+func (p Provider) WithHealthReporter(health HealthReporter) Provider {
+	p.health = health
+	return p
+}
+
+// WithHistory returns a copy of the provider that records ticks calculated
+// by price models in the given history. The history is also used by TickAt
+// to evaluate models at a past time. To record ticks fetched from origins,
+// the same history must be set on the updater.
+func (p Provider) WithHistory(history *TickHistory) Provider {
+	p.history = history
+	return p
 }
 
 // ModelNames implements the provider.Provider interface.
@@ -42,10 +74,14 @@ func (p Provider) Tick(ctx context.Context, model string) (provider.Tick, error)
 	if !ok {
 		return provider.Tick{}, ErrModelNotFound{model: model}
 	}
-	if err := p.updater.Update(ctx, []Node{node}); err != nil {
-		return provider.Tick{}, err
+	if p.updater != nil {
+		if err := p.updater.Update(ctx, []Node{node}); err != nil {
+			return provider.Tick{}, err
+		}
 	}
-	return node.Tick(), nil
+	tick := node.Tick()
+	p.recordModelTick(model, tick)
+	return tick, nil
 }
 
 // Ticks implements the provider.Provider interface.
@@ -58,23 +94,73 @@ func (p Provider) Ticks(ctx context.Context, models ...string) (map[string]provi
 		}
 		nodes[i] = node
 	}
-	if err := p.updater.Update(ctx, nodes); err != nil {
-		return nil, err
+	if p.updater != nil {
+		if err := p.updater.Update(ctx, nodes); err != nil {
+			return nil, err
+		}
 	}
 	ticks := make(map[string]provider.Tick, len(models))
 	for i, model := range models {
 		ticks[model] = nodes[i].Tick()
+		p.recordModelTick(model, ticks[model])
 	}
 	return ticks, nil
 }
 
+// TickAt returns a tick for the given model calculated as it would have been
+// at the given time, using origin ticks recorded in the history.
+//
+// For every origin node, the most recent valid tick not newer than the given
+// time is used, and freshness and expiry are evaluated relative to that
+// time.
+//
+// Models that contain nodes keeping their own state, like TWAP nodes, cannot
+// be recalculated from origin ticks. For these models, the most recent tick
+// calculated by the model not newer than the given time is returned. If no
+// such tick was recorded, an error is returned.
+func (p Provider) TickAt(_ context.Context, model string, at time.Time) (provider.Tick, error) {
+	if p.history == nil {
+		return provider.Tick{}, errors.New("tick history is not enabled")
+	}
+	node, ok := p.models[model]
+	if !ok {
+		return provider.Tick{}, ErrModelNotFound{model: model}
+	}
+	if isStateful(node) {
+		tick, ok := p.history.ModelTick(model, at)
+		if !ok {
+			return provider.Tick{}, fmt.Errorf("model %s keeps its own state and no tick was recorded before %s", model, at)
+		}
+		return tick, nil
+	}
+	clone, err := cloneGraph(node, func(n *OriginNode) Node {
+		return historicalOriginNode(n, p.history, at)
+	})
+	if err != nil {
+		return provider.Tick{}, err
+	}
+	return clone.Tick(), nil
+}
+
+// ModelTicks returns ticks calculated by the given model between the given
+// times, inclusive, as recorded in the history.
+func (p Provider) ModelTicks(_ context.Context, model string, from, to time.Time) ([]provider.Tick, error) {
+	if p.history == nil {
+		return nil, errors.New("tick history is not enabled")
+	}
+	if _, ok := p.models[model]; !ok {
+		return nil, ErrModelNotFound{model: model}
+	}
+	return p.history.ModelTicks(model, from, to), nil
+}
+
 // Model implements the provider.Provider interface.
 func (p Provider) Model(_ context.Context, model string) (provider.Model, error) {
 	node, ok := p.models[model]
 	if !ok {
 		return provider.Model{}, ErrModelNotFound{model: model}
 	}
-	return nodeToModel(node), nil
+	return nodeToModel(node, p.originHealth()), nil
 }
 
 // Models implements the provider.Provider interface.
@@ -87,19 +173,45 @@ func (p Provider) Models(_ context.Context, models ...string) (map[string]provid
 		}
 		nodes[i] = node
 	}
+	health := p.originHealth()
 	modelsMap := make(map[string]provider.Model, len(models))
 	for i, model := range models {
-		modelsMap[model] = nodeToModel(nodes[i])
+		modelsMap[model] = nodeToModel(nodes[i], health)
 	}
 	return modelsMap, nil
 }
 
-func nodeToModel(n Node) provider.Model {
+func (p Provider) recordModelTick(model string, tick provider.Tick) {
+	if p.history != nil {
+		p.history.RecordModel(model, tick)
+	}
+}
+
+func (p Provider) originHealth() map[string]OriginHealth {
+	if p.health == nil {
+		return nil
+	}
+	return p.health.OriginHealth()
+}
+
+// nodeToModel converts a node to a model. If the health of the origin used
+// by an origin node is known, it is added to the model meta under the
+// "health" key.
+func nodeToModel(n Node, health map[string]OriginHealth) provider.Model {
 	m := provider.Model{}
 	m.Pair = n.Pair()
 	m.Meta = n.Meta()
+	if o, ok := n.(*OriginNode); ok {
+		if h, ok := health[o.Origin()]; ok {
+			meta := MapMeta{"health": h.Meta()}
+			for k, v := range o.Meta().Meta() {
+				meta[k] = v
+			}
+			m.Meta = meta
+		}
+	}
 	for _, n := range n.Branches() {
-		m.Models = append(m.Models, nodeToModel(n))
+		m.Models = append(m.Models, nodeToModel(n, health))
 	}
 	if m.Meta == nil {
 		m.Meta = MapMeta{}
@@ -95,3 +95,114 @@ func TestProvider_Models(t *testing.T) {
 	assert.Equal(t, provider.Pair{Base: "BTC", Quote: "USD"}, models["model_a"].Pair)
 	assert.Equal(t, provider.Pair{Base: "BTC", Quote: "USD"}, models["model_b"].Pair)
 }
+
+func TestProvider_Model_Health(t *testing.T) {
+	prov := newTestProvider()
+
+	// Health is not known until the origin is used.
+	model, err := prov.Model(context.Background(), "model_a")
+	require.NoError(t, err)
+	assert.NotContains(t, model.Meta.Meta(), "health")
+
+	_, err = prov.Tick(context.Background(), "model_a")
+	require.NoError(t, err)
+	model, err = prov.Model(context.Background(), "model_a")
+	require.NoError(t, err)
+	require.Contains(t, model.Meta.Meta(), "health")
+	health := model.Meta.Meta()["health"].(MapMeta)
+	assert.Equal(t, uint64(1), health["requests"])
+	assert.Equal(t, false, health["quarantined"])
+	assert.Equal(t, "origin", model.Meta.Meta()["type"])
+}
+
+func TestProvider_TickAt(t *testing.T) {
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history, err := NewTickHistory(10, "")
+	require.NoError(t, err)
+	history.RecordOrigin("a", provider.Tick{Pair: pair, Price: bn.Float(10), Time: t0})
+	history.RecordOrigin("a", provider.Tick{Pair: pair, Price: bn.Float(20), Time: t0.Add(time.Minute)})
+	history.RecordOrigin("b", provider.Tick{Pair: pair, Price: bn.Float(30), Time: t0})
+
+	originA := NewOriginNode("a", pair, pair, time.Minute, 5*time.Minute)
+	originB := NewOriginNode("b", pair, pair, time.Minute, 5*time.Minute)
+	median := NewMedianNode(pair, 2)
+	require.NoError(t, median.AddBranch(originA, originB))
+	prov := NewProvider(map[string]Node{"model": median}, nil).WithHistory(history)
+
+	tick, err := prov.TickAt(context.Background(), "model", t0.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "20", tick.Price.String())
+
+	tick, err = prov.TickAt(context.Background(), "model", t0.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "25", tick.Price.String())
+
+	// Ticks are expired.
+	tick, err = prov.TickAt(context.Background(), "model", t0.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Error(t, tick.Validate())
+
+	// No ticks recorded before t0.
+	tick, err = prov.TickAt(context.Background(), "model", t0.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Error(t, tick.Validate())
+
+	// The original graph must not be modified.
+	assert.Error(t, originA.Tick().Validate())
+
+	_, err = prov.TickAt(context.Background(), "unknown", t0)
+	assert.ErrorAs(t, err, &ErrModelNotFound{})
+}
+
+func TestProvider_TickAt_Stateful(t *testing.T) {
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history, err := NewTickHistory(10, "")
+	require.NoError(t, err)
+	history.RecordOrigin("a", provider.Tick{Pair: pair, Price: bn.Float(10), Time: t0})
+	history.RecordModel("model", provider.Tick{Pair: pair, Price: bn.Float(15), Time: t0})
+	history.RecordModel("model", provider.Tick{Pair: pair, Price: bn.Float(16), Time: t0.Add(time.Minute)})
+
+	twap := NewTWAPNode(pair, time.Minute, 10)
+	require.NoError(t, twap.AddBranch(NewOriginNode("a", pair, pair, time.Minute, 5*time.Minute)))
+	prov := NewProvider(map[string]Node{"model": twap}, nil).WithHistory(history)
+
+	// The tick recorded for the model is returned instead of the spot
+	// price of the origin.
+	tick, err := prov.TickAt(context.Background(), "model", t0.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "15", tick.Price.String())
+	assert.True(t, t0.Equal(tick.Time))
+
+	tick, err = prov.TickAt(context.Background(), "model", t0.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "16", tick.Price.String())
+
+	// No ticks recorded before t0.
+	_, err = prov.TickAt(context.Background(), "model", t0.Add(-time.Second))
+	assert.Error(t, err)
+}
+
+func TestProvider_Tick_History(t *testing.T) {
+	history, err := NewTickHistory(10, "")
+	require.NoError(t, err)
+	prov := newTestProvider()
+	prov.updater.SetHistory(history)
+	prov = prov.WithHistory(history)
+
+	_, err = prov.Tick(context.Background(), "model_a")
+	require.NoError(t, err)
+
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	now := time.Now()
+	tick, ok := history.OriginTick("test", pair, now)
+	require.True(t, ok)
+	assert.Equal(t, bn.Float(42), tick.Price)
+	ticks, err := prov.ModelTicks(context.Background(), "model_a", now.Add(-time.Minute), now)
+	require.NoError(t, err)
+	require.Len(t, ticks, 1)
+	assert.Equal(t, bn.Float(42), ticks[0].Price)
+}
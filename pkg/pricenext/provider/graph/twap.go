@@ -0,0 +1,217 @@
+package graph
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+// twapSample is a single price observation used to calculate TWAP.
+type twapSample struct {
+	price *bn.FloatNumber
+	time  time.Time
+}
+
+// TWAPNode is a node that calculates a time-weighted average price of its
+// branch over a given time window.
+//
+// The node records an observation every time the Updater updates an origin
+// node in its branch, so the history does not depend on how often the node
+// is read. The node must be registered as an observer of these origin nodes
+// using the AttachObservers function.
+//
+// Each price in the history is weighted by the time it was effective, that
+// is, until the next observation. The window ends at the time of the latest
+// observation, so only tick times are used and the result does not depend
+// on the local clock. Observations older than the window are discarded, but
+// the last one before the window is kept because its price is effective at
+// the beginning of the window.
+//
+// If the history does not cover the whole window yet, for example shortly
+// after startup, the node returns an error instead of an average of a
+// shorter period.
+type TWAPNode struct {
+	mu sync.Mutex
+
+	pair       provider.Pair
+	branch     Node
+	window     time.Duration
+	maxSamples int
+	samples    []twapSample
+}
+
+// NewTWAPNode creates a new TWAPNode instance.
+//
+// The window argument is the duration over which the average is calculated.
+//
+// The maxSamples argument is the maximum number of observations kept in the
+// history. If the limit is reached, the oldest observations are discarded.
+// If zero, the history is limited only by the window.
+func NewTWAPNode(pair provider.Pair, window time.Duration, maxSamples int) *TWAPNode {
+	return &TWAPNode{
+		pair:       pair,
+		window:     window,
+		maxSamples: maxSamples,
+	}
+}
+
+// AddBranch implements the Node interface.
+//
+// Node requires one branch. If more than one branch is added, an error is
+// returned.
+func (n *TWAPNode) AddBranch(branch ...Node) error {
+	if len(branch) == 0 {
+		return nil
+	}
+	if n.branch != nil {
+		return fmt.Errorf("branch already exists")
+	}
+	if len(branch) != 1 {
+		return fmt.Errorf("only 1 branch is allowed")
+	}
+	if !branch[0].Pair().Equal(n.pair) {
+		return fmt.Errorf("expected pair %s, got %s", n.pair, branch[0].Pair())
+	}
+	n.branch = branch[0]
+	return nil
+}
+
+// Branches implements the Node interface.
+func (n *TWAPNode) Branches() []Node {
+	if n.branch == nil {
+		return nil
+	}
+	return []Node{n.branch}
+}
+
+// Pair implements the Node interface.
+func (n *TWAPNode) Pair() provider.Pair {
+	return n.pair
+}
+
+// Tick implements the Node interface.
+func (n *TWAPNode) Tick() provider.Tick {
+	if n.branch == nil {
+		return provider.Tick{
+			Pair:  n.pair,
+			Error: fmt.Errorf("branch is not set"),
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	tick := n.branch.Tick()
+	meta := n.meta()
+	meta["samples"] = len(n.samples)
+	if err := tick.Validate(); err != nil {
+		return provider.Tick{
+			Pair:     n.pair,
+			Time:     tick.Time,
+			SubTicks: []provider.Tick{tick},
+			Meta:     meta,
+			Error:    fmt.Errorf("invalid branch tick: %w", err),
+		}
+	}
+	if len(n.samples) == 0 {
+		return provider.Tick{
+			Pair:     n.pair,
+			Time:     tick.Time,
+			SubTicks: []provider.Tick{tick},
+			Meta:     meta,
+			Error:    fmt.Errorf("no observations recorded"),
+		}
+	}
+
+	end := n.samples[len(n.samples)-1].time
+	coverage := end.Sub(n.samples[0].time)
+	meta["coverage"] = coverage
+	if coverage < n.window {
+		return provider.Tick{
+			Pair:     n.pair,
+			Time:     end,
+			SubTicks: []provider.Tick{tick},
+			Meta:     meta,
+			Error:    fmt.Errorf("observations cover %s of the %s window", coverage, n.window),
+		}
+	}
+	return provider.Tick{
+		Pair:      n.pair,
+		Price:     n.twap(),
+		Volume24h: tick.Volume24h,
+		Time:      end,
+		SubTicks:  []provider.Tick{tick},
+		Meta:      meta,
+	}
+}
+
+// TicksUpdated implements the TickObserver interface.
+//
+// It records the current branch tick in the history.
+func (n *TWAPNode) TicksUpdated() {
+	if n.branch == nil {
+		return
+	}
+	tick := n.branch.Tick()
+	if tick.Validate() != nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.addSample(tick)
+}
+
+// Meta implements the Node interface.
+func (n *TWAPNode) Meta() provider.Meta {
+	return n.meta()
+}
+
+func (n *TWAPNode) meta() MapMeta {
+	return MapMeta{"type": "twap", "window": n.window, "max_samples": n.maxSamples}
+}
+
+// addSample adds the tick to the history if it is newer than the last
+// observation and removes observations that are no longer needed.
+func (n *TWAPNode) addSample(tick provider.Tick) {
+	if len(n.samples) > 0 && !tick.Time.After(n.samples[len(n.samples)-1].time) {
+		return
+	}
+	n.samples = append(n.samples, twapSample{price: tick.Price, time: tick.Time})
+
+	// Discard observations that are not effective within the window.
+	// An observation is still effective if the next one is within
+	// the window.
+	start := tick.Time.Add(-n.window)
+	drop := 0
+	for drop+1 < len(n.samples) && !n.samples[drop+1].time.After(start) {
+		drop++
+	}
+	if n.maxSamples > 0 && len(n.samples)-drop > n.maxSamples {
+		drop = len(n.samples) - n.maxSamples
+	}
+	n.samples = n.samples[drop:]
+}
+
+// twap calculates the time-weighted average price of the observations
+// within the window ending at the latest observation. The history must
+// cover the whole window.
+func (n *TWAPNode) twap() *bn.FloatNumber {
+	end := n.samples[len(n.samples)-1].time
+	start := end.Add(-n.window)
+	sum := bn.Float(0)
+	for i := 0; i+1 < len(n.samples); i++ {
+		from := n.samples[i].time
+		if from.Before(start) {
+			from = start
+		}
+		to := n.samples[i+1].time
+		if !to.After(from) {
+			continue
+		}
+		sum = sum.Add(n.samples[i].price.Mul(bn.Float(to.Sub(from).Seconds())))
+	}
+	return sum.Div(bn.Float(n.window.Seconds()))
+}
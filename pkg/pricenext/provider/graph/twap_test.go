@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+func TestTWAPNode_AddBranch(t *testing.T) {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	t.Run("single branch", func(t *testing.T) {
+		node := NewTWAPNode(btcusd, time.Minute, 10)
+		require.NoError(t, node.AddBranch(NewOriginNode("test", btcusd, btcusd, time.Minute, time.Minute)))
+		assert.Len(t, node.Branches(), 1)
+	})
+	t.Run("second branch", func(t *testing.T) {
+		node := NewTWAPNode(btcusd, time.Minute, 10)
+		require.NoError(t, node.AddBranch(NewOriginNode("test", btcusd, btcusd, time.Minute, time.Minute)))
+		assert.Error(t, node.AddBranch(NewOriginNode("test", btcusd, btcusd, time.Minute, time.Minute)))
+	})
+	t.Run("different pair", func(t *testing.T) {
+		ethusd := provider.Pair{Base: "ETH", Quote: "USD"}
+		node := NewTWAPNode(btcusd, time.Minute, 10)
+		assert.Error(t, node.AddBranch(NewOriginNode("test", ethusd, ethusd, time.Minute, time.Minute)))
+	})
+}
+
+func TestTWAPNode_Tick(t *testing.T) {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	now := time.Now()
+	tests := []struct {
+		name        string
+		window      time.Duration
+		maxSamples  int
+		ticks       []provider.Tick
+		wantPrice   float64
+		wantSamples int
+		wantErr     bool
+	}{
+		{
+			name:   "single tick",
+			window: time.Minute,
+			ticks: []provider.Tick{
+				{Pair: btcusd, Price: bn.Float(100), Time: now},
+			},
+			wantSamples: 1,
+			wantErr:     true,
+		},
+		{
+			name:   "equal weights",
+			window: time.Minute,
+			ticks: []provider.Tick{
+				{Pair: btcusd, Price: bn.Float(100), Time: now.Add(-60 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(200), Time: now.Add(-30 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(300), Time: now},
+			},
+			wantPrice:   150,
+			wantSamples: 3,
+		},
+		{
+			name:   "spike is damped",
+			window: time.Minute,
+			ticks: []provider.Tick{
+				{Pair: btcusd, Price: bn.Float(100), Time: now.Add(-60 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(10), Time: now.Add(-3 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(10), Time: now},
+			},
+			wantPrice:   95.5,
+			wantSamples: 3,
+		},
+		{
+			name:   "old ticks are discarded",
+			window: time.Minute,
+			ticks: []provider.Tick{
+				{Pair: btcusd, Price: bn.Float(1000), Time: now.Add(-5 * time.Minute)},
+				{Pair: btcusd, Price: bn.Float(100), Time: now.Add(-2 * time.Minute)},
+				{Pair: btcusd, Price: bn.Float(200), Time: now.Add(-30 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(300), Time: now},
+			},
+			wantPrice:   150,
+			wantSamples: 3,
+		},
+		{
+			name:   "repeated ticks are ignored",
+			window: time.Minute,
+			ticks: []provider.Tick{
+				{Pair: btcusd, Price: bn.Float(100), Time: now.Add(-60 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(100), Time: now.Add(-60 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(200), Time: now},
+			},
+			wantPrice:   100,
+			wantSamples: 2,
+		},
+		{
+			name:       "max samples",
+			window:     time.Minute,
+			maxSamples: 2,
+			ticks: []provider.Tick{
+				{Pair: btcusd, Price: bn.Float(1000), Time: now.Add(-60 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(100), Time: now.Add(-30 * time.Second)},
+				{Pair: btcusd, Price: bn.Float(200), Time: now},
+			},
+			wantSamples: 2,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := NewOriginNode("test", btcusd, btcusd, time.Hour, time.Hour)
+			node := NewTWAPNode(btcusd, tt.window, tt.maxSamples)
+			require.NoError(t, node.AddBranch(origin))
+			for _, tk := range tt.ticks {
+				require.NoError(t, origin.SetTick(tk))
+				node.TicksUpdated()
+			}
+			tick := node.Tick()
+			assert.Equal(t, tt.ticks[len(tt.ticks)-1].Time, tick.Time)
+			assert.Equal(t, "twap", tick.Meta.Meta()["type"])
+			assert.Equal(t, tt.wantSamples, tick.Meta.Meta()["samples"])
+			if tt.wantErr {
+				assert.Error(t, tick.Validate())
+				return
+			}
+			require.NoError(t, tick.Validate())
+			assert.InDelta(t, tt.wantPrice, tick.Price.Float64(), 0.1)
+		})
+	}
+}
+
+func TestTWAPNode_Tick_NoSamplesOnRead(t *testing.T) {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	origin := NewOriginNode("test", btcusd, btcusd, time.Hour, time.Hour)
+	node := NewTWAPNode(btcusd, time.Minute, 10)
+	require.NoError(t, node.AddBranch(origin))
+	require.NoError(t, origin.SetTick(provider.Tick{Pair: btcusd, Price: bn.Float(100), Time: time.Now()}))
+
+	// Reading the node must not record observations, otherwise the
+	// average would depend on how often the node is read.
+	for i := 0; i < 3; i++ {
+		tick := node.Tick()
+		assert.Error(t, tick.Error)
+		assert.Equal(t, 0, tick.Meta.Meta()["samples"])
+	}
+}
+
+func TestTWAPNode_Tick_InvalidBranch(t *testing.T) {
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	node := NewTWAPNode(btcusd, time.Minute, 10)
+	require.NoError(t, node.AddBranch(NewOriginNode("test", btcusd, btcusd, time.Minute, time.Minute)))
+	tick := node.Tick()
+	assert.Error(t, tick.Error)
+	assert.Len(t, tick.SubTicks, 1)
+}
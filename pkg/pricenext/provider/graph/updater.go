@@ -2,7 +2,9 @@ package graph
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -17,9 +19,16 @@ const UpdaterLoggerTag = "GRAPH_UPDATER"
 const maxConcurrentUpdates = 10
 
 // Updater updates the origin nodes using ticks from the origins.
+//
+// The updater tracks the health of every origin. Origins that repeatedly fail
+// to return valid ticks are quarantined and not used until the quarantine
+// expires. The quarantine duration grows exponentially with every failed
+// attempt made after it expires.
 type Updater struct {
 	origins map[string]origin.Origin
 	limiter chan struct{}
+	health  *healthTracker
+	history *TickHistory
 	logger  log.Logger
 }
 
@@ -28,19 +37,34 @@ func NewUpdater(origins map[string]origin.Origin, logger log.Logger) *Updater {
 	if logger == nil {
 		logger = null.New()
 	}
+	logger = logger.WithField("tag", UpdaterLoggerTag)
 	return &Updater{
 		origins: origins,
 		limiter: make(chan struct{}, maxConcurrentUpdates),
-		logger:  logger.WithField("tag", UpdaterLoggerTag),
+		health:  newHealthTracker(logger),
+		logger:  logger,
 	}
 }
 
+// OriginHealth implements the HealthReporter interface.
+func (u *Updater) OriginHealth() map[string]OriginHealth {
+	return u.health.snapshot()
+}
+
+// SetHistory sets the history in which all ticks fetched from origins are
+// recorded. It must be called before the updater is used.
+func (u *Updater) SetHistory(history *TickHistory) {
+	u.history = history
+}
+
 // Update updates the origin nodes in the given graphs.
 //
 // Only origin nodes that are not fresh will be updated.
 func (u *Updater) Update(ctx context.Context, graphs []Node) error {
 	nodes, pairs := u.identifyNodesAndPairsToUpdate(graphs)
-	u.updateNodesWithTicks(nodes, u.fetchTicksForPairs(ctx, pairs))
+	for _, o := range u.updateNodesWithTicks(nodes, u.fetchTicksForPairs(ctx, pairs)) {
+		o.TicksUpdated()
+	}
 	return nil
 }
 
@@ -64,7 +88,7 @@ func (u *Updater) identifyNodesAndPairsToUpdate(graphs []Node) (nodesMap, pairsM
 // fetchTicksForPairs fetches the ticks for the given pairs from the origins.
 //
 // Ticks are fetched asynchronously, number of concurrent fetches is limited by
-// the maxConcurrentUpdates constant.
+// the maxConcurrentUpdates constant. Quarantined origins are skipped.
 func (u *Updater) fetchTicksForPairs(ctx context.Context, pairs pairsMap) ticksMap {
 	mu := sync.Mutex{}
 	wg := sync.WaitGroup{}
@@ -79,10 +103,22 @@ func (u *Updater) fetchTicksForPairs(ctx context.Context, pairs pairsMap) ticksM
 			if origin == nil {
 				return
 			}
+			if u.health.quarantined(originName, time.Now()) {
+				u.logger.
+					WithField("origin", originName).
+					Debug("Origin is quarantined, skipping")
+				return
+			}
+
+			// Limit the number of concurrent updates.
+			u.limiter <- struct{}{}
+			defer func() { <-u.limiter }()
 
 			// Recover from panics that may occur during fetching ticks.
+			start := time.Now()
 			defer func() {
 				if r := recover(); r != nil {
+					u.health.record(originName, time.Now(), time.Since(start), nil, fmt.Errorf("panic: %v", r))
 					u.logger.
 						WithFields(log.Fields{
 							"origin": originName,
@@ -92,12 +128,13 @@ func (u *Updater) fetchTicksForPairs(ctx context.Context, pairs pairsMap) ticksM
 				}
 			}()
 
-			// Limit the number of concurrent updates.
-			u.limiter <- struct{}{}
-			defer func() { <-u.limiter }()
-
 			// Fetch ticks from the origin and store them in the map.
-			for _, tick := range origin.FetchTicks(ctx, pairs) {
+			fetched := origin.FetchTicks(ctx, pairs)
+			u.health.record(originName, time.Now(), time.Since(start), fetched, nil)
+			for _, tick := range fetched {
+				if u.history != nil {
+					u.history.RecordOrigin(originName, tick)
+				}
 				mu.Lock()
 				ticks.add(originName, tick)
 				mu.Unlock()
@@ -110,11 +147,13 @@ func (u *Updater) fetchTicksForPairs(ctx context.Context, pairs pairsMap) ticksM
 	return ticks
 }
 
-// updateNodesWithTicks updates the nodes with the given ticks.
+// updateNodesWithTicks updates the nodes with the given ticks and returns
+// the observers of the updated nodes.
 //
 // If a tick is missing for a node, the ErrMissingTick error will be set on the
 // node as a warning.
-func (u *Updater) updateNodesWithTicks(nodes nodesMap, ticks ticksMap) {
+func (u *Updater) updateNodesWithTicks(nodes nodesMap, ticks ticksMap) []TickObserver {
+	var observers []TickObserver
 	for op, nodes := range nodes {
 		tick, ok := ticks[op]
 		for _, node := range nodes {
@@ -135,9 +174,14 @@ func (u *Updater) updateNodesWithTicks(nodes nodesMap, ticks ticksMap) {
 					}).
 					WithError(err).
 					Warn("Failed to set tick on origin node")
+				continue
+			}
+			for _, o := range node.Observers() {
+				observers = appendIfUnique(observers, o)
 			}
 		}
 	}
+	return observers
 }
 
 type (
@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/timeutil"
+)
+
+const UpdaterServiceLoggerTag = "GRAPH_UPDATER_SERVICE"
+
+// minUpdateInterval is the minimum interval between updates used when the
+// interval is derived from the freshness thresholds of origin nodes.
+const minUpdateInterval = time.Second
+
+// UpdaterService is a service which periodically updates origin nodes in the
+// given graphs in the background.
+//
+// Origin nodes are grouped by origin and each group is updated
+// independently, so a slow origin does not delay updates of other origins.
+// Only origin nodes that are not fresh are updated.
+//
+// When the service is running, the Provider may be created without an
+// updater, so that ticks are read directly from the cached origin nodes.
+type UpdaterService struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	waitCh chan error
+
+	updater  *Updater
+	graphs   []Node
+	interval *timeutil.Ticker
+	logger   log.Logger
+
+	busy map[string]bool   // origins that are currently being updated
+	wg   sync.WaitGroup    // in-flight updates
+	jobs map[string][]Node // origin nodes grouped by origin
+}
+
+// UpdaterServiceConfig is the configuration for the UpdaterService.
+type UpdaterServiceConfig struct {
+	// Updater is the updater used to update the origin nodes.
+	Updater *Updater
+
+	// Graphs is a list of graphs whose origin nodes should be updated.
+	Graphs []Node
+
+	// Interval describes how often origin nodes should be checked for
+	// updates. If nil, the smallest freshness threshold of all origin
+	// nodes is used, but not less than one second.
+	Interval *timeutil.Ticker
+
+	// Logger is a current logger interface used by the UpdaterService.
+	Logger log.Logger
+}
+
+// NewUpdaterService returns a new UpdaterService instance.
+func NewUpdaterService(cfg UpdaterServiceConfig) (*UpdaterService, error) {
+	if cfg.Updater == nil {
+		return nil, errors.New("updater must not be nil")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	if cfg.Interval == nil {
+		cfg.Interval = timeutil.NewTicker(updateInterval(cfg.Graphs))
+	}
+	return &UpdaterService{
+		waitCh:   make(chan error),
+		updater:  cfg.Updater,
+		graphs:   cfg.Graphs,
+		interval: cfg.Interval,
+		logger:   cfg.Logger.WithField("tag", UpdaterServiceLoggerTag),
+		busy:     make(map[string]bool),
+		jobs:     make(map[string][]Node),
+	}, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (s *UpdaterService) Start(ctx context.Context) error {
+	if s.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	s.logger.Info("Starting")
+	s.ctx = ctx
+	Walk(func(n Node) {
+		if originNode, ok := n.(*OriginNode); ok {
+			s.jobs[originNode.Origin()] = append(s.jobs[originNode.Origin()], originNode)
+		}
+	}, s.graphs...)
+	s.interval.Start(s.ctx)
+	go s.updaterRoutine()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (s *UpdaterService) Wait() <-chan error {
+	return s.waitCh
+}
+
+// OriginHealth implements the HealthReporter interface.
+func (s *UpdaterService) OriginHealth() map[string]OriginHealth {
+	return s.updater.OriginHealth()
+}
+
+// update starts updating origin nodes for all origins that are not
+// currently being updated.
+func (s *UpdaterService) update() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for origin, nodes := range s.jobs {
+		if s.busy[origin] {
+			s.logger.
+				WithField("origin", origin).
+				Debug("Previous update is still in progress, skipping")
+			continue
+		}
+		s.busy[origin] = true
+		s.wg.Add(1)
+		go func(origin string, nodes []Node) {
+			defer s.wg.Done()
+			defer func() {
+				s.mu.Lock()
+				s.busy[origin] = false
+				s.mu.Unlock()
+			}()
+			if err := s.updater.Update(s.ctx, nodes); err != nil {
+				s.logger.
+					WithField("origin", origin).
+					WithError(err).
+					Warn("Unable to update origin nodes")
+			}
+		}(origin, nodes)
+	}
+}
+
+func (s *UpdaterService) updaterRoutine() {
+	defer func() { close(s.waitCh) }()
+	defer s.logger.Info("Stopped")
+	s.update()
+	for {
+		select {
+		case <-s.ctx.Done():
+			// Wait for in-flight updates before reporting the service as
+			// stopped.
+			s.wg.Wait()
+			return
+		case <-s.interval.TickCh():
+			s.update()
+		}
+	}
+}
+
+// updateInterval returns the smallest freshness threshold of all origin
+// nodes in the given graphs, but not less than minUpdateInterval.
+func updateInterval(graphs []Node) time.Duration {
+	interval := time.Duration(0)
+	Walk(func(n Node) {
+		if originNode, ok := n.(*OriginNode); ok {
+			if interval == 0 || originNode.FreshnessThreshold() < interval {
+				interval = originNode.FreshnessThreshold()
+			}
+		}
+	}, graphs...)
+	if interval < minUpdateInterval {
+		interval = minUpdateInterval
+	}
+	return interval
+}
@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider/origin"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/timeutil"
+)
+
+func TestUpdaterService(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	var calls int32
+	g := []Node{
+		NewOriginNode(
+			"origin_a",
+			provider.Pair{Base: "BTC", Quote: "USD"},
+			provider.Pair{Base: "BTC", Quote: "USD"},
+			time.Minute,
+			time.Minute*2,
+		),
+		NewOriginNode(
+			"origin_b",
+			provider.Pair{Base: "ETH", Quote: "USD"},
+			provider.Pair{Base: "ETH", Quote: "USD"},
+			time.Minute,
+			time.Minute*2,
+		),
+	}
+	fetchTicks := func(_ context.Context, pairs []provider.Pair) []provider.Tick {
+		atomic.AddInt32(&calls, 1)
+		ticks := make([]provider.Tick, len(pairs))
+		for i, pair := range pairs {
+			ticks[i] = provider.Tick{
+				Pair:  pair,
+				Price: bn.Float(42),
+				Time:  time.Now(),
+			}
+		}
+		return ticks
+	}
+	interval := timeutil.NewTicker(0)
+	s, err := NewUpdaterService(UpdaterServiceConfig{
+		Updater: NewUpdater(
+			map[string]origin.Origin{
+				"origin_a": &mockOrigin{fetchTicks: fetchTicks},
+				"origin_b": &mockOrigin{fetchTicks: fetchTicks},
+			},
+			null.New(),
+		),
+		Graphs:   g,
+		Interval: interval,
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Start(ctx))
+
+	// Origin nodes must be updated right after the service is started.
+	assert.Eventually(t, func() bool {
+		return g[0].Tick().Validate() == nil && g[1].Tick().Validate() == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, bn.Float(42), g[0].Tick().Price)
+	assert.Equal(t, bn.Float(42), g[1].Tick().Price)
+
+	// Fresh nodes must not be updated again.
+	interval.Tick()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// Service must stop after the context is canceled.
+	ctxCancel()
+	select {
+	case <-s.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("service did not stop")
+	}
+}
+
+func TestUpdaterService_Interval(t *testing.T) {
+	g := []Node{
+		NewOriginNode(
+			"origin_a",
+			provider.Pair{Base: "BTC", Quote: "USD"},
+			provider.Pair{Base: "BTC", Quote: "USD"},
+			time.Minute,
+			time.Minute*2,
+		),
+		NewOriginNode(
+			"origin_b",
+			provider.Pair{Base: "ETH", Quote: "USD"},
+			provider.Pair{Base: "ETH", Quote: "USD"},
+			time.Second*30,
+			time.Minute*2,
+		),
+	}
+	assert.Equal(t, time.Second*30, updateInterval(g))
+	assert.Equal(t, minUpdateInterval, updateInterval(nil))
+}
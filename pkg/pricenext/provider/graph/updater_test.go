@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -201,4 +202,86 @@ func TestUpdater(t *testing.T) {
 		assert.Contains(t, logs, "Panic while fetching ticks")
 		assert.Equal(t, bn.Float(42), g[1].Tick().Price)
 	})
+	t.Run("observers", func(t *testing.T) {
+		btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+		originNode := NewOriginNode("origin_a", btcusd, btcusd, time.Nanosecond, time.Minute)
+		twapNode := NewTWAPNode(btcusd, time.Minute, 10)
+		require.NoError(t, twapNode.AddBranch(originNode))
+		AttachObservers(twapNode)
+
+		u := NewUpdater(
+			map[string]origin.Origin{
+				"origin_a": &mockOrigin{
+					fetchTicks: func(_ context.Context, pairs []provider.Pair) []provider.Tick {
+						return []provider.Tick{{Pair: btcusd, Price: bn.Float(42), Time: time.Now()}}
+					},
+				},
+			},
+			null.New(),
+		)
+
+		// Observations must be recorded even if only origin nodes are
+		// updated, as the UpdaterService does.
+		require.NoError(t, u.Update(context.Background(), []Node{originNode}))
+		require.NoError(t, u.Update(context.Background(), []Node{originNode}))
+		assert.Equal(t, 2, twapNode.Tick().Meta.Meta()["samples"])
+	})
+}
+
+func TestUpdater_Quarantine(t *testing.T) {
+	pair := provider.Pair{Base: "BTC", Quote: "USD"}
+	calls := 0
+	failing := true
+	u := NewUpdater(
+		map[string]origin.Origin{
+			"origin_a": &mockOrigin{
+				fetchTicks: func(_ context.Context, pairs []provider.Pair) []provider.Tick {
+					calls++
+					if failing {
+						return []provider.Tick{{Pair: pair, Error: errors.New("timeout")}}
+					}
+					return []provider.Tick{{Pair: pair, Price: bn.Float(42), Time: time.Now()}}
+				},
+			},
+		},
+		null.New(),
+	)
+	u.health.minQuarantine = 50 * time.Millisecond
+	u.health.maxQuarantine = 80 * time.Millisecond
+	g := []Node{NewOriginNode("origin_a", pair, pair, time.Minute, time.Minute)}
+
+	// The origin is quarantined after three consecutive failures.
+	for i := 0; i < quarantineThreshold; i++ {
+		require.NoError(t, u.Update(context.Background(), g))
+	}
+	health := u.OriginHealth()["origin_a"]
+	assert.Equal(t, uint64(3), health.Requests)
+	assert.Equal(t, uint64(3), health.Failures)
+	assert.Equal(t, 3, health.ConsecutiveFailures)
+	assert.Equal(t, "timeout", health.LastError)
+	assert.True(t, health.Quarantined(time.Now()))
+
+	// Quarantined origin is not called.
+	require.NoError(t, u.Update(context.Background(), g))
+	assert.Equal(t, 3, calls)
+
+	// After the quarantine expires, the origin is called again, and
+	// the quarantine is extended if it still fails.
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, u.Update(context.Background(), g))
+	assert.Equal(t, 4, calls)
+	health = u.OriginHealth()["origin_a"]
+	assert.Equal(t, 4, health.ConsecutiveFailures)
+	assert.True(t, health.Quarantined(time.Now()))
+
+	// A successful fetch lifts the quarantine.
+	time.Sleep(90 * time.Millisecond)
+	failing = false
+	require.NoError(t, u.Update(context.Background(), g))
+	assert.Equal(t, 5, calls)
+	health = u.OriginHealth()["origin_a"]
+	assert.Equal(t, 0, health.ConsecutiveFailures)
+	assert.False(t, health.Quarantined(time.Now()))
+	assert.InDelta(t, 0.2, health.SuccessRate(), 0.001)
+	assert.Equal(t, bn.Float(42), g[0].Tick().Price)
 }
@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+// VolumeWeightedMethod is a method used by VolumeWeightedNode to aggregate
+// prices.
+type VolumeWeightedMethod int
+
+const (
+	// VolumeWeightedMedian calculates the volume-weighted median, that is,
+	// the price below and above which lies half of the total volume.
+	VolumeWeightedMedian VolumeWeightedMethod = iota
+
+	// VolumeWeightedAverage calculates the volume-weighted average price
+	// (VWAP).
+	VolumeWeightedAverage
+)
+
+// String returns the name of the method.
+func (m VolumeWeightedMethod) String() string {
+	switch m {
+	case VolumeWeightedMedian:
+		return "volume_weighted_median"
+	case VolumeWeightedAverage:
+		return "vwap"
+	default:
+		return "unknown"
+	}
+}
+
+// VolumeWeightedNode is a node that calculates a price from its branches
+// using the 24h volume of each tick as a weight.
+//
+// Ticks without volume are weighted using the fallback weight. Ticks with
+// zero weight are ignored and are not counted as valid sources.
+type VolumeWeightedNode struct {
+	pair           provider.Pair
+	method         VolumeWeightedMethod
+	min            int
+	fallbackWeight *bn.FloatNumber
+	branches       []Node
+}
+
+// NewVolumeWeightedNode creates a new VolumeWeightedNode instance.
+//
+// The min argument is a minimum number of valid prices obtained from
+// branches required to calculate the price.
+//
+// The fallbackWeight argument is a weight used for ticks that do not have
+// volume. If nil, such ticks are ignored.
+func NewVolumeWeightedNode(
+	pair provider.Pair,
+	method VolumeWeightedMethod,
+	min int,
+	fallbackWeight *bn.FloatNumber,
+) *VolumeWeightedNode {
+
+	return &VolumeWeightedNode{
+		pair:           pair,
+		method:         method,
+		min:            min,
+		fallbackWeight: fallbackWeight,
+	}
+}
+
+// AddBranch implements the Node interface.
+func (n *VolumeWeightedNode) AddBranch(branch ...Node) error {
+	n.branches = append(n.branches, branch...)
+	return nil
+}
+
+// Branches implements the Node interface.
+func (n *VolumeWeightedNode) Branches() []Node {
+	return n.branches
+}
+
+// Pair implements the Node interface.
+func (n *VolumeWeightedNode) Pair() provider.Pair {
+	return n.pair
+}
+
+// Tick implements the Node interface.
+func (n *VolumeWeightedNode) Tick() provider.Tick {
+	var (
+		tm      time.Time
+		ticks   []provider.Tick
+		samples []weightedPrice
+	)
+
+	meta := n.Meta()
+
+	// Collect all ticks from branches and weighted prices from ticks
+	// that can be used to calculate the price.
+	for _, branch := range n.branches {
+		tick := branch.Tick()
+		if tm.IsZero() {
+			tm = tick.Time
+		}
+		if tick.Time.Before(tm) {
+			tm = tick.Time
+		}
+		ticks = append(ticks, tick)
+		if !n.pair.Equal(tick.Pair) {
+			continue
+		}
+		if err := tick.Validate(); err != nil {
+			continue
+		}
+		weight := tick.Volume24h
+		if weight == nil {
+			weight = n.fallbackWeight
+		}
+		if weight == nil || weight.Sign() <= 0 {
+			continue
+		}
+		samples = append(samples, weightedPrice{price: tick.Price, weight: weight})
+	}
+
+	// Verify that we have enough valid prices to calculate the price.
+	if len(samples) < n.min || len(samples) == 0 {
+		return provider.Tick{
+			Pair:     n.pair,
+			Meta:     meta,
+			SubTicks: ticks,
+			Error:    fmt.Errorf("not enough prices to calculate %s", n.method),
+		}
+	}
+
+	var price *bn.FloatNumber
+	switch n.method {
+	case VolumeWeightedMedian:
+		price = weightedMedian(samples)
+	case VolumeWeightedAverage:
+		price = weightedAverage(samples)
+	default:
+		return provider.Tick{
+			Pair:     n.pair,
+			Meta:     meta,
+			SubTicks: ticks,
+			Error:    fmt.Errorf("unknown method: %d", n.method),
+		}
+	}
+
+	return provider.Tick{
+		Pair:     n.pair,
+		Price:    price,
+		Time:     tm,
+		SubTicks: ticks,
+		Meta:     meta,
+	}
+}
+
+// Meta implements the Node interface.
+func (n *VolumeWeightedNode) Meta() provider.Meta {
+	meta := MapMeta{"type": n.method.String(), "min_sources": n.min}
+	if n.fallbackWeight != nil {
+		meta["fallback_weight"] = n.fallbackWeight.String()
+	}
+	return meta
+}
+
+type weightedPrice struct {
+	price  *bn.FloatNumber
+	weight *bn.FloatNumber
+}
+
+// weightedMedian returns the price at which the cumulative weight reaches
+// half of the total weight. If the cumulative weight is exactly half of the
+// total weight, the average of the two middle prices is returned.
+func weightedMedian(xs []weightedPrice) *bn.FloatNumber {
+	sort.Slice(xs, func(i, j int) bool {
+		return xs[i].price.Cmp(xs[j].price) < 0
+	})
+	total := bn.Float(0)
+	for _, x := range xs {
+		total = total.Add(x.weight)
+	}
+	half := total.Div(bn.Float(2))
+	cumulative := bn.Float(0)
+	for i, x := range xs {
+		cumulative = cumulative.Add(x.weight)
+		switch cumulative.Cmp(half) {
+		case 0:
+			if i+1 < len(xs) {
+				return x.price.Add(xs[i+1].price).Div(bn.Float(2))
+			}
+			return x.price
+		case 1:
+			return x.price
+		}
+	}
+	return xs[len(xs)-1].price
+}
+
+// weightedAverage returns the weighted arithmetic mean of prices.
+func weightedAverage(xs []weightedPrice) *bn.FloatNumber {
+	sum := bn.Float(0)
+	total := bn.Float(0)
+	for _, x := range xs {
+		sum = sum.Add(x.price.Mul(x.weight))
+		total = total.Add(x.weight)
+	}
+	return sum.Div(total)
+}
@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+func TestVolumeWeightedNode(t *testing.T) {
+	pair := provider.Pair{Base: "A", Quote: "B"}
+	tests := []struct {
+		name           string
+		method         VolumeWeightedMethod
+		ticks          []provider.Tick
+		min            int
+		fallbackWeight *bn.FloatNumber
+		expectedPrice  float64
+		wantErr        bool
+	}{
+		{
+			name:   "median with deep market",
+			method: VolumeWeightedMedian,
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Volume24h: bn.Float(1), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Volume24h: bn.Float(10), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(3), Volume24h: bn.Float(1), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(100), Volume24h: bn.Float(1), Pair: pair, Time: time.Now()},
+			},
+			min:           2,
+			expectedPrice: 2,
+		},
+		{
+			name:   "median with thin market",
+			method: VolumeWeightedMedian,
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Volume24h: bn.Float(10), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Volume24h: bn.Float(10), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(100), Volume24h: bn.Float(0.1), Pair: pair, Time: time.Now()},
+			},
+			min:           2,
+			expectedPrice: 2,
+		},
+		{
+			name:   "median with exactly half weight",
+			method: VolumeWeightedMedian,
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Volume24h: bn.Float(5), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Volume24h: bn.Float(5), Pair: pair, Time: time.Now()},
+			},
+			min:           2,
+			expectedPrice: 1.5,
+		},
+		{
+			name:   "vwap",
+			method: VolumeWeightedAverage,
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Volume24h: bn.Float(3), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Volume24h: bn.Float(1), Pair: pair, Time: time.Now()},
+			},
+			min:           2,
+			expectedPrice: 1.25,
+		},
+		{
+			name:   "vwap with fallback weight",
+			method: VolumeWeightedAverage,
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Volume24h: bn.Float(3), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Pair: pair, Time: time.Now()},
+			},
+			min:            2,
+			fallbackWeight: bn.Float(1),
+			expectedPrice:  1.25,
+		},
+		{
+			name:   "ticks without volume are ignored",
+			method: VolumeWeightedAverage,
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Volume24h: bn.Float(3), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Pair: pair, Time: time.Now()},
+			},
+			min:     2,
+			wantErr: true,
+		},
+		{
+			name:   "not enough ticks",
+			method: VolumeWeightedMedian,
+			ticks: []provider.Tick{
+				{Price: bn.Float(1), Volume24h: bn.Float(1), Pair: pair, Time: time.Now()},
+				{Price: bn.Float(2), Volume24h: bn.Float(1), Pair: pair, Time: time.Now(), Error: errors.New("err")},
+			},
+			min:     2,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := NewVolumeWeightedNode(pair, tt.method, tt.min, tt.fallbackWeight)
+			for _, tick := range tt.ticks {
+				n := new(mockNode)
+				n.On("Tick").Return(tick)
+				require.NoError(t, node.AddBranch(n))
+			}
+			tick := node.Tick()
+			if tt.wantErr {
+				assert.Error(t, tick.Validate())
+			} else {
+				require.NoError(t, tick.Validate())
+				assert.Equal(t, tt.expectedPrice, tick.Price.Float64())
+				assert.Equal(t, tt.method.String(), tick.Meta.Meta()["type"])
+				assert.Len(t, tick.SubTicks, len(tt.ticks))
+			}
+		})
+	}
+}
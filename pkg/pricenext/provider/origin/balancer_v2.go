@@ -0,0 +1,81 @@
+package origin
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+// balancerV2PairPrice is the PAIR_PRICE variable of the getLatest method.
+const balancerV2PairPrice = 0
+
+var balancerV2GetLatestMethod = abi.MustParseMethod("getLatest(uint8 variable) view returns (uint256)")
+
+// BalancerV2Options are options for the BalancerV2 origin.
+type BalancerV2Options struct {
+	// Client is an Ethereum RPC client.
+	Client rpc.RPC
+
+	// Pools is a list of Balancer V2 pools with price oracles (e.g.
+	// WeightedPool2Tokens or MetaStablePool) from which prices are read.
+	Pools []DEXPool
+
+	// BlockOffset is the number of blocks behind the latest block at which
+	// the pool state is read.
+	BlockOffset uint64
+
+	// Now returns the current time, which is used as the time of ticks.
+	// If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// BalancerV2 is an origin that reads prices from oracles of Balancer V2
+// pools.
+//
+// Balancer returns the price of the second token in units of the first one,
+// adjusted for token decimals, so the price is inverted to match the order
+// of tokens in the pool pair.
+type BalancerV2 struct {
+	*dexOrigin
+}
+
+// NewBalancerV2 creates a new BalancerV2 instance.
+func NewBalancerV2(opts BalancerV2Options) (*BalancerV2, error) {
+	d, err := newDEXOrigin(opts.Client, twoTokenPools(opts.Pools), opts.BlockOffset, balancerV2Adapter{}, opts.Now)
+	if err != nil {
+		return nil, err
+	}
+	return &BalancerV2{dexOrigin: d}, nil
+}
+
+type balancerV2Adapter struct{}
+
+func (balancerV2Adapter) tokenCalls(_ dexPool) ([]types.Call, error) {
+	// Prices returned by Balancer oracles already include token decimals.
+	return nil, nil
+}
+
+func (balancerV2Adapter) priceCall(pool dexPool, _ [2]uint8) (types.Call, error) {
+	input, err := balancerV2GetLatestMethod.EncodeArgs(balancerV2PairPrice)
+	if err != nil {
+		return types.Call{}, err
+	}
+	return types.Call{To: &pool.address, Input: input}, nil
+}
+
+func (balancerV2Adapter) price(_ dexPool, _ [2]uint8, result []byte) (*bn.FloatNumber, error) {
+	var price *big.Int
+	if err := balancerV2GetLatestMethod.DecodeValues(result, &price); err != nil {
+		return nil, fmt.Errorf("failed to decode pair price: %w", err)
+	}
+	if price.Sign() == 0 {
+		return bn.Float(0), nil
+	}
+	return pow10(18).Div(bn.Float(price)), nil
+}
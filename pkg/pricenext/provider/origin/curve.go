@@ -0,0 +1,116 @@
+package origin
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+var (
+	curveCoinsMethod = abi.MustParseMethod("coins(uint256) view returns (address)")
+	curveGetDyMethod = abi.MustParseMethod("get_dy(int128 i, int128 j, uint256 dx) view returns (uint256)")
+)
+
+// CurvePool describes a Curve pool.
+type CurvePool struct {
+	// Pair is the pair of tokens. Ticks for the inverted pair are calculated
+	// by inverting the price.
+	Pair provider.Pair
+
+	// Address is the address of the pool contract.
+	Address types.Address
+
+	// BaseIndex and QuoteIndex are indices of the base and quote tokens
+	// in the pool.
+	BaseIndex  int
+	QuoteIndex int
+}
+
+// CurveOptions are options for the Curve origin.
+type CurveOptions struct {
+	// Client is an Ethereum RPC client.
+	Client rpc.RPC
+
+	// Pools is a list of pools from which prices are read.
+	Pools []CurvePool
+
+	// BlockOffset is the number of blocks behind the latest block at which
+	// the pool state is read.
+	BlockOffset uint64
+
+	// Now returns the current time, which is used as the time of ticks.
+	// If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Curve is an origin that reads prices from Curve pools.
+//
+// The price is the amount of the quote token received for one base token,
+// as returned by the get_dy method, so it includes the pool fee.
+type Curve struct {
+	*dexOrigin
+}
+
+// NewCurve creates a new Curve instance.
+func NewCurve(opts CurveOptions) (*Curve, error) {
+	pools := make([]dexPool, len(opts.Pools))
+	for i, p := range opts.Pools {
+		if p.BaseIndex < 0 || p.QuoteIndex < 0 {
+			return nil, fmt.Errorf("invalid token indices for pair %s", p.Pair)
+		}
+		if p.BaseIndex == p.QuoteIndex {
+			return nil, fmt.Errorf("base and quote token indices must differ for pair %s", p.Pair)
+		}
+		pools[i] = dexPool{pair: p.Pair, address: p.Address, indices: [2]int{p.BaseIndex, p.QuoteIndex}}
+	}
+	d, err := newDEXOrigin(opts.Client, pools, opts.BlockOffset, curveAdapter{}, opts.Now)
+	if err != nil {
+		return nil, err
+	}
+	return &Curve{dexOrigin: d}, nil
+}
+
+type curveAdapter struct{}
+
+func (curveAdapter) tokenCalls(pool dexPool) ([]types.Call, error) {
+	calls := make([]types.Call, 2)
+	for i, idx := range pool.indices {
+		input, err := curveCoinsMethod.EncodeArgs(idx)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = types.Call{To: &pool.address, Input: input}
+	}
+	return calls, nil
+}
+
+func (curveAdapter) priceCall(pool dexPool, decimals [2]uint8) (types.Call, error) {
+	input, err := curveGetDyMethod.EncodeArgs(
+		pool.indices[0],
+		pool.indices[1],
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals[0])), nil),
+	)
+	if err != nil {
+		return types.Call{}, err
+	}
+	return types.Call{To: &pool.address, Input: input}, nil
+}
+
+func (curveAdapter) price(_ dexPool, decimals [2]uint8, result []byte) (*bn.FloatNumber, error) {
+	var dy *big.Int
+	if err := curveGetDyMethod.DecodeValues(result, &dy); err != nil {
+		return nil, fmt.Errorf("failed to decode get_dy result: %w", err)
+	}
+	if dy.Sign() == 0 {
+		return nil, errors.New("pool has no liquidity")
+	}
+	return bn.Float(dy).Div(pow10(decimals[1])), nil
+}
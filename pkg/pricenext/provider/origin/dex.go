@@ -0,0 +1,395 @@
+package origin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+var erc20DecimalsMethod = abi.MustParseMethod("decimals() view returns (uint8)")
+
+// nativeETHAddress is the placeholder address used by some pools, e.g. Curve
+// pools with native ETH, instead of a token address.
+var nativeETHAddress = types.MustAddressFromHex("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+// nativeETHDecimals is the number of decimals of native ETH.
+const nativeETHDecimals = 18
+
+// DEXPool describes a DEX liquidity pool.
+type DEXPool struct {
+	// Pair is the pair of tokens in the pool. The base token must be the
+	// first token in the pool (e.g. token0 in Uniswap pools) and the quote
+	// token must be the second one. Ticks for the inverted pair are
+	// calculated by inverting the price.
+	Pair provider.Pair
+
+	// Address is the address of the pool contract.
+	Address types.Address
+}
+
+// dexPool is a pool used internally by dexOrigin.
+type dexPool struct {
+	pair    provider.Pair
+	address types.Address
+
+	// indices of the base and quote tokens in the pool, used only by pools
+	// with more than two tokens.
+	indices [2]int
+}
+
+// dexAdapter implements a DEX specific logic used by dexOrigin.
+type dexAdapter interface {
+	// tokenCalls returns calls that return the addresses of the base and
+	// quote tokens of the pool. If nil is returned, token decimals are not
+	// fetched.
+	tokenCalls(pool dexPool) ([]types.Call, error)
+
+	// priceCall returns a call that is used to calculate the pool price.
+	priceCall(pool dexPool, decimals [2]uint8) (types.Call, error)
+
+	// price returns the price of the base token in the quote token using
+	// the result of the price call.
+	price(pool dexPool, decimals [2]uint8, result []byte) (*bn.FloatNumber, error)
+}
+
+// dexOrigin is a base implementation for origins that read prices from DEX
+// pools using multicall.
+//
+// Token decimals are fetched once per pool and cached, because they never
+// change.
+type dexOrigin struct {
+	client      rpc.RPC
+	pools       []dexPool
+	blockOffset uint64
+	adapter     dexAdapter
+	now         func() time.Time
+
+	mu       sync.Mutex
+	decimals map[types.Address][2]uint8 // pool address -> decimals
+}
+
+func newDEXOrigin(
+	client rpc.RPC,
+	pools []dexPool,
+	blockOffset uint64,
+	adapter dexAdapter,
+	now func() time.Time,
+) (*dexOrigin, error) {
+
+	if client == nil {
+		return nil, errors.New("ethereum client must not be nil")
+	}
+	seen := make(map[provider.Pair]bool)
+	for _, p := range pools {
+		if p.pair.Empty() {
+			return nil, errors.New("pool pair must not be empty")
+		}
+		if seen[p.pair] || seen[p.pair.Invert()] {
+			return nil, fmt.Errorf("duplicate pool for pair %s", p.pair)
+		}
+		seen[p.pair] = true
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &dexOrigin{
+		client:      client,
+		pools:       pools,
+		blockOffset: blockOffset,
+		adapter:     adapter,
+		now:         now,
+		decimals:    make(map[types.Address][2]uint8),
+	}, nil
+}
+
+// FetchTicks implements the Origin interface.
+func (d *dexOrigin) FetchTicks(ctx context.Context, pairs []provider.Pair) []provider.Tick {
+	ticks := make([]provider.Tick, len(pairs))
+	pools := make([]dexPool, len(pairs))
+	inverted := make([]bool, len(pairs))
+	var usedPools []dexPool
+	for i, pair := range pairs {
+		pool, inv, ok := d.findPool(pair)
+		if !ok {
+			ticks[i] = provider.Tick{Pair: pair, Error: ErrPairNotSupported{Pair: pair}}
+			continue
+		}
+		pools[i], inverted[i] = pool, inv
+		usedPools = append(usedPools, pool)
+	}
+	if len(usedPools) == 0 {
+		return ticks
+	}
+
+	// Fetch token decimals.
+	decimals, decimalsErrs := d.fetchDecimals(ctx, usedPools)
+
+	// Fetch pool prices.
+	var (
+		calls   []types.Call
+		callIdx = make([]int, len(pairs))
+	)
+	for i, pool := range pools {
+		callIdx[i] = -1
+		if ticks[i].Error != nil {
+			continue
+		}
+		if err := decimalsErrs[pool.address]; err != nil {
+			ticks[i] = provider.Tick{Pair: pairs[i], Error: fmt.Errorf("failed to fetch token decimals: %w", err)}
+			continue
+		}
+		call, err := d.adapter.priceCall(pool, decimals[pool.address])
+		if err != nil {
+			ticks[i] = provider.Tick{Pair: pairs[i], Error: fmt.Errorf("failed to prepare call: %w", err)}
+			continue
+		}
+		callIdx[i] = len(calls)
+		calls = append(calls, call)
+	}
+	if len(calls) == 0 {
+		return ticks
+	}
+	results, err := multiCallWithOffset(ctx, d.client, calls, d.blockOffset)
+	if err != nil {
+		return withError(pairs, d.now(), fmt.Errorf("failed to call contract: %w", err))
+	}
+	for i, pool := range pools {
+		if callIdx[i] < 0 {
+			continue
+		}
+		ticks[i] = provider.Tick{Pair: pairs[i], Time: d.now()}
+		result := results[callIdx[i]]
+		if err := callResultError(result); err != nil {
+			ticks[i].Error = err
+			continue
+		}
+		price, err := d.adapter.price(pool, decimals[pool.address], result)
+		if err != nil {
+			ticks[i].Error = err
+			continue
+		}
+		if price.Sign() <= 0 {
+			ticks[i].Error = errors.New("pool price is zero")
+			continue
+		}
+		if inverted[i] {
+			price = price.Inv()
+		}
+		ticks[i].Price = price
+	}
+	return ticks
+}
+
+// findPool returns the pool for the given pair. The second return value is
+// true if the pool has inverted pair.
+func (d *dexOrigin) findPool(pair provider.Pair) (dexPool, bool, bool) {
+	for _, p := range d.pools {
+		if p.pair.Equal(pair) {
+			return p, false, true
+		}
+		if p.pair.Equal(pair.Invert()) {
+			return p, true, true
+		}
+	}
+	return dexPool{}, false, false
+}
+
+// fetchDecimals returns decimals of base and quote tokens for the given
+// pools, indexed by pool address. Errors are returned per pool, so a pool
+// with a broken token does not affect other pools.
+func (d *dexOrigin) fetchDecimals(ctx context.Context, pools []dexPool) (map[types.Address][2]uint8, map[types.Address]error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	errs := make(map[types.Address]error)
+
+	// Find pools for which decimals are not known yet.
+	var (
+		missing []dexPool
+		calls   []types.Call
+		seen    = make(map[types.Address]bool)
+	)
+	for _, pool := range pools {
+		if _, ok := d.decimals[pool.address]; ok || seen[pool.address] {
+			continue
+		}
+		seen[pool.address] = true
+		tokenCalls, err := d.adapter.tokenCalls(pool)
+		if err != nil {
+			errs[pool.address] = err
+			continue
+		}
+		if tokenCalls == nil {
+			d.decimals[pool.address] = [2]uint8{}
+			continue
+		}
+		missing = append(missing, pool)
+		calls = append(calls, tokenCalls...)
+	}
+	if len(missing) > 0 {
+		d.fetchMissingDecimals(ctx, missing, calls, errs)
+	}
+
+	decimals := make(map[types.Address][2]uint8, len(pools))
+	for _, pool := range pools {
+		if dec, ok := d.decimals[pool.address]; ok {
+			decimals[pool.address] = dec
+		}
+	}
+	return decimals, errs
+}
+
+// fetchMissingDecimals fetches token addresses using the given calls and
+// then decimals of these tokens. Results are stored in the decimals cache,
+// and errors are stored in the errs map.
+func (d *dexOrigin) fetchMissingDecimals(
+	ctx context.Context,
+	pools []dexPool,
+	calls []types.Call,
+	errs map[types.Address]error,
+) {
+
+	setErr := func(pools []dexPool, err error) {
+		for _, pool := range pools {
+			errs[pool.address] = err
+		}
+	}
+
+	// Fetch token addresses.
+	results, err := ethereum.MultiCall(ctx, d.client, calls, types.LatestBlockNumber)
+	if err != nil {
+		setErr(pools, err)
+		return
+	}
+	if len(results) != len(calls) {
+		setErr(pools, fmt.Errorf("unexpected number of results: %d", len(results)))
+		return
+	}
+	var (
+		resolved []dexPool
+		tokens   []types.Address
+	)
+	for i, pool := range pools {
+		var poolTokens [2]types.Address
+		for j := 0; j < 2; j++ {
+			result := results[i*2+j]
+			if err = callResultError(result); err == nil && len(result) != abi.WordLength {
+				err = fmt.Errorf("unexpected result length: %d", len(result))
+			}
+			if err != nil {
+				break
+			}
+			poolTokens[j] = types.MustAddressFromBytes(result[abi.WordLength-types.AddressLength:])
+		}
+		if err != nil {
+			errs[pool.address] = err
+			continue
+		}
+		resolved = append(resolved, pool)
+		tokens = append(tokens, poolTokens[:]...)
+	}
+	if len(resolved) == 0 {
+		return
+	}
+
+	// Fetch token decimals. Native ETH placeholder addresses are not
+	// contracts, so they are not called.
+	input, err := erc20DecimalsMethod.EncodeArgs()
+	if err != nil {
+		setErr(resolved, err)
+		return
+	}
+	calls = nil
+	callIdx := make([]int, len(tokens))
+	for i := range tokens {
+		callIdx[i] = -1
+		if tokens[i] == nativeETHAddress {
+			continue
+		}
+		callIdx[i] = len(calls)
+		calls = append(calls, types.Call{To: &tokens[i], Input: input})
+	}
+	if len(calls) > 0 {
+		results, err = ethereum.MultiCall(ctx, d.client, calls, types.LatestBlockNumber)
+		if err != nil {
+			setErr(resolved, err)
+			return
+		}
+		if len(results) != len(calls) {
+			setErr(resolved, fmt.Errorf("unexpected number of results: %d", len(results)))
+			return
+		}
+	}
+	for i, pool := range resolved {
+		var decimals [2]uint8
+		err = nil
+		for j := 0; j < 2; j++ {
+			idx := callIdx[i*2+j]
+			if idx < 0 {
+				decimals[j] = nativeETHDecimals
+				continue
+			}
+			if err = callResultError(results[idx]); err != nil {
+				break
+			}
+			if err = erc20DecimalsMethod.DecodeValues(results[idx], &decimals[j]); err != nil {
+				err = fmt.Errorf("failed to decode decimals of %s: %w", tokens[i*2+j], err)
+				break
+			}
+		}
+		if err != nil {
+			errs[pool.address] = err
+			continue
+		}
+		d.decimals[pool.address] = decimals
+	}
+}
+
+// multiCallWithOffset executes the given calls using multicall at the block
+// that is blockOffset blocks behind the latest block.
+func multiCallWithOffset(ctx context.Context, client rpc.RPC, calls []types.Call, blockOffset uint64) ([][]byte, error) {
+	blockNumber, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+	results, err := ethereum.MultiCall(
+		ctx,
+		client,
+		calls,
+		types.BlockNumberFromBigInt(bn.Int(blockNumber).Sub(blockOffset).BigInt()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("unexpected number of results: %d", len(results))
+	}
+	return results, nil
+}
+
+// callResultError returns an error if the call result is a revert or
+// a panic.
+func callResultError(result []byte) error {
+	if abi.IsRevert(result) {
+		return fmt.Errorf("contract reverted: %s", abi.DecodeRevert(result))
+	}
+	if abi.IsPanic(result) {
+		return fmt.Errorf("contract panicked: %s", abi.DecodePanic(result))
+	}
+	return nil
+}
+
+// pow10 returns 10^n as a FloatNumber.
+func pow10(n uint8) *bn.FloatNumber {
+	return bn.Float(bn.Int(10).Pow(n))
+}
@@ -0,0 +1,312 @@
+package origin
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+var testMulticallMethod = abi.MustParseMethod(`
+	function aggregate(
+		(address target, bytes callData)[] memory calls
+	) public returns (
+		uint256 blockNumber,
+		bytes[] memory returnData
+	)`,
+)
+
+// fakeChain is an RPC client that executes multicall calls using
+// registered contract methods.
+type fakeChain struct {
+	mocks.RPC
+
+	// methods maps contract addresses and method selectors to functions
+	// returning ABI encoded results.
+	methods map[types.Address]map[abi.FourBytes]func(input []byte) []byte
+	calls   int
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{methods: make(map[types.Address]map[abi.FourBytes]func([]byte) []byte)}
+}
+
+func (c *fakeChain) register(address types.Address, method *abi.Method, fn func(input []byte) []any) {
+	if c.methods[address] == nil {
+		c.methods[address] = make(map[abi.FourBytes]func([]byte) []byte)
+	}
+	c.methods[address][method.FourBytes()] = func(input []byte) []byte {
+		return abi.MustEncodeValues(method.Outputs(), fn(input)...)
+	}
+}
+
+func (c *fakeChain) ChainID(_ context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (c *fakeChain) BlockNumber(_ context.Context) (*big.Int, error) {
+	return big.NewInt(100), nil
+}
+
+func (c *fakeChain) Call(_ context.Context, call types.Call, _ types.BlockNumber) ([]byte, error) {
+	type multicallCall struct {
+		Target types.Address `abi:"target"`
+		Data   []byte        `abi:"callData"`
+	}
+	c.calls++
+	var calls []multicallCall
+	if err := testMulticallMethod.DecodeArgs(call.Input, &calls); err != nil {
+		return nil, err
+	}
+	results := make([][]byte, len(calls))
+	for i, mc := range calls {
+		fn, ok := c.methods[mc.Target][abi.FourBytes(mc.Data[:4])]
+		if !ok {
+			results[i] = abi.MustEncodeValues(abi.MustParseType("(string)"), "not implemented")
+			results[i] = append([]byte{0x08, 0xc3, 0x79, 0xa0}, results[i]...)
+			continue
+		}
+		results[i] = fn(mc.Data)
+	}
+	return abi.MustEncodeValues(testMulticallMethod.Outputs(), big.NewInt(100), results), nil
+}
+
+var (
+	testToken0 = types.MustAddressFromHex("0x1000000000000000000000000000000000000001")
+	testToken1 = types.MustAddressFromHex("0x1000000000000000000000000000000000000002")
+	testPool   = types.MustAddressFromHex("0x2000000000000000000000000000000000000001")
+)
+
+// registerTokens registers an ERC20 decimals method for test tokens.
+// Token0 has 18 decimals and token1 has 6 decimals.
+func (c *fakeChain) registerTokens() {
+	c.register(testToken0, erc20DecimalsMethod, func([]byte) []any { return []any{uint8(18)} })
+	c.register(testToken1, erc20DecimalsMethod, func([]byte) []any { return []any{uint8(6)} })
+}
+
+func (c *fakeChain) registerUniswapTokens() {
+	c.registerTokens()
+	c.register(testPool, uniswapToken0Method, func([]byte) []any { return []any{testToken0} })
+	c.register(testPool, uniswapToken1Method, func([]byte) []any { return []any{testToken1} })
+}
+
+func assertPrice(t *testing.T, expected float64, tick provider.Tick) {
+	require.NoError(t, tick.Error)
+	require.NotNil(t, tick.Price)
+	assert.InDelta(t, expected, tick.Price.Float64(), expected*1e-9)
+	assert.False(t, tick.Time.IsZero())
+}
+
+func TestUniswapV2_FetchTicks(t *testing.T) {
+	chain := newFakeChain()
+	chain.registerUniswapTokens()
+	chain.register(testPool, uniswapV2GetReservesMethod, func([]byte) []any {
+		// 10 ETH and 20,000 USDC.
+		return []any{
+			bn.Int(10).Mul(bn.Int(10).Pow(18)).BigInt(),
+			bn.Int(20000).Mul(bn.Int(10).Pow(6)).BigInt(),
+			uint32(0),
+		}
+	})
+
+	u, err := NewUniswapV2(UniswapV2Options{
+		Client: chain,
+		Pools:  []DEXPool{{Pair: provider.Pair{Base: "ETH", Quote: "USDC"}, Address: testPool}},
+	})
+	require.NoError(t, err)
+
+	ticks := u.FetchTicks(context.Background(), []provider.Pair{
+		{Base: "ETH", Quote: "USDC"},
+		{Base: "USDC", Quote: "ETH"},
+		{Base: "BTC", Quote: "USD"},
+	})
+	require.Len(t, ticks, 3)
+	assertPrice(t, 2000, ticks[0])
+	assertPrice(t, 0.0005, ticks[1])
+	assert.ErrorIs(t, ticks[2].Error, ErrPairNotSupported{Pair: provider.Pair{Base: "BTC", Quote: "USD"}})
+
+	// Decimals must be cached, so only the price multicall is executed.
+	calls := chain.calls
+	u.FetchTicks(context.Background(), []provider.Pair{{Base: "ETH", Quote: "USDC"}})
+	assert.Equal(t, calls+1, chain.calls)
+}
+
+func TestUniswapV2_NoLiquidity(t *testing.T) {
+	chain := newFakeChain()
+	chain.registerUniswapTokens()
+	chain.register(testPool, uniswapV2GetReservesMethod, func([]byte) []any {
+		return []any{big.NewInt(0), big.NewInt(0), uint32(0)}
+	})
+
+	u, err := NewUniswapV2(UniswapV2Options{
+		Client: chain,
+		Pools:  []DEXPool{{Pair: provider.Pair{Base: "ETH", Quote: "USDC"}, Address: testPool}},
+	})
+	require.NoError(t, err)
+
+	ticks := u.FetchTicks(context.Background(), []provider.Pair{{Base: "ETH", Quote: "USDC"}})
+	require.Len(t, ticks, 1)
+	assert.EqualError(t, ticks[0].Error, "pool has no liquidity")
+}
+
+func TestUniswapV3_FetchTicks(t *testing.T) {
+	chain := newFakeChain()
+	chain.registerUniswapTokens()
+	chain.register(testPool, uniswapV3Slot0Method, func([]byte) []any {
+		// sqrtPriceX96 = sqrt(2000 * 10^6 / 10^18) * 2^96
+		sqrtPrice := new(big.Float).SetPrec(256).Sqrt(big.NewFloat(2000e-12).SetPrec(256))
+		sqrtPriceX96, _ := sqrtPrice.Mul(sqrtPrice, new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))).Int(nil)
+		return []any{sqrtPriceX96, int32(0), uint16(0), uint16(0), uint16(0), uint8(0), true}
+	})
+
+	u, err := NewUniswapV3(UniswapV3Options{
+		Client: chain,
+		Pools:  []DEXPool{{Pair: provider.Pair{Base: "ETH", Quote: "USDC"}, Address: testPool}},
+	})
+	require.NoError(t, err)
+
+	ticks := u.FetchTicks(context.Background(), []provider.Pair{
+		{Base: "ETH", Quote: "USDC"},
+		{Base: "USDC", Quote: "ETH"},
+	})
+	require.Len(t, ticks, 2)
+	assertPrice(t, 2000, ticks[0])
+	assertPrice(t, 0.0005, ticks[1])
+}
+
+func TestCurve_FetchTicks(t *testing.T) {
+	chain := newFakeChain()
+	chain.registerTokens()
+	chain.register(testPool, curveCoinsMethod, func(input []byte) []any {
+		var idx *big.Int
+		require.NoError(t, curveCoinsMethod.DecodeArgs(input, &idx))
+		return []any{map[int64]types.Address{2: testToken0, 3: testToken1}[idx.Int64()]}
+	})
+	chain.register(testPool, curveGetDyMethod, func(input []byte) []any {
+		var i, j, dx *big.Int
+		require.NoError(t, curveGetDyMethod.DecodeArgs(input, &i, &j, &dx))
+		assert.Equal(t, int64(2), i.Int64())
+		assert.Equal(t, int64(3), j.Int64())
+		assert.Equal(t, bn.Int(10).Pow(18).BigInt(), dx)
+		return []any{big.NewInt(1_001_000)}
+	})
+
+	c, err := NewCurve(CurveOptions{
+		Client: chain,
+		Pools: []CurvePool{{
+			Pair:       provider.Pair{Base: "DAI", Quote: "USDC"},
+			Address:    testPool,
+			BaseIndex:  2,
+			QuoteIndex: 3,
+		}},
+	})
+	require.NoError(t, err)
+
+	ticks := c.FetchTicks(context.Background(), []provider.Pair{{Base: "DAI", Quote: "USDC"}})
+	require.Len(t, ticks, 1)
+	assertPrice(t, 1.001, ticks[0])
+}
+
+func TestCurve_NativeETH(t *testing.T) {
+	var (
+		ethPool    = types.MustAddressFromHex("0x2000000000000000000000000000000000000002")
+		brokenPool = types.MustAddressFromHex("0x2000000000000000000000000000000000000003")
+		noToken    = types.MustAddressFromHex("0x1000000000000000000000000000000000000009")
+	)
+	chain := newFakeChain()
+	chain.registerTokens()
+
+	// Pool with native ETH at index 0 and an 18 decimals token at index 1.
+	chain.register(ethPool, curveCoinsMethod, func(input []byte) []any {
+		var idx *big.Int
+		require.NoError(t, curveCoinsMethod.DecodeArgs(input, &idx))
+		return []any{map[int64]types.Address{0: nativeETHAddress, 1: testToken0}[idx.Int64()]}
+	})
+	chain.register(ethPool, curveGetDyMethod, func(input []byte) []any {
+		var i, j, dx *big.Int
+		require.NoError(t, curveGetDyMethod.DecodeArgs(input, &i, &j, &dx))
+		assert.Equal(t, bn.Int(10).Pow(18).BigInt(), dx)
+		return []any{bn.Int(999).Mul(bn.Int(10).Pow(15)).BigInt()}
+	})
+
+	// Pool with a token that does not implement the decimals method.
+	chain.register(brokenPool, curveCoinsMethod, func(input []byte) []any {
+		var idx *big.Int
+		require.NoError(t, curveCoinsMethod.DecodeArgs(input, &idx))
+		return []any{map[int64]types.Address{0: testToken0, 1: noToken}[idx.Int64()]}
+	})
+
+	c, err := NewCurve(CurveOptions{
+		Client: chain,
+		Pools: []CurvePool{
+			{Pair: provider.Pair{Base: "STETH", Quote: "ETH"}, Address: ethPool, BaseIndex: 1, QuoteIndex: 0},
+			{Pair: provider.Pair{Base: "FOO", Quote: "BAR"}, Address: brokenPool, BaseIndex: 0, QuoteIndex: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	ticks := c.FetchTicks(context.Background(), []provider.Pair{
+		{Base: "STETH", Quote: "ETH"},
+		{Base: "FOO", Quote: "BAR"},
+	})
+	require.Len(t, ticks, 2)
+	assertPrice(t, 0.999, ticks[0])
+	assert.ErrorContains(t, ticks[1].Error, "failed to fetch token decimals")
+}
+
+func TestNewCurve_InvalidIndices(t *testing.T) {
+	_, err := NewCurve(CurveOptions{
+		Client: newFakeChain(),
+		Pools: []CurvePool{{
+			Pair:       provider.Pair{Base: "DAI", Quote: "USDC"},
+			Address:    testPool,
+			BaseIndex:  1,
+			QuoteIndex: 1,
+		}},
+	})
+	assert.Error(t, err)
+}
+
+func TestBalancerV2_FetchTicks(t *testing.T) {
+	chain := newFakeChain()
+	chain.register(testPool, balancerV2GetLatestMethod, func(input []byte) []any {
+		var variable uint8
+		require.NoError(t, balancerV2GetLatestMethod.DecodeArgs(input, &variable))
+		assert.Equal(t, uint8(balancerV2PairPrice), variable)
+		// Price of the second token in units of the first one.
+		return []any{bn.Int(4).Mul(bn.Int(10).Pow(17)).BigInt()}
+	})
+
+	b, err := NewBalancerV2(BalancerV2Options{
+		Client: chain,
+		Pools:  []DEXPool{{Pair: provider.Pair{Base: "WSTETH", Quote: "WETH"}, Address: testPool}},
+	})
+	require.NoError(t, err)
+
+	ticks := b.FetchTicks(context.Background(), []provider.Pair{
+		{Base: "WSTETH", Quote: "WETH"},
+		{Base: "WETH", Quote: "WSTETH"},
+	})
+	require.Len(t, ticks, 2)
+	assertPrice(t, 2.5, ticks[0])
+	assertPrice(t, 0.4, ticks[1])
+}
+
+func TestNewDEXOrigin_DuplicatePools(t *testing.T) {
+	_, err := NewUniswapV2(UniswapV2Options{
+		Client: newFakeChain(),
+		Pools: []DEXPool{
+			{Pair: provider.Pair{Base: "ETH", Quote: "USDC"}, Address: testPool},
+			{Pair: provider.Pair{Base: "USDC", Quote: "ETH"}, Address: testPool},
+		},
+	})
+	assert.EqualError(t, err, "duplicate pool for pair USDC/ETH")
+}
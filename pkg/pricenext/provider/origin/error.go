@@ -16,13 +16,13 @@ func (e ErrPairNotSupported) Error() string {
 }
 
 // withError is a helper function which returns a list of ticks for the given
-// pairs with the given error.
-func withError(pairs []provider.Pair, err error) []provider.Tick {
+// pairs with the given error and time.
+func withError(pairs []provider.Pair, tm time.Time, err error) []provider.Tick {
 	var ticks []provider.Tick
 	for _, pair := range pairs {
 		ticks = append(ticks, provider.Tick{
 			Pair:  pair,
-			Time:  time.Now(),
+			Time:  tm,
 			Error: err,
 		})
 	}
@@ -3,44 +3,99 @@ package origin
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/defiweb/go-eth/abi"
 	"github.com/defiweb/go-eth/rpc"
 	"github.com/defiweb/go-eth/types"
 
-	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum"
 	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
 )
 
+// GenericETHContract describes a contract method used to read a price.
 type GenericETHContract struct {
 	Method    *abi.Method
 	Contract  types.Address
 	Arguments []any
 	Decimals  uint8
+
+	// PriceIndex is the index of the method return value that contains
+	// the price. The value must be an integer.
+	PriceIndex int
+
+	// TimestampIndex is the index of the method return value that contains
+	// the Unix timestamp of the price. The value must be an integer. If nil,
+	// ticks are stamped with the time of the fetch.
+	TimestampIndex *int
+
+	// Scale is an optional factor by which the price is multiplied after
+	// applying decimals.
+	Scale *bn.FloatNumber
+
+	// Invert indicates that the price must be inverted after scaling.
+	Invert bool
+}
+
+// GenericEVMOptions are options for the GenericEVM origin.
+type GenericEVMOptions struct {
+	// Client is an Ethereum RPC client.
+	Client rpc.RPC
+
+	// Contracts describes contract methods used to read prices for pairs.
+	Contracts map[provider.Pair]GenericETHContract
+
+	// BlockOffset is the number of blocks behind the latest block at which
+	// contracts are called.
+	BlockOffset uint64
+
+	// Now returns the current time, which is used as the time of ticks
+	// for contracts that do not return a timestamp. If nil, time.Now is
+	// used.
+	Now func() time.Time
 }
 
 // GenericEVM is a generic EVM-based price provider.
 //
 // It can fetch a price from a smart contract method that returns a price
-// as an uint256 value.
+// as an integer value, optionally along with a timestamp of the price.
 type GenericEVM struct {
 	client      rpc.RPC
 	contracts   map[provider.Pair]GenericETHContract
 	blockOffset uint64
+	now         func() time.Time
 }
 
-func NewGenericEVM(
-	client rpc.RPC,
-	contracts map[provider.Pair]GenericETHContract,
-	blockOffset uint64,
-) (*GenericEVM, error) {
-
+// NewGenericEVM creates a new GenericEVM instance.
+func NewGenericEVM(opts GenericEVMOptions) (*GenericEVM, error) {
+	for pair, contract := range opts.Contracts {
+		if contract.Method == nil {
+			return nil, fmt.Errorf("method for pair %s is not set", pair)
+		}
+		if err := validateOutputIndex(contract.Method, contract.PriceIndex); err != nil {
+			return nil, fmt.Errorf("invalid price index for pair %s: %w", pair, err)
+		}
+		if contract.TimestampIndex != nil {
+			if err := validateOutputIndex(contract.Method, *contract.TimestampIndex); err != nil {
+				return nil, fmt.Errorf("invalid timestamp index for pair %s: %w", pair, err)
+			}
+			if *contract.TimestampIndex == contract.PriceIndex {
+				return nil, fmt.Errorf("price and timestamp indices for pair %s must differ", pair)
+			}
+		}
+		if contract.Scale != nil && contract.Scale.Sign() <= 0 {
+			return nil, fmt.Errorf("scale for pair %s must be positive", pair)
+		}
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
 	return &GenericEVM{
-		client:      client,
-		contracts:   contracts,
-		blockOffset: blockOffset,
+		client:      opts.Client,
+		contracts:   opts.Contracts,
+		blockOffset: opts.BlockOffset,
+		now:         opts.Now,
 	}, nil
 }
 
@@ -50,60 +105,86 @@ func (g GenericEVM) FetchTicks(ctx context.Context, pairs []provider.Pair) []pro
 	for i, pair := range pairs {
 		contract, ok := g.contracts[pair]
 		if !ok {
-			return withError(pairs, ErrPairNotSupported{Pair: pair})
+			return withError(pairs, g.now(), ErrPairNotSupported{Pair: pair})
 		}
 		input, err := contract.Method.EncodeArgs(contract.Arguments...)
 		if err != nil {
-			return withError(pairs, fmt.Errorf("failed to encode arguments: %w", err))
+			return withError(pairs, g.now(), fmt.Errorf("failed to encode arguments: %w", err))
 		}
 		calls[i] = types.Call{
 			To:    &contract.Contract,
 			Input: input,
 		}
 	}
-	blockNumber, err := g.client.BlockNumber(ctx)
-	if err != nil {
-		return withError(pairs, fmt.Errorf("failed to get block number: %w", err))
-	}
-	results, err := ethereum.MultiCall(
-		ctx,
-		g.client,
-		calls,
-		types.BlockNumberFromBigInt(bn.Int(blockNumber).Sub(g.blockOffset).BigInt()),
-	)
+	results, err := multiCallWithOffset(ctx, g.client, calls, g.blockOffset)
 	if err != nil {
-		return withError(pairs, fmt.Errorf("failed to call contract: %w", err))
-	}
-	if len(results) != len(pairs) {
-		return withError(pairs, fmt.Errorf("unexpected number of results: %d", len(results)))
+		return withError(pairs, g.now(), fmt.Errorf("failed to call contract: %w", err))
 	}
 	for i, pair := range pairs {
-		if abi.IsRevert(results[i]) {
-			ticks[i] = provider.Tick{
-				Pair:  pairs[i],
-				Error: fmt.Errorf("contract reverted: %s", abi.DecodeRevert(results[i])),
-			}
-			continue
-		}
-		if abi.IsPanic(results[i]) {
-			ticks[i] = provider.Tick{
-				Pair:  pairs[i],
-				Error: fmt.Errorf("contract panicked: %s", abi.DecodePanic(results[i])),
-			}
+		if err := callResultError(results[i]); err != nil {
+			ticks[i] = provider.Tick{Pair: pair, Error: err}
 			continue
 		}
-		if len(results[i]) != abi.WordLength {
-			ticks[i] = provider.Tick{
-				Pair:  pairs[i],
-				Error: fmt.Errorf("unexpected result length: %d", len(results[i])),
-			}
+		price, tm, err := g.contracts[pair].decode(results[i], g.now())
+		if err != nil {
+			ticks[i] = provider.Tick{Pair: pair, Error: err}
 			continue
 		}
 		ticks[i] = provider.Tick{
-			Pair:  pairs[i],
-			Price: bn.Float(bn.Int(results[i])).Div(bn.Int(10).Pow(g.contracts[pair].Decimals)),
-			Time:  time.Now(),
+			Pair:  pair,
+			Price: price,
+			Time:  tm,
 		}
 	}
 	return ticks
 }
+
+// decode decodes the price and its time from the result of a method call.
+// If the method does not return a timestamp, the given time is used.
+func (c GenericETHContract) decode(result []byte, now time.Time) (*bn.FloatNumber, time.Time, error) {
+	var (
+		price     = new(big.Int)
+		timestamp = new(big.Int)
+		vals      = make([]any, c.Method.Outputs().Size())
+	)
+	vals[c.PriceIndex] = price
+	if c.TimestampIndex != nil {
+		vals[*c.TimestampIndex] = timestamp
+	}
+	if err := c.Method.DecodeValues(result, vals...); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to decode result: %w", err)
+	}
+	if price.Sign() <= 0 {
+		return nil, time.Time{}, fmt.Errorf("price is not positive: %s", price)
+	}
+	p := bn.Float(price).Div(bn.Int(10).Pow(c.Decimals))
+	if c.Scale != nil {
+		p = p.Mul(c.Scale)
+	}
+	if c.Invert {
+		p = p.Inv()
+	}
+	tm := now
+	if c.TimestampIndex != nil {
+		if timestamp.Sign() <= 0 || !timestamp.IsInt64() {
+			return nil, time.Time{}, fmt.Errorf("invalid timestamp: %s", timestamp)
+		}
+		tm = time.Unix(timestamp.Int64(), 0)
+	}
+	return p, tm, nil
+}
+
+// validateOutputIndex verifies that the method return value at the given
+// index exists and is an integer.
+func validateOutputIndex(method *abi.Method, idx int) error {
+	outputs := method.Outputs().Elements()
+	if idx < 0 || idx >= len(outputs) {
+		return fmt.Errorf("method %s has no return value at index %d", method.Name(), idx)
+	}
+	switch outputs[idx].Type.(type) {
+	case *abi.UintType, *abi.IntType:
+		return nil
+	default:
+		return fmt.Errorf("return value at index %d is not an integer", idx)
+	}
+}
@@ -0,0 +1,162 @@
+package origin
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+var (
+	testLatestAnswerMethod    = abi.MustParseMethod("latestAnswer() view returns (int256)")
+	testLatestRoundDataMethod = abi.MustParseMethod(`
+		function latestRoundData() view returns (
+			uint80 roundId,
+			int256 answer,
+			uint256 startedAt,
+			uint256 updatedAt,
+			uint80 answeredInRound
+		)`,
+	)
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestNewGenericEVM(t *testing.T) {
+	tests := []struct {
+		name     string
+		contract GenericETHContract
+		wantErr  bool
+	}{
+		{
+			name:     "single value",
+			contract: GenericETHContract{Method: testLatestAnswerMethod},
+		},
+		{
+			name: "tuple",
+			contract: GenericETHContract{
+				Method:         testLatestRoundDataMethod,
+				PriceIndex:     1,
+				TimestampIndex: intPtr(3),
+			},
+		},
+		{
+			name:     "price index out of range",
+			contract: GenericETHContract{Method: testLatestAnswerMethod, PriceIndex: 1},
+			wantErr:  true,
+		},
+		{
+			name:     "timestamp index out of range",
+			contract: GenericETHContract{Method: testLatestAnswerMethod, TimestampIndex: intPtr(5)},
+			wantErr:  true,
+		},
+		{
+			name:     "same price and timestamp index",
+			contract: GenericETHContract{Method: testLatestRoundDataMethod, PriceIndex: 1, TimestampIndex: intPtr(1)},
+			wantErr:  true,
+		},
+		{
+			name:     "non-integer value",
+			contract: GenericETHContract{Method: abi.MustParseMethod("foo() view returns (address)")},
+			wantErr:  true,
+		},
+		{
+			name:     "negative scale",
+			contract: GenericETHContract{Method: testLatestAnswerMethod, Scale: bn.Float(-1)},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenericEVM(GenericEVMOptions{
+				Client: newFakeChain(),
+				Contracts: map[provider.Pair]GenericETHContract{
+					{Base: "ETH", Quote: "USD"}: tt.contract,
+				},
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenericEVM_FetchTicks(t *testing.T) {
+	updatedAt := time.Unix(1700000000, 0)
+	chain := newFakeChain()
+	chain.register(testPool, testLatestAnswerMethod, func([]byte) []any {
+		return []any{bn.Int(2000).Mul(bn.Int(10).Pow(8)).BigInt()}
+	})
+	chain.register(testPool, testLatestRoundDataMethod, func([]byte) []any {
+		return []any{
+			big.NewInt(1),
+			bn.Int(4).Mul(bn.Int(10).Pow(8)).BigInt(),
+			big.NewInt(updatedAt.Unix() - 10),
+			big.NewInt(updatedAt.Unix()),
+			big.NewInt(1),
+		}
+	})
+
+	now := time.Unix(1700000000, 0)
+	g, err := NewGenericEVM(GenericEVMOptions{
+		Client: chain,
+		Contracts: map[provider.Pair]GenericETHContract{
+			{Base: "ETH", Quote: "USD"}: {
+				Method:   testLatestAnswerMethod,
+				Contract: testPool,
+				Decimals: 8,
+			},
+			{Base: "USD", Quote: "BTC"}: {
+				Method:         testLatestRoundDataMethod,
+				Contract:       testPool,
+				Decimals:       8,
+				PriceIndex:     1,
+				TimestampIndex: intPtr(3),
+				Scale:          bn.Float(10000),
+				Invert:         true,
+			},
+		},
+		Now: func() time.Time { return now },
+	})
+	require.NoError(t, err)
+
+	ticks := g.FetchTicks(context.Background(), []provider.Pair{
+		{Base: "ETH", Quote: "USD"},
+		{Base: "USD", Quote: "BTC"},
+	})
+	require.Len(t, ticks, 2)
+	assertPrice(t, 2000, ticks[0])
+	assertPrice(t, 0.000025, ticks[1])
+	assert.Equal(t, now, ticks[0].Time)
+	assert.Equal(t, updatedAt, ticks[1].Time)
+}
+
+func TestGenericEVM_FetchTicks_InvalidPrice(t *testing.T) {
+	chain := newFakeChain()
+	chain.register(testPool, testLatestAnswerMethod, func([]byte) []any {
+		return []any{big.NewInt(-1)}
+	})
+
+	g, err := NewGenericEVM(GenericEVMOptions{
+		Client: chain,
+		Contracts: map[provider.Pair]GenericETHContract{
+			{Base: "ETH", Quote: "USD"}: {Method: testLatestAnswerMethod, Contract: testPool},
+		},
+	})
+	require.NoError(t, err)
+
+	ticks := g.FetchTicks(context.Background(), []provider.Pair{{Base: "ETH", Quote: "USD"}})
+	require.Len(t, ticks, 1)
+	assert.EqualError(t, ticks[0].Error, "price is not positive: -1")
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
@@ -43,6 +44,10 @@ type GenericHTTPOptions struct {
 	// Logger is an GenericHTTP logger that is used to log errors. If nil,
 	// null logger is used.
 	Logger log.Logger
+
+	// Now returns the current time, which is used as the time of ticks
+	// that do not provide their own time. If nil, time.Now is used.
+	Now func() time.Time
 }
 
 // GenericHTTP is a generic GenericHTTP price provider that can fetch prices from
@@ -53,6 +58,7 @@ type GenericHTTP struct {
 	headers  http.Header
 	callback HTTPCallback
 	logger   log.Logger
+	now      func() time.Time
 }
 
 // NewGenericHTTP creates a new GenericHTTP instance.
@@ -69,12 +75,16 @@ func NewGenericHTTP(opts GenericHTTPOptions) (*GenericHTTP, error) {
 	if opts.Logger == nil {
 		opts.Logger = null.New()
 	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
 	return &GenericHTTP{
 		url:      opts.URL,
 		client:   opts.Client,
 		headers:  opts.Headers,
 		callback: opts.Callback,
 		logger:   opts.Logger.WithField("tag", GenericHTTPLoggerTag),
+		now:      opts.Now,
 	}, nil
 }
 
@@ -92,7 +102,7 @@ func (g *GenericHTTP) FetchTicks(ctx context.Context, pairs []provider.Pair) []p
 		// Perform GenericHTTP request.
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
-			ticks = append(ticks, withError(pairs, err)...)
+			ticks = append(ticks, withError(pairs, g.now(), err)...)
 			continue
 		}
 		req.Header = g.headers
@@ -101,7 +111,7 @@ func (g *GenericHTTP) FetchTicks(ctx context.Context, pairs []provider.Pair) []p
 		// Execute GenericHTTP request.
 		res, err := g.client.Do(req)
 		if err != nil {
-			ticks = append(ticks, withError(pairs, err)...)
+			ticks = append(ticks, withError(pairs, g.now(), err)...)
 			continue
 		}
 		defer res.Body.Close()
@@ -60,6 +60,10 @@ type GenericJQOptions struct {
 	// Logger is an GenericHTTP logger that is used to log errors. If nil,
 	// null logger is used.
 	Logger log.Logger
+
+	// Now returns the current time, which is used as the time of ticks
+	// that do not provide their own time. If nil, time.Now is used.
+	Now func() time.Time
 }
 
 // GenericJQ is a generic origin implementation that uses JQ to parse JSON data
@@ -70,6 +74,7 @@ type GenericJQ struct {
 	rawQuery string
 	query    *gojq.Code
 	logger   log.Logger
+	now      func() time.Time
 }
 
 // NewGenericJQ creates a new GenericJQ instance.
@@ -120,6 +125,9 @@ func NewGenericJQ(opts GenericJQOptions) (*GenericJQ, error) {
 	if opts.Logger == nil {
 		opts.Logger = null.New()
 	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
 	parsed, err := gojq.Parse(opts.Query)
 	if err != nil {
 		return nil, err
@@ -140,6 +148,7 @@ func NewGenericJQ(opts GenericJQOptions) (*GenericJQ, error) {
 		Callback: jq.handle,
 		Client:   opts.Client,
 		Logger:   opts.Logger,
+		Now:      opts.Now,
 	})
 	if err != nil {
 		return nil, err
@@ -148,6 +157,7 @@ func NewGenericJQ(opts GenericJQOptions) (*GenericJQ, error) {
 	jq.rawQuery = opts.Query
 	jq.query = compiled
 	jq.logger = opts.Logger.WithField("tag", GenericJQLoggerTag)
+	jq.now = opts.Now
 	return jq, nil
 }
 
@@ -162,7 +172,7 @@ func (g *GenericJQ) handle(ctx context.Context, pairs []provider.Pair, body io.R
 	// Parse JSON data.
 	var data any
 	if err := json.NewDecoder(body).Decode(&data); err != nil {
-		return withError(pairs, err)
+		return withError(pairs, g.now(), err)
 	}
 
 	// Run JQ query for each pair and parse the result.
@@ -177,7 +187,7 @@ func (g *GenericJQ) handle(ctx context.Context, pairs []provider.Pair, body io.R
 
 		tick := provider.Tick{
 			Pair: pair,
-			Time: time.Now(),
+			Time: g.now(),
 		}
 		iter := g.query.RunWithContext(
 			ctx,
@@ -203,28 +213,36 @@ func (g *GenericJQ) handle(ctx context.Context, pairs []provider.Pair, body io.R
 			ticks = append(ticks, tick)
 			continue
 		}
-		switch v := v.(type) {
-		case map[string]any:
-			for k, v := range v {
-				switch k {
-				case "price":
-					tick.Price = bn.Float(v)
-				case "volume":
-					tick.Volume24h = bn.Float(v)
-				case "time":
-					if tm, ok := anyToTime(v); ok {
-						tick.Time = tm
-					}
-				default:
-					tick.Error = fmt.Errorf("unknown key in JQ result: %s", k)
+		parseJQResult(&tick, v)
+		ticks = append(ticks, tick)
+	}
+	return ticks
+}
+
+// parseJQResult updates the tick using the value returned by a JQ query.
+//
+// The value must be either a number that will be used as a price or an
+// object with price, volume and time fields.
+func parseJQResult(tick *provider.Tick, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, v := range v {
+			switch k {
+			case "price":
+				tick.Price = bn.Float(v)
+			case "volume":
+				tick.Volume24h = bn.Float(v)
+			case "time":
+				if tm, ok := anyToTime(v); ok {
+					tick.Time = tm
 				}
+			default:
+				tick.Error = fmt.Errorf("unknown key in JQ result: %s", k)
 			}
-		case int, int32, int64, uint, uint32, uint64, float32, float64:
-			tick.Price = bn.Float(v)
 		}
-		ticks = append(ticks, tick)
+	case int, int32, int64, uint, uint32, uint64, float32, float64:
+		tick.Price = bn.Float(v)
 	}
-	return ticks
 }
 
 // anyToTime converts an arbitrary value to a time.Time.
@@ -0,0 +1,485 @@
+package origin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/itchyny/gojq"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/interpolate"
+)
+
+const GenericWebSocketLoggerTag = "GENERIC_WEBSOCKET_ORIGIN"
+
+const (
+	defaultMinReconnectDelay = time.Second
+	defaultMaxReconnectDelay = time.Minute
+	defaultFirstTickTimeout  = 10 * time.Second
+)
+
+var errNoTickReceived = errors.New("no tick received yet")
+
+type GenericWebSocketOptions struct {
+	// URL is a WebSocket endpoint, e.g. "wss://example.com/ws".
+	URL string
+
+	// Subscriptions is a list of messages that are sent to the server after
+	// connecting. Messages may contain the following variables:
+	//   - ${lcbase} - lower case base asset
+	//   - ${ucbase} - upper case base asset
+	//   - ${lcquote} - lower case quote asset
+	//   - ${ucquote} - upper case quote asset
+	//
+	// Messages are interpolated for every pair for which ticks were
+	// requested. Identical messages are sent only once.
+	Subscriptions []string
+
+	// Query is a JQ query that is applied to every received message for
+	// every subscribed pair. It must return nothing if the message does not
+	// contain a price for the pair, otherwise it must return a single value
+	// that will be used as a price or an object with the following fields:
+	//   - price - a price
+	//   - time - a timestamp (optional)
+	//   - volume - a 24h volume (optional)
+	//
+	// The JQ query may contain the following variables:
+	//   - $lcbase - lower case base asset
+	//   - $ucbase - upper case base asset
+	//   - $lcquote - lower case quote asset
+	//   - $ucquote - upper case quote asset
+	Query string
+
+	// Headers is a set of HTTP headers that are sent with the handshake
+	// request.
+	Headers http.Header
+
+	// MinReconnectDelay is the initial delay before reconnecting after
+	// the connection is lost. The delay is doubled after every failed
+	// attempt, up to MaxReconnectDelay. If zero, one second is used.
+	MinReconnectDelay time.Duration
+
+	// MaxReconnectDelay is the maximum delay before reconnecting. If zero,
+	// one minute is used.
+	MaxReconnectDelay time.Duration
+
+	// FirstTickTimeout is the maximum time FetchTicks waits for the first
+	// tick of a pair that was not requested before. If zero, ten seconds
+	// is used.
+	FirstTickTimeout time.Duration
+
+	// Dialer is a WebSocket dialer. If nil, websocket.DefaultDialer is used.
+	Dialer *websocket.Dialer
+
+	// Logger is a logger that is used to log errors. If nil, null logger is
+	// used.
+	Logger log.Logger
+
+	// Now returns the current time, which is used as the time of ticks
+	// that do not provide their own time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// GenericWebSocket is a generic origin implementation that keeps a persistent
+// WebSocket connection, subscribes to configured channels and uses JQ to
+// parse incoming messages.
+//
+// The latest tick for every pair is kept in memory, so FetchTicks returns
+// immediately for pairs that were already requested. Pairs that were not
+// requested before are subscribed on the first call, in which case
+// FetchTicks waits for the first tick up to FirstTickTimeout.
+//
+// GenericWebSocket implements the supervisor.Service interface and must be
+// started before use.
+type GenericWebSocket struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	waitCh chan error
+
+	url               string
+	subscriptions     []interpolate.Parsed
+	rawQuery          string
+	query             *gojq.Code
+	headers           http.Header
+	minReconnectDelay time.Duration
+	maxReconnectDelay time.Duration
+	firstTickTimeout  time.Duration
+	dialer            *websocket.Dialer
+	logger            log.Logger
+	now               func() time.Time
+
+	conn    *websocket.Conn
+	pairs   []provider.Pair                 // subscribed pairs
+	sent    map[string]struct{}             // subscription messages sent on the current connection
+	ticks   map[provider.Pair]provider.Tick // latest ticks
+	updated chan struct{}                   // closed and replaced every time a tick is received
+}
+
+// NewGenericWebSocket creates a new GenericWebSocket instance.
+func NewGenericWebSocket(opts GenericWebSocketOptions) (*GenericWebSocket, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("url cannot be empty")
+	}
+	if opts.Query == "" {
+		return nil, fmt.Errorf("query must be specified")
+	}
+	if opts.MinReconnectDelay == 0 {
+		opts.MinReconnectDelay = defaultMinReconnectDelay
+	}
+	if opts.MaxReconnectDelay == 0 {
+		opts.MaxReconnectDelay = defaultMaxReconnectDelay
+	}
+	if opts.MaxReconnectDelay < opts.MinReconnectDelay {
+		return nil, fmt.Errorf("max reconnect delay must be greater than min reconnect delay")
+	}
+	if opts.FirstTickTimeout == 0 {
+		opts.FirstTickTimeout = defaultFirstTickTimeout
+	}
+	if opts.Dialer == nil {
+		opts.Dialer = websocket.DefaultDialer
+	}
+	if opts.Logger == nil {
+		opts.Logger = null.New()
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	parsed, err := gojq.Parse(opts.Query)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := gojq.Compile(parsed, gojq.WithVariables([]string{
+		"$lcbase",
+		"$ucbase",
+		"$lcquote",
+		"$ucquote",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := make([]interpolate.Parsed, len(opts.Subscriptions))
+	for i, s := range opts.Subscriptions {
+		subscriptions[i] = interpolate.Parse(s)
+	}
+	return &GenericWebSocket{
+		waitCh:            make(chan error),
+		url:               opts.URL,
+		subscriptions:     subscriptions,
+		rawQuery:          opts.Query,
+		query:             compiled,
+		headers:           opts.Headers,
+		minReconnectDelay: opts.MinReconnectDelay,
+		maxReconnectDelay: opts.MaxReconnectDelay,
+		firstTickTimeout:  opts.FirstTickTimeout,
+		dialer:            opts.Dialer,
+		logger:            opts.Logger.WithField("tag", GenericWebSocketLoggerTag),
+		now:               opts.Now,
+		sent:              make(map[string]struct{}),
+		ticks:             make(map[provider.Pair]provider.Tick),
+		updated:           make(chan struct{}),
+	}, nil
+}
+
+// Start implements the supervisor.Service interface.
+func (g *GenericWebSocket) Start(ctx context.Context) error {
+	if g.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	g.logger.WithField("url", g.url).Info("Starting")
+	g.ctx = ctx
+	go g.connectionRoutine()
+	return nil
+}
+
+// Wait implements the supervisor.Service interface.
+func (g *GenericWebSocket) Wait() <-chan error {
+	return g.waitCh
+}
+
+// FetchTicks implements the Origin interface.
+func (g *GenericWebSocket) FetchTicks(ctx context.Context, pairs []provider.Pair) []provider.Tick {
+	if g.ctx == nil {
+		return withError(pairs, g.now(), errors.New("origin is not started"))
+	}
+
+	// Subscribe to pairs that were not requested before and wait for their
+	// first ticks.
+	if g.subscribe(pairs) {
+		waitCtx, waitCtxCancel := context.WithTimeout(ctx, g.firstTickTimeout)
+		defer waitCtxCancel()
+		for !g.hasTicks(pairs) {
+			g.mu.Lock()
+			updated := g.updated
+			g.mu.Unlock()
+			select {
+			case <-waitCtx.Done():
+			case <-updated:
+				continue
+			}
+			break
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ticks := make([]provider.Tick, 0, len(pairs))
+	for _, pair := range pairs {
+		tick, ok := g.ticks[pair]
+		if !ok {
+			ticks = append(ticks, provider.Tick{
+				Pair:  pair,
+				Time:  g.now(),
+				Error: errNoTickReceived,
+			})
+			continue
+		}
+		ticks = append(ticks, tick)
+	}
+	return ticks
+}
+
+// subscribe adds the given pairs to the list of subscribed pairs and sends
+// subscription messages if connected. It returns true if any new pair was
+// added.
+func (g *GenericWebSocket) subscribe(pairs []provider.Pair) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	added := false
+	for _, pair := range pairs {
+		if !containsPair(g.pairs, pair) {
+			g.pairs = append(g.pairs, pair)
+			added = true
+		}
+	}
+	if added && g.conn != nil {
+		if err := g.sendSubscriptions(); err != nil {
+			g.logger.WithError(err).Warn("Unable to send subscription messages")
+		}
+	}
+	return added
+}
+
+// hasTicks returns true if ticks for all given pairs were received.
+func (g *GenericWebSocket) hasTicks(pairs []provider.Pair) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, pair := range pairs {
+		if _, ok := g.ticks[pair]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sendSubscriptions sends subscription messages for all subscribed pairs
+// that were not sent on the current connection yet.
+//
+// Must be called with the mutex locked.
+func (g *GenericWebSocket) sendSubscriptions() error {
+	for _, pair := range g.pairs {
+		for _, s := range g.subscriptions {
+			msg := s.Interpolate(pairVariables(pair))
+			if _, ok := g.sent[msg]; ok {
+				continue
+			}
+			g.logger.
+				WithFields(log.Fields{
+					"url":     g.url,
+					"message": msg,
+				}).
+				Debug("WebSocket subscription")
+			if err := g.conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return err
+			}
+			g.sent[msg] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// connectionRoutine keeps the connection open, reconnecting with
+// exponential backoff if the connection is lost.
+func (g *GenericWebSocket) connectionRoutine() {
+	defer func() { close(g.waitCh) }()
+	defer g.logger.Info("Stopped")
+	delay := g.minReconnectDelay
+	for {
+		err := g.connect()
+		if err == nil {
+			// Reset the delay after a successful connection.
+			delay = g.minReconnectDelay
+			err = g.readMessages()
+		}
+		g.disconnect()
+		if g.ctx.Err() != nil {
+			return
+		}
+		g.logger.
+			WithError(err).
+			WithFields(log.Fields{
+				"url":   g.url,
+				"delay": delay,
+			}).
+			Warn("WebSocket connection lost, reconnecting")
+		t := time.NewTimer(delay)
+		select {
+		case <-g.ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+		delay *= 2
+		if delay > g.maxReconnectDelay {
+			delay = g.maxReconnectDelay
+		}
+	}
+}
+
+func (g *GenericWebSocket) connect() error {
+	conn, res, err := g.dialer.DialContext(g.ctx, g.url, g.headers)
+	if err != nil {
+		return err
+	}
+	if res != nil && res.Body != nil {
+		_ = res.Body.Close()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.conn = conn
+	g.sent = make(map[string]struct{})
+	return g.sendSubscriptions()
+}
+
+func (g *GenericWebSocket) disconnect() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.conn != nil {
+		_ = g.conn.Close()
+		g.conn = nil
+	}
+}
+
+func (g *GenericWebSocket) readMessages() error {
+	g.mu.Lock()
+	conn := g.conn
+	g.mu.Unlock()
+
+	// Close the connection when the context is canceled to interrupt
+	// the blocking read.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-g.ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		g.handle(msg)
+	}
+}
+
+// handle applies the JQ query to the given message for every subscribed
+// pair and stores the resulting ticks.
+func (g *GenericWebSocket) handle(msg []byte) {
+	var data any
+	if err := json.Unmarshal(msg, &data); err != nil {
+		g.logger.WithError(err).Debug("Unable to parse WebSocket message")
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	received := false
+	for _, pair := range g.pairs {
+		iter := g.query.RunWithContext(
+			g.ctx,
+			data,
+			strings.ToLower(pair.Base),  // $lcbase
+			strings.ToUpper(pair.Base),  // $ucbase
+			strings.ToLower(pair.Quote), // $lcquote
+			strings.ToUpper(pair.Quote), // $ucquote
+		)
+		v, ok := iter.Next()
+		if !ok {
+			// Message does not contain data for this pair.
+			continue
+		}
+		if err, ok := v.(error); ok {
+			g.logger.
+				WithError(err).
+				WithFields(log.Fields{
+					"pair":  pair,
+					"query": g.rawQuery,
+				}).
+				Debug("JQ query failed")
+			continue
+		}
+		tick := provider.Tick{
+			Pair: pair,
+			Time: g.now(),
+		}
+		if _, ok := iter.Next(); ok {
+			tick.Error = fmt.Errorf("multiple results from JQ query")
+		} else {
+			parseJQResult(&tick, v)
+		}
+
+		// Do not replace a valid tick with an older one.
+		if prev, ok := g.ticks[pair]; ok && prev.Error == nil && prev.Time.After(tick.Time) {
+			continue
+		}
+		g.ticks[pair] = tick
+		received = true
+	}
+	if received {
+		close(g.updated)
+		g.updated = make(chan struct{})
+	}
+}
+
+// pairVariables returns a function that maps interpolation variables to
+// values for the given pair.
+func pairVariables(pair provider.Pair) func(variable interpolate.Variable) string {
+	return func(variable interpolate.Variable) string {
+		switch variable.Name {
+		case "lcbase":
+			return strings.ToLower(pair.Base)
+		case "ucbase":
+			return strings.ToUpper(pair.Base)
+		case "lcquote":
+			return strings.ToLower(pair.Quote)
+		case "ucquote":
+			return strings.ToUpper(pair.Quote)
+		default:
+			return variable.Default
+		}
+	}
+}
+
+func containsPair(pairs []provider.Pair, pair provider.Pair) bool {
+	for _, p := range pairs {
+		if p.Equal(pair) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package origin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+// testWebSocketServer is a WebSocket server that responds to subscription
+// messages with a price message.
+type testWebSocketServer struct {
+	mu       sync.Mutex
+	srv      *httptest.Server
+	messages []string
+	conns    []*websocket.Conn
+}
+
+func newTestWebSocketServer(t *testing.T, prices map[string]string) *testWebSocketServer {
+	s := &testWebSocketServer{}
+	upgrader := websocket.Upgrader{}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(msg))
+			s.mu.Unlock()
+			symbol := strings.TrimPrefix(string(msg), "subscribe:")
+			if price, ok := prices[symbol]; ok {
+				_ = conn.WriteMessage(
+					websocket.TextMessage,
+					[]byte(`{"symbol":"`+symbol+`","price":"`+price+`"}`),
+				)
+			}
+		}
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *testWebSocketServer) url() string {
+	return "ws" + strings.TrimPrefix(s.srv.URL, "http")
+}
+
+func (s *testWebSocketServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func (s *testWebSocketServer) closeConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+	s.conns = nil
+}
+
+func TestNewGenericWebSocket(t *testing.T) {
+	t.Run("empty URL", func(t *testing.T) {
+		_, err := NewGenericWebSocket(GenericWebSocketOptions{Query: ".price"})
+		assert.EqualError(t, err, "url cannot be empty")
+	})
+	t.Run("empty query", func(t *testing.T) {
+		_, err := NewGenericWebSocket(GenericWebSocketOptions{URL: "wss://example.com"})
+		assert.EqualError(t, err, "query must be specified")
+	})
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := NewGenericWebSocket(GenericWebSocketOptions{URL: "wss://example.com", Query: "invalid jq"})
+		assert.Error(t, err)
+	})
+	t.Run("valid options", func(t *testing.T) {
+		_, err := NewGenericWebSocket(GenericWebSocketOptions{URL: "wss://example.com", Query: ".price"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestGenericWebSocket_FetchTicks(t *testing.T) {
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+
+	srv := newTestWebSocketServer(t, map[string]string{
+		"btcusd": "20000",
+		"ethusd": "1500",
+	})
+	ws, err := NewGenericWebSocket(GenericWebSocketOptions{
+		URL:               srv.url(),
+		Subscriptions:     []string{"subscribe:${lcbase}${lcquote}"},
+		Query:             `select(.symbol == ($lcbase + $lcquote)) | .price | tonumber`,
+		MinReconnectDelay: 10 * time.Millisecond,
+		FirstTickTimeout:  time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, ws.Start(ctx))
+
+	btcusd := provider.Pair{Base: "BTC", Quote: "USD"}
+	ethusd := provider.Pair{Base: "ETH", Quote: "USD"}
+	xyzusd := provider.Pair{Base: "XYZ", Quote: "USD"}
+
+	t.Run("first fetch", func(t *testing.T) {
+		ticks := ws.FetchTicks(ctx, []provider.Pair{btcusd, ethusd})
+		require.Len(t, ticks, 2)
+		assert.Equal(t, btcusd, ticks[0].Pair)
+		assert.Equal(t, bn.Float(20000).String(), ticks[0].Price.String())
+		assert.NoError(t, ticks[0].Error)
+		assert.Equal(t, ethusd, ticks[1].Pair)
+		assert.Equal(t, bn.Float(1500).String(), ticks[1].Price.String())
+		assert.NoError(t, ticks[1].Error)
+	})
+	t.Run("no data for pair", func(t *testing.T) {
+		fetchCtx, fetchCtxCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer fetchCtxCancel()
+		ticks := ws.FetchTicks(fetchCtx, []provider.Pair{xyzusd})
+		require.Len(t, ticks, 1)
+		assert.Equal(t, errNoTickReceived, ticks[0].Error)
+	})
+	t.Run("resubscribe after reconnect", func(t *testing.T) {
+		srv.closeConnections()
+		assert.Eventually(t, func() bool {
+			n := 0
+			for _, msg := range srv.received() {
+				if msg == "subscribe:btcusd" {
+					n++
+				}
+			}
+			return n == 2
+		}, time.Second, 10*time.Millisecond)
+		ticks := ws.FetchTicks(ctx, []provider.Pair{btcusd})
+		require.Len(t, ticks, 1)
+		assert.NoError(t, ticks[0].Error)
+	})
+
+	ctxCancel()
+	select {
+	case <-ws.Wait():
+	case <-time.After(time.Second):
+		t.Fatal("origin did not stop")
+	}
+}
@@ -0,0 +1,397 @@
+package origin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+)
+
+const HTTPTransportLoggerTag = "HTTP_TRANSPORT"
+
+const (
+	defaultHTTPMinBackoff = time.Second
+	defaultHTTPMaxBackoff = 30 * time.Second
+)
+
+type HTTPTransportOptions struct {
+	// Transport is the underlying transport used to perform requests.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// RateLimit is the maximum number of requests per second sent to
+	// a single host. If zero, requests are not rate limited.
+	RateLimit float64
+
+	// Burst is the maximum number of requests that can be sent to a single
+	// host at once. If zero, 1 is used.
+	Burst int
+
+	// MaxRetries is the maximum number of retries of a request that failed
+	// because of a network error or a 429 or 5xx response.
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff are bounds of the exponential backoff
+	// between retries. If zero, 1 and 30 seconds are used respectively.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// CacheTTL is the time for which successful responses to GET requests
+	// are cached. If zero, responses are not cached.
+	CacheTTL time.Duration
+
+	// Logger is used to log retries. If nil, null logger is used.
+	Logger log.Logger
+}
+
+// HTTPTransport is an http.RoundTripper that protects HTTP origins from
+// being rate limited or banned by exchanges.
+//
+// Requests are rate limited per host using a token bucket. Requests that
+// failed because of a network error or a 429 or 5xx response are retried
+// using an exponential backoff with jitter. If a host responds with the
+// Retry-After header, no requests are sent to that host until the given
+// time.
+//
+// Identical GET requests that are performed concurrently are coalesced into
+// a single request, and successful responses may be cached for a short time,
+// so origins that are shared by many price models do not query the same
+// endpoint repeatedly.
+type HTTPTransport struct {
+	transport  http.RoundTripper
+	rateLimit  rate.Limit
+	burst      int
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	cacheTTL   time.Duration
+	logger     log.Logger
+
+	mu       sync.Mutex
+	hosts    map[string]*httpHost
+	inflight map[string]*httpCall
+	cache    map[string]*httpCacheEntry
+}
+
+// httpHost holds the rate limiting state of a single host.
+type httpHost struct {
+	limiter    *rate.Limiter
+	retryAfter time.Time
+}
+
+// httpCall is a request in progress that other identical requests wait for.
+type httpCall struct {
+	done chan struct{}
+	res  *httpResponse
+	err  error
+}
+
+type httpCacheEntry struct {
+	res     *httpResponse
+	expires time.Time
+}
+
+// httpResponse is a response with a buffered body that can be returned
+// multiple times.
+type httpResponse struct {
+	status     string
+	statusCode int
+	proto      string
+	protoMajor int
+	protoMinor int
+	header     http.Header
+	body       []byte
+}
+
+// NewHTTPTransport creates a new HTTPTransport instance.
+func NewHTTPTransport(opts HTTPTransportOptions) (*HTTPTransport, error) {
+	if opts.RateLimit < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative")
+	}
+	if opts.Burst < 0 {
+		return nil, fmt.Errorf("burst must not be negative")
+	}
+	if opts.MaxRetries < 0 {
+		return nil, fmt.Errorf("max retries must not be negative")
+	}
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+	if opts.Burst == 0 {
+		opts.Burst = 1
+	}
+	if opts.MinBackoff == 0 {
+		opts.MinBackoff = defaultHTTPMinBackoff
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = defaultHTTPMaxBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		return nil, fmt.Errorf("max backoff must not be less than min backoff")
+	}
+	if opts.Logger == nil {
+		opts.Logger = null.New()
+	}
+	limit := rate.Inf
+	if opts.RateLimit > 0 {
+		limit = rate.Limit(opts.RateLimit)
+	}
+	return &HTTPTransport{
+		transport:  opts.Transport,
+		rateLimit:  limit,
+		burst:      opts.Burst,
+		maxRetries: opts.MaxRetries,
+		minBackoff: opts.MinBackoff,
+		maxBackoff: opts.MaxBackoff,
+		cacheTTL:   opts.CacheTTL,
+		logger:     opts.Logger.WithField("tag", HTTPTransportLoggerTag),
+		hosts:      make(map[string]*httpHost),
+		inflight:   make(map[string]*httpCall),
+		cache:      make(map[string]*httpCacheEntry),
+	}, nil
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *HTTPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Body != nil && req.Body != http.NoBody {
+		// Only GET requests are safe to coalesce, cache and retry.
+		if err := t.wait(req.Context(), req.URL.Host); err != nil {
+			return nil, err
+		}
+		return t.transport.RoundTrip(req)
+	}
+
+	key := requestKey(req)
+	now := time.Now()
+
+	t.mu.Lock()
+	if e, ok := t.cache[key]; ok {
+		if now.Before(e.expires) {
+			t.mu.Unlock()
+			return e.res.response(req), nil
+		}
+		delete(t.cache, key)
+	}
+	if call, ok := t.inflight[key]; ok {
+		t.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		return call.res.response(req), nil
+	}
+	call := &httpCall{done: make(chan struct{})}
+	t.inflight[key] = call
+	t.mu.Unlock()
+
+	call.res, call.err = t.do(req)
+
+	t.mu.Lock()
+	delete(t.inflight, key)
+	if call.err == nil && t.cacheTTL > 0 && call.res.statusCode >= 200 && call.res.statusCode < 300 {
+		t.pruneCache(now)
+		t.cache[key] = &httpCacheEntry{res: call.res, expires: time.Now().Add(t.cacheTTL)}
+	}
+	t.mu.Unlock()
+	close(call.done)
+
+	if call.err != nil {
+		return nil, call.err
+	}
+	return call.res.response(req), nil
+}
+
+// do performs the request, retrying it if necessary.
+func (t *HTTPTransport) do(req *http.Request) (*httpResponse, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(ctx, req.URL.Host); err != nil {
+			return nil, err
+		}
+		res, err := t.roundTrip(req)
+		retry := attempt < t.maxRetries && ctx.Err() == nil
+		switch {
+		case err != nil && !retry:
+			return nil, err
+		case err == nil && (!retryableStatus(res.statusCode) || !retry):
+			return res, nil
+		}
+		delay := t.backoff(attempt)
+		if err == nil {
+			if retryAfter, ok := parseRetryAfter(res.header.Get("Retry-After"), time.Now()); ok {
+				t.setRetryAfter(req.URL.Host, time.Now().Add(retryAfter))
+				if retryAfter > t.maxBackoff {
+					// Do not block the caller for too long, the host
+					// remains paused for subsequent requests.
+					return res, nil
+				}
+				if retryAfter > delay {
+					delay = retryAfter
+				}
+			}
+			err = fmt.Errorf("unexpected status code: %d", res.statusCode)
+		}
+		t.logger.
+			WithError(err).
+			WithFields(log.Fields{
+				"url":     req.URL.String(),
+				"attempt": attempt + 1,
+				"delay":   delay,
+			}).
+			Warn("HTTP request failed, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// roundTrip performs a single request and reads the response body.
+func (t *HTTPTransport) roundTrip(req *http.Request) (*httpResponse, error) {
+	res, err := t.transport.RoundTrip(req.Clone(req.Context()))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &httpResponse{
+		status:     res.Status,
+		statusCode: res.StatusCode,
+		proto:      res.Proto,
+		protoMajor: res.ProtoMajor,
+		protoMinor: res.ProtoMinor,
+		header:     res.Header,
+		body:       body,
+	}, nil
+}
+
+// wait blocks until a request to the given host is allowed.
+func (t *HTTPTransport) wait(ctx context.Context, host string) error {
+	t.mu.Lock()
+	h := t.host(host)
+	retryAfter := h.retryAfter
+	t.mu.Unlock()
+	if d := time.Until(retryAfter); d > 0 {
+		if deadline, ok := ctx.Deadline(); ok && deadline.Before(retryAfter) {
+			return fmt.Errorf("requests to %s are paused until %s", host, retryAfter.Format(time.RFC3339))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+	return h.limiter.Wait(ctx)
+}
+
+func (t *HTTPTransport) setRetryAfter(host string, tm time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.host(host)
+	if tm.After(h.retryAfter) {
+		h.retryAfter = tm
+	}
+}
+
+// host returns the state of the given host. Must be called with the mutex
+// locked.
+func (t *HTTPTransport) host(host string) *httpHost {
+	h, ok := t.hosts[host]
+	if !ok {
+		h = &httpHost{limiter: rate.NewLimiter(t.rateLimit, t.burst)}
+		t.hosts[host] = h
+	}
+	return h
+}
+
+// pruneCache removes expired responses from the cache. Must be called with
+// the mutex locked.
+func (t *HTTPTransport) pruneCache(now time.Time) {
+	for key, e := range t.cache {
+		if !now.Before(e.expires) {
+			delete(t.cache, key)
+		}
+	}
+}
+
+// backoff returns the delay before the next retry. The delay grows
+// exponentially with the number of attempts and is randomized to avoid
+// synchronized retries.
+func (t *HTTPTransport) backoff(attempt int) time.Duration {
+	d := t.minBackoff
+	for i := 0; i < attempt && d < t.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > t.maxBackoff {
+		d = t.maxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec
+}
+
+func (r *httpResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        r.status,
+		StatusCode:    r.statusCode,
+		Proto:         r.proto,
+		ProtoMajor:    r.protoMajor,
+		ProtoMinor:    r.protoMinor,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// requestKey returns a key that identifies identical requests.
+func requestKey(req *http.Request) string {
+	var buf bytes.Buffer
+	buf.WriteString(req.Method)
+	buf.WriteByte(' ')
+	buf.WriteString(req.URL.String())
+	buf.WriteByte('\n')
+	_ = req.Header.Write(&buf)
+	return buf.String()
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// parseRetryAfter parses the Retry-After header, which may contain either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	if tm, err := http.ParseTime(v); err == nil {
+		if d := tm.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,196 @@
+package origin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func httpGet(t *testing.T, client *http.Client, url string) (int, string) {
+	res, err := client.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(body)
+}
+
+func TestHTTPTransport_Coalescing(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	transport, err := NewHTTPTransport(HTTPTransportOptions{})
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, body := httpGet(t, client, srv.URL)
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "ok", body)
+		}()
+	}
+
+	// Wait until all requests are waiting for the first one.
+	require.Eventually(t, func() bool {
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		return len(transport.inflight) == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestHTTPTransport_Cache(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		expected int32
+	}{
+		{name: "cache disabled", ttl: 0, expected: 2},
+		{name: "cache enabled", ttl: time.Minute, expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&requests, 1)
+				_, _ = fmt.Fprintf(w, "%d", n)
+			}))
+			defer srv.Close()
+
+			transport, err := NewHTTPTransport(HTTPTransportOptions{CacheTTL: tt.ttl})
+			require.NoError(t, err)
+			client := &http.Client{Transport: transport}
+
+			_, body1 := httpGet(t, client, srv.URL)
+			_, body2 := httpGet(t, client, srv.URL)
+			assert.Equal(t, "1", body1)
+			assert.Equal(t, fmt.Sprint(tt.expected), body2)
+			assert.Equal(t, tt.expected, atomic.LoadInt32(&requests))
+		})
+	}
+}
+
+func TestHTTPTransport_Retry(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	transport, err := NewHTTPTransport(HTTPTransportOptions{
+		MaxRetries: 2,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	code, body := httpGet(t, client, srv.URL)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestHTTPTransport_RetryAfter(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	transport, err := NewHTTPTransport(HTTPTransportOptions{
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	// Retry-After exceeds the maximum backoff, so the response is returned
+	// without retrying.
+	code, _ := httpGet(t, client, srv.URL)
+	assert.Equal(t, http.StatusTooManyRequests, code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// The host must be paused.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestHTTPTransport_RateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	transport, err := NewHTTPTransport(HTTPTransportOptions{RateLimit: 20, Burst: 1})
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		httpGet(t, client, fmt.Sprintf("%s/%d", srv.URL, i))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "120", expected: 2 * time.Minute, ok: true},
+		{value: "-1", ok: false},
+		{value: "Tue, 02 May 2023 12:01:00 GMT", expected: time.Minute, ok: true},
+		{value: "Tue, 02 May 2023 11:00:00 GMT", expected: 0, ok: true},
+		{value: "invalid", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			d, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
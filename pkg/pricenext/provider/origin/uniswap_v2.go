@@ -0,0 +1,106 @@
+package origin
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+var (
+	uniswapToken0Method        = abi.MustParseMethod("token0() view returns (address)")
+	uniswapToken1Method        = abi.MustParseMethod("token1() view returns (address)")
+	uniswapV2GetReservesMethod = abi.MustParseMethod("getReserves() view returns (uint112 reserve0, uint112 reserve1, uint32 blockTimestampLast)")
+)
+
+// UniswapV2Options are options for the UniswapV2 origin.
+type UniswapV2Options struct {
+	// Client is an Ethereum RPC client.
+	Client rpc.RPC
+
+	// Pools is a list of pools from which prices are read. Pools of
+	// Uniswap V2 forks, like SushiSwap, can be used as well.
+	Pools []DEXPool
+
+	// BlockOffset is the number of blocks behind the latest block at which
+	// the reserves are read.
+	BlockOffset uint64
+
+	// Now returns the current time, which is used as the time of ticks.
+	// If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// UniswapV2 is an origin that calculates prices from reserves of
+// Uniswap V2 pools.
+type UniswapV2 struct {
+	*dexOrigin
+}
+
+// NewUniswapV2 creates a new UniswapV2 instance.
+func NewUniswapV2(opts UniswapV2Options) (*UniswapV2, error) {
+	d, err := newDEXOrigin(opts.Client, twoTokenPools(opts.Pools), opts.BlockOffset, uniswapV2Adapter{}, opts.Now)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV2{dexOrigin: d}, nil
+}
+
+type uniswapV2Adapter struct{}
+
+func (uniswapV2Adapter) tokenCalls(pool dexPool) ([]types.Call, error) {
+	return uniswapTokenCalls(pool)
+}
+
+func (uniswapV2Adapter) priceCall(pool dexPool, _ [2]uint8) (types.Call, error) {
+	input, err := uniswapV2GetReservesMethod.EncodeArgs()
+	if err != nil {
+		return types.Call{}, err
+	}
+	return types.Call{To: &pool.address, Input: input}, nil
+}
+
+func (uniswapV2Adapter) price(_ dexPool, decimals [2]uint8, result []byte) (*bn.FloatNumber, error) {
+	var reserve0, reserve1 *big.Int
+	if err := uniswapV2GetReservesMethod.DecodeValues(result, &reserve0, &reserve1, nil); err != nil {
+		return nil, fmt.Errorf("failed to decode reserves: %w", err)
+	}
+	if reserve0.Sign() == 0 || reserve1.Sign() == 0 {
+		return nil, errors.New("pool has no liquidity")
+	}
+	amount0 := bn.Float(reserve0).Div(pow10(decimals[0]))
+	amount1 := bn.Float(reserve1).Div(pow10(decimals[1]))
+	return amount1.Div(amount0), nil
+}
+
+// uniswapTokenCalls returns calls to the token0 and token1 methods used by
+// Uniswap pools.
+func uniswapTokenCalls(pool dexPool) ([]types.Call, error) {
+	input0, err := uniswapToken0Method.EncodeArgs()
+	if err != nil {
+		return nil, err
+	}
+	input1, err := uniswapToken1Method.EncodeArgs()
+	if err != nil {
+		return nil, err
+	}
+	return []types.Call{
+		{To: &pool.address, Input: input0},
+		{To: &pool.address, Input: input1},
+	}, nil
+}
+
+// twoTokenPools converts a list of DEXPool to a list of dexPool.
+func twoTokenPools(pools []DEXPool) []dexPool {
+	ps := make([]dexPool, len(pools))
+	for i, p := range pools {
+		ps[i] = dexPool{pair: p.Pair, address: p.Address, indices: [2]int{0, 1}}
+	}
+	return ps
+}
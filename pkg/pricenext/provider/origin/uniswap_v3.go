@@ -0,0 +1,87 @@
+package origin
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+)
+
+var uniswapV3Slot0Method = abi.MustParseMethod(`
+	function slot0() view returns (
+		uint160 sqrtPriceX96,
+		int24 tick,
+		uint16 observationIndex,
+		uint16 observationCardinality,
+		uint16 observationCardinalityNext,
+		uint8 feeProtocol,
+		bool unlocked
+	)`,
+)
+
+// q192 is 2^192, used to convert sqrtPriceX96 to a price.
+var q192 = bn.Float(new(big.Int).Lsh(big.NewInt(1), 192))
+
+// UniswapV3Options are options for the UniswapV3 origin.
+type UniswapV3Options struct {
+	// Client is an Ethereum RPC client.
+	Client rpc.RPC
+
+	// Pools is a list of pools from which prices are read.
+	Pools []DEXPool
+
+	// BlockOffset is the number of blocks behind the latest block at which
+	// the pool state is read.
+	BlockOffset uint64
+
+	// Now returns the current time, which is used as the time of ticks.
+	// If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// UniswapV3 is an origin that reads current prices from Uniswap V3 pools.
+//
+// The price is calculated from the sqrtPriceX96 value returned by the slot0
+// method, hence it is the spot price of the pool.
+type UniswapV3 struct {
+	*dexOrigin
+}
+
+// NewUniswapV3 creates a new UniswapV3 instance.
+func NewUniswapV3(opts UniswapV3Options) (*UniswapV3, error) {
+	d, err := newDEXOrigin(opts.Client, twoTokenPools(opts.Pools), opts.BlockOffset, uniswapV3Adapter{}, opts.Now)
+	if err != nil {
+		return nil, err
+	}
+	return &UniswapV3{dexOrigin: d}, nil
+}
+
+type uniswapV3Adapter struct{}
+
+func (uniswapV3Adapter) tokenCalls(pool dexPool) ([]types.Call, error) {
+	return uniswapTokenCalls(pool)
+}
+
+func (uniswapV3Adapter) priceCall(pool dexPool, _ [2]uint8) (types.Call, error) {
+	input, err := uniswapV3Slot0Method.EncodeArgs()
+	if err != nil {
+		return types.Call{}, err
+	}
+	return types.Call{To: &pool.address, Input: input}, nil
+}
+
+func (uniswapV3Adapter) price(_ dexPool, decimals [2]uint8, result []byte) (*bn.FloatNumber, error) {
+	var sqrtPriceX96 *big.Int
+	if err := uniswapV3Slot0Method.DecodeValues(result, &sqrtPriceX96, nil, nil, nil, nil, nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to decode slot0: %w", err)
+	}
+
+	// price = (sqrtPriceX96 / 2^96)^2 * 10^decimals0 / 10^decimals1
+	sqrtPrice := bn.Float(sqrtPriceX96)
+	return sqrtPrice.Mul(sqrtPrice).Div(q192).Mul(pow10(decimals[0])).Div(pow10(decimals[1])), nil
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/graphrender"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/treerender"
 )
 
@@ -30,6 +33,22 @@ type Provider interface {
 	Models(ctx context.Context, models ...string) (map[string]Model, error)
 }
 
+// HistoryProvider is a provider that keeps a history of ticks and can
+// calculate prices as they would have been at a past time.
+type HistoryProvider interface {
+	Provider
+
+	// TickAt returns a price for the given model calculated using origin
+	// ticks that were available at the given time. For models that cannot
+	// be recalculated from origin ticks alone, the price recorded at that
+	// time is returned.
+	TickAt(ctx context.Context, model string, at time.Time) (Tick, error)
+
+	// ModelTicks returns prices calculated by the given model between the
+	// given times.
+	ModelTicks(ctx context.Context, model string, from, to time.Time) ([]Tick, error)
+}
+
 // Model is a simplified representation of a model which is used to calculate
 // asset pair prices. The main purpose of this structure is to help the end
 // user to understand how prices are derived and calculated.
@@ -82,6 +101,36 @@ func (m Model) MarshalTrace() ([]byte, error) {
 	}, []any{m}, 0), nil
 }
 
+// MarshalModelsGraph returns a diagram of the given models in the DOT or
+// Mermaid format. Models are drawn in a single graph, so sub models shared by
+// multiple models, e.g. referenced price models, are drawn only once.
+func MarshalModelsGraph(models map[string]Model, format graphrender.Format) ([]byte, error) {
+	var roots []graphrender.Root
+	for _, name := range maputil.SortKeys(models, sort.Strings) {
+		roots = append(roots, graphrender.Root{Name: name, Node: models[name]})
+	}
+	return graphrender.RenderGraph(format, func(node any) graphrender.NodeData {
+		model := node.(Model)
+		meta := copyMeta(model.Meta)
+		typ := "node"
+		if n, ok := meta["type"].(string); ok {
+			typ = n
+			delete(meta, "type")
+		}
+		meta["pair"] = model.Pair
+		var models []any
+		for _, m := range model.Models {
+			models = append(models, m)
+		}
+		return graphrender.NodeData{
+			Name:      typ,
+			Params:    meta,
+			Ancestors: models,
+			Reference: typ == "reference",
+		}
+	}, roots)
+}
+
 // Tick contains a price, volume and other information for a given asset pair
 // at a given time.
 //
@@ -199,6 +248,53 @@ func (t Tick) MarshalTrace() ([]byte, error) {
 	}, []any{t}, 0), nil
 }
 
+// MarshalTicksGraph returns a diagram of the given ticks and their sub ticks
+// in the DOT or Mermaid format. Every node shows its price and, if the tick
+// is invalid, the error.
+func MarshalTicksGraph(ticks map[string]Tick, format graphrender.Format) ([]byte, error) {
+	var roots []graphrender.Root
+	for _, name := range maputil.SortKeys(ticks, sort.Strings) {
+		roots = append(roots, graphrender.Root{Name: name, Node: ticks[name]})
+	}
+	return graphrender.RenderGraph(format, func(node any) graphrender.NodeData {
+		tick := node.(Tick)
+		err := tick.Validate()
+		meta := copyMeta(tick.Meta)
+		typ := "tick"
+		if n, ok := meta["type"].(string); ok {
+			typ = n
+			delete(meta, "type")
+		}
+		meta["pair"] = tick.Pair
+		meta["price"] = tick.Price
+		meta["time"] = tick.Time.In(time.UTC).Format(time.RFC3339Nano)
+		var ticks []any
+		for _, t := range tick.SubTicks {
+			ticks = append(ticks, t)
+		}
+		return graphrender.NodeData{
+			Name:      typ,
+			Params:    meta,
+			Ancestors: ticks,
+			Error:     err,
+			Reference: typ == "reference",
+		}
+	}, roots)
+}
+
+// copyMeta returns a copy of the metadata map. The metadata may be nil.
+// Copies are needed when a model or a tick is visited more than once, because
+// the map returned by Meta may be shared.
+func copyMeta(m Meta) map[string]any {
+	c := make(map[string]any)
+	if m != nil {
+		for k, v := range m.Meta() {
+			c[k] = v
+		}
+	}
+	return c
+}
+
 // Meta is an additional metadata for a price or a model.
 type Meta interface {
 	// Meta returns a map of metadata.
@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/bn"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/graphrender"
 )
 
 func TestTick_Validate(t *testing.T) {
@@ -188,3 +191,52 @@ func TestPair(t *testing.T) {
 		})
 	}
 }
+
+type testMeta map[string]any
+
+func (m testMeta) Meta() map[string]any {
+	return m
+}
+
+func TestMarshalModelsGraph(t *testing.T) {
+	pair := Pair{Base: "BTC", Quote: "USD"}
+	origin := Model{
+		Meta: testMeta{"type": "origin", "freshness_threshold": time.Minute, "expiry_threshold": 2 * time.Minute},
+		Pair: pair,
+	}
+	models := map[string]Model{
+		"a": {Meta: testMeta{"type": "reference"}, Pair: pair, Models: []Model{origin}},
+		"b": {Meta: testMeta{"type": "median"}, Pair: pair, Models: []Model{
+			{Meta: testMeta{"type": "reference"}, Pair: pair, Models: []Model{origin}},
+		}},
+	}
+	b, err := MarshalModelsGraph(models, graphrender.DOT)
+	require.NoError(t, err)
+
+	// The origin must be drawn once and referenced with a dashed edge.
+	assert.Equal(t, 1, strings.Count(string(b), "expiry_threshold: 2m0s"))
+	assert.Contains(t, string(b), "freshness_threshold: 1m0s")
+	assert.Contains(t, string(b), "n3 -> n1 [style=dashed]")
+	assert.NotContains(t, string(b), "reference")
+}
+
+func TestMarshalTicksGraph(t *testing.T) {
+	pair := Pair{Base: "BTC", Quote: "USD"}
+	ticks := map[string]Tick{
+		"a": {
+			Pair:  pair,
+			Price: bn.Float(42),
+			Time:  time.Date(2023, 5, 2, 12, 34, 56, 0, time.UTC),
+			Meta:  testMeta{"type": "median"},
+			SubTicks: []Tick{
+				{Pair: pair, Price: bn.Float(42), Time: time.Date(2023, 5, 2, 12, 34, 56, 0, time.UTC)},
+				{Pair: pair, Error: errors.New("failed")},
+			},
+		},
+	}
+	b, err := MarshalTicksGraph(ticks, graphrender.Mermaid)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "median<br>pair: BTC/USD<br>price: 42")
+	assert.Contains(t, string(b), "error: failed")
+	assert.Contains(t, string(b), "class n2 error")
+}
@@ -0,0 +1,94 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+const httpKind = "http"
+
+type httpRecord struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+
+	// BodyBase64 is used instead of Body if the body is not valid UTF-8.
+	BodyBase64 []byte `json:"body_base64,omitempty"`
+}
+
+type roundTripper struct {
+	fixture   *Fixture
+	transport http.RoundTripper
+}
+
+// RoundTripper returns an http.RoundTripper that records responses returned
+// by the given transport or replays them, depending on the fixture mode.
+//
+// Requests are identified by the method and URL. If transport is nil,
+// http.DefaultTransport is used. In the replay mode, the transport is
+// not used.
+func (f *Fixture) RoundTripper(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &roundTripper{fixture: f, transport: transport}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+	if r.fixture.mode == Replay {
+		var rec httpRecord
+		if err := r.fixture.load(httpKind, key, &rec); err != nil {
+			return nil, fmt.Errorf("replay: %s: %w", key, err)
+		}
+		return rec.response(req), nil
+	}
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	rec := httpRecord{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+	}
+	if utf8.Valid(body) {
+		rec.Body = string(body)
+	} else {
+		rec.BodyBase64 = body
+	}
+	if err := r.fixture.store(httpKind, key, rec); err != nil {
+		return nil, fmt.Errorf("record: %s: %w", key, err)
+	}
+	return rec.response(req), nil
+}
+
+func (r httpRecord) response(req *http.Request) *http.Response {
+	body := []byte(r.Body)
+	if r.BodyBase64 != nil {
+		body = r.BodyBase64
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,173 @@
+// Package replay provides tools to record responses of price origins into
+// a fixture directory and to replay them later without network access.
+//
+// HTTP responses are recorded using an http.RoundTripper and Ethereum
+// JSON-RPC results using an rpc.RPC wrapper. Each response is stored in
+// a separate JSON file named after a hash of the request, so fixtures can be
+// inspected and edited by hand.
+//
+// The time at which responses were recorded is stored in the fixture as
+// well. Price models must use it as the current time, so that ticks are
+// evaluated the same way during the replay as during the recording.
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// metaFile is the name of the file in which the fixture metadata is stored.
+const metaFile = "fixture.json"
+
+// Mode is the mode in which a Fixture operates.
+type Mode int
+
+const (
+	// Record mode performs requests and stores responses in the fixture
+	// directory.
+	Record Mode = iota
+
+	// Replay mode returns responses stored in the fixture directory without
+	// performing any requests.
+	Replay
+)
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case Record:
+		return "record"
+	case Replay:
+		return "replay"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrNotRecorded is returned in the replay mode if there is no recorded
+// response for a request.
+var ErrNotRecorded = errors.New("response not recorded")
+
+// Fixture is a directory with recorded responses.
+type Fixture struct {
+	mu   sync.Mutex
+	dir  string
+	mode Mode
+	time time.Time
+}
+
+type fixtureMeta struct {
+	// Time is the time at which responses were recorded.
+	Time time.Time `json:"time"`
+}
+
+// NewFixture creates a new Fixture instance.
+//
+// In the record mode, the directory is created if it does not exist, and the
+// current time is stored in the fixture. In the replay mode, the directory
+// must exist, and the stored time is read from it.
+func NewFixture(dir string, mode Mode) (*Fixture, error) {
+	f := &Fixture{dir: dir, mode: mode}
+	switch mode {
+	case Record:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("unable to create fixture directory: %w", err)
+		}
+		if err := f.setTime(time.Now()); err != nil {
+			return nil, fmt.Errorf("unable to write fixture metadata: %w", err)
+		}
+	case Replay:
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open fixture directory: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+		var meta fixtureMeta
+		if err := readJSON(filepath.Join(dir, metaFile), &meta); err != nil {
+			return nil, fmt.Errorf("unable to read fixture metadata: %w", err)
+		}
+		f.time = meta.Time
+	default:
+		return nil, fmt.Errorf("unknown mode: %d", mode)
+	}
+	return f, nil
+}
+
+// Mode returns the mode of the fixture.
+func (f *Fixture) Mode() Mode {
+	return f.mode
+}
+
+// Dir returns the fixture directory.
+func (f *Fixture) Dir() string {
+	return f.dir
+}
+
+// Time returns the time at which responses were recorded. It should be used
+// as the current time by price models, both in the record and in the replay
+// mode, so that the results do not depend on when the fixture is replayed.
+func (f *Fixture) Time() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.time
+}
+
+// setTime sets the time at which responses were recorded and stores it in
+// the fixture directory.
+func (f *Fixture) setTime(t time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.time = t
+	return writeJSON(filepath.Join(f.dir, metaFile), fixtureMeta{Time: t})
+}
+
+// store saves the value in a file identified by the kind and the key.
+func (f *Fixture) store(kind, key string, v any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := f.path(kind, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeJSON(path, v)
+}
+
+// load reads the value from a file identified by the kind and the key.
+func (f *Fixture) load(kind, key string, v any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return readJSON(f.path(kind, key), v)
+}
+
+func (f *Fixture) path(kind, key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, kind, hex.EncodeToString(h[:16])+".json")
+}
+
+func writeJSON(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644) //nolint:gosec
+}
+
+func readJSON(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotRecorded
+		}
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,278 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/config"
+	priceProviderConfig "github.com/chronicleprotocol/oracle-suite/pkg/config/priceprovidernext"
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/pricenext/provider"
+)
+
+func TestNewFixture(t *testing.T) {
+	_, err := NewFixture(t.TempDir()+"/missing", Replay)
+	assert.Error(t, err)
+
+	f, err := NewFixture(t.TempDir()+"/new", Record)
+	require.NoError(t, err)
+	assert.Equal(t, Record, f.Mode())
+
+	// The recording time is stored in the fixture.
+	r, err := NewFixture(f.Dir(), Replay)
+	require.NoError(t, err)
+	assert.True(t, f.Time().Equal(r.Time()))
+}
+
+func TestRoundTripper(t *testing.T) {
+	dir := t.TempDir()
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"price": "42"}`))
+	}))
+	defer srv.Close()
+
+	get := func(client *http.Client, url string) (*http.Response, string, error) {
+		res, err := client.Get(url)
+		if err != nil {
+			return nil, "", err
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		return res, string(body), err
+	}
+
+	// Record.
+	recorder, err := NewFixture(dir, Record)
+	require.NoError(t, err)
+	res, body, err := get(&http.Client{Transport: recorder.RoundTripper(nil)}, srv.URL+"/price")
+	require.NoError(t, err)
+	assert.Equal(t, `{"price": "42"}`, body)
+	assert.Equal(t, 1, requests)
+
+	// Replay.
+	replayer, err := NewFixture(dir, Replay)
+	require.NoError(t, err)
+	client := &http.Client{Transport: replayer.RoundTripper(nil)}
+	res2, body2, err := get(client, srv.URL+"/price")
+	require.NoError(t, err)
+	assert.Equal(t, body, body2)
+	assert.Equal(t, res.StatusCode, res2.StatusCode)
+	assert.Equal(t, "application/json", res2.Header.Get("Content-Type"))
+	assert.Equal(t, 1, requests)
+
+	// Not recorded.
+	_, _, err = get(client, srv.URL+"/other")
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestRPC(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	address := types.MustAddressFromHex("0x1000000000000000000000000000000000000001")
+	call := types.Call{To: &address, Input: []byte{1, 2, 3, 4}}
+
+	client := &mocks.RPC{}
+	client.On("ChainID", ctx).Return(uint64(1), nil).Once()
+	client.On("BlockNumber", ctx).Return(big.NewInt(42), nil).Once()
+	client.On("Call", ctx, call, mock.Anything).Return([]byte{5, 6, 7, 8}, nil).Once()
+
+	// Record.
+	recorder, err := NewFixture(dir, Record)
+	require.NoError(t, err)
+	wrapped := recorder.RPCClients(map[string]rpc.RPC{"default": client})["default"]
+	chainID, err := wrapped.ChainID(ctx)
+	require.NoError(t, err)
+	blockNumber, err := wrapped.BlockNumber(ctx)
+	require.NoError(t, err)
+	result, err := wrapped.Call(ctx, call, types.BlockNumberFromUint64(42))
+	require.NoError(t, err)
+	client.AssertExpectations(t)
+
+	// Replay, without the underlying client.
+	replayer, err := NewFixture(dir, Replay)
+	require.NoError(t, err)
+	wrapped = replayer.RPC("default", nil)
+	chainID2, err := wrapped.ChainID(ctx)
+	require.NoError(t, err)
+	blockNumber2, err := wrapped.BlockNumber(ctx)
+	require.NoError(t, err)
+	result2, err := wrapped.Call(ctx, call, types.BlockNumberFromUint64(40))
+	require.NoError(t, err)
+	assert.Equal(t, chainID, chainID2)
+	assert.Equal(t, blockNumber, blockNumber2)
+	assert.Equal(t, result, result2)
+
+	// Not recorded.
+	_, err = wrapped.Call(ctx, types.Call{To: &address, Input: []byte{0}}, types.LatestBlockNumber)
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	_, err = replayer.RPC("other", nil).ChainID(ctx)
+	assert.ErrorIs(t, err, ErrNotRecorded)
+}
+
+const pricesConfig = `
+origin "test" {
+  origin = "generic_jq"
+  url    = "%s/$${ucbase}/$${ucquote}"
+  jq     = "{price: .price, time: .time}"
+}
+
+price_model "BTC/USD" "BTC/USD" {
+  median "BTC/USD" {
+    origin "test" "BTC/USD" { }
+    indirect "BTC/USD" {
+      origin "test" "BTC/ETH" { }
+      origin "test" "ETH/USD" { }
+    }
+    min_sources = 2
+  }
+}
+`
+
+func TestReplay_Prices(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Responses are recorded as of an hour ago, so the recorded ticks are
+	// expired relative to the wall clock.
+	recTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	prices := map[string]string{"/BTC/USD": "30000", "/BTC/ETH": "15", "/ETH/USD": "2000"}
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = fmt.Fprintf(w, `{"price": %q, "time": %d}`, prices[r.URL.Path], recTime.Unix())
+	}))
+	defer srv.Close()
+	cfgPath := filepath.Join(t.TempDir(), "config.hcl")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(fmt.Sprintf(pricesConfig, srv.URL)), 0o600))
+
+	ticks := func(f *Fixture, clock func() time.Time) map[string]provider.Tick {
+		var cfg priceProviderConfig.Config
+		require.NoError(t, config.LoadFiles(&cfg, []string{cfgPath}))
+		p, err := cfg.PriceProvider(priceProviderConfig.Dependencies{
+			HTTPClient: &http.Client{Transport: f.RoundTripper(nil)},
+			Logger:     null.New(),
+			Clock:      clock,
+		}, true)
+		require.NoError(t, err)
+		ticks, err := p.Ticks(ctx, p.ModelNames(ctx)...)
+		require.NoError(t, err)
+		return ticks
+	}
+
+	// Record.
+	recorder, err := NewFixture(dir, Record)
+	require.NoError(t, err)
+	require.NoError(t, recorder.setTime(recTime.Add(time.Second)))
+	recorded := ticks(recorder, recorder.Time)
+	require.NoError(t, recorded["BTC/USD"].Validate())
+	assert.Equal(t, 3, requests)
+
+	// Replay.
+	replayer, err := NewFixture(dir, Replay)
+	require.NoError(t, err)
+	replayed := ticks(replayer, replayer.Time)
+	require.Len(t, replayed, len(recorded))
+	for model, tick := range recorded {
+		require.NoError(t, replayed[model].Validate())
+		assert.Equal(t, tick.Price.String(), replayed[model].Price.String())
+		assert.True(t, tick.Time.Equal(replayed[model].Time))
+	}
+	assert.Equal(t, 3, requests)
+
+	// Without the recording time, the replayed ticks are expired.
+	require.Error(t, ticks(replayer, nil)["BTC/USD"].Validate())
+}
+
+const deterministicConfig = `
+origin "test" {
+  origin = "generic_jq"
+  url    = "%s/$${ucbase}/$${ucquote}"
+  jq     = "{price: .price}"
+}
+
+price_model "BTC/USD" "BTC/USD" {
+  twap "BTC/USD" {
+    window = 300
+    origin "test" "BTC/USD" { }
+  }
+}
+
+price_model "ETH/USD" "ETH/USD" {
+  expression "ETH/USD" {
+    expression = "2000"
+  }
+}
+`
+
+func TestReplay_Deterministic(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Responses do not contain the price time, so ticks are stamped with
+	// the current time.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"price": "30000"}`)
+	}))
+	defer srv.Close()
+	cfgPath := filepath.Join(t.TempDir(), "config.hcl")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(fmt.Sprintf(deterministicConfig, srv.URL)), 0o600))
+
+	ticks := func(f *Fixture) map[string]provider.Tick {
+		var cfg priceProviderConfig.Config
+		require.NoError(t, config.LoadFiles(&cfg, []string{cfgPath}))
+		p, err := cfg.PriceProvider(priceProviderConfig.Dependencies{
+			HTTPClient: &http.Client{Transport: f.RoundTripper(nil)},
+			Logger:     null.New(),
+			Clock:      f.Time,
+		}, true)
+		require.NoError(t, err)
+		ticks, err := p.Ticks(ctx, p.ModelNames(ctx)...)
+		require.NoError(t, err)
+		return ticks
+	}
+
+	// Record.
+	recorder, err := NewFixture(dir, Record)
+	require.NoError(t, err)
+	ticks(recorder)
+	srv.Close()
+
+	// Replaying the same fixture twice must produce the same output, and
+	// ticks must be stamped with the recording time. A single observation
+	// does not cover the TWAP window, so the TWAP tick is an error.
+	var outputs [][]byte
+	for i := 0; i < 2; i++ {
+		replayer, err := NewFixture(dir, Replay)
+		require.NoError(t, err)
+		replayed := ticks(replayer)
+		require.ErrorContains(t, replayed["BTC/USD"].Validate(), "observations cover 0s of the 5m0s window")
+		require.NoError(t, replayed["ETH/USD"].Validate())
+		for model, tick := range replayed {
+			assert.True(t, replayer.Time().Equal(tick.Time), model)
+		}
+		out, err := json.Marshal(replayed)
+		require.NoError(t, err)
+		outputs = append(outputs, out)
+		time.Sleep(time.Second)
+	}
+	assert.JSONEq(t, string(outputs[0]), string(outputs[1]))
+}
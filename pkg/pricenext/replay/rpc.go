@@ -0,0 +1,139 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+)
+
+const rpcKind = "rpc"
+
+type rpcRecord struct {
+	Client string          `json:"client"`
+	Method string          `json:"method"`
+	Params any             `json:"params,omitempty"`
+	Result json.RawMessage `json:"result"`
+}
+
+// rpcClient records or replays results of the RPC methods used by price
+// origins: eth_chainId, eth_blockNumber and eth_call. Other methods are
+// passed to the wrapped client.
+type rpcClient struct {
+	rpc.RPC
+	fixture *Fixture
+	name    string
+}
+
+// RPC returns an rpc.RPC client that records results returned by the given
+// client or replays them, depending on the fixture mode.
+//
+// Only the eth_chainId, eth_blockNumber and eth_call methods are recorded.
+// Calls are identified by the target address and input data, the block
+// number is ignored, so results can be replayed regardless of the block
+// number at which they were recorded. In the replay mode, other methods
+// are passed to the given client, which may be nil if they are not used.
+func (f *Fixture) RPC(name string, client rpc.RPC) rpc.RPC {
+	return &rpcClient{RPC: client, fixture: f, name: name}
+}
+
+// RPCClients wraps all clients using the RPC method.
+func (f *Fixture) RPCClients(clients map[string]rpc.RPC) map[string]rpc.RPC {
+	if clients == nil {
+		return nil
+	}
+	wrapped := make(map[string]rpc.RPC, len(clients))
+	for name, client := range clients {
+		wrapped[name] = f.RPC(name, client)
+	}
+	return wrapped
+}
+
+// ChainID implements the rpc.RPC interface.
+func (c *rpcClient) ChainID(ctx context.Context) (uint64, error) {
+	return recordRPC(c, "eth_chainId", nil, func() (uint64, error) {
+		return c.RPC.ChainID(ctx)
+	})
+}
+
+// BlockNumber implements the rpc.RPC interface.
+func (c *rpcClient) BlockNumber(ctx context.Context) (*big.Int, error) {
+	return recordRPC(c, "eth_blockNumber", nil, func() (*big.Int, error) {
+		return c.RPC.BlockNumber(ctx)
+	})
+}
+
+// Call implements the rpc.RPC interface.
+func (c *rpcClient) Call(ctx context.Context, call types.Call, block types.BlockNumber) ([]byte, error) {
+	var to string
+	if call.To != nil {
+		to = call.To.String()
+	}
+	params := map[string]string{"to": to, "input": hexutil.BytesToHex(call.Input)}
+	res, err := recordRPC(c, "eth_call", params, func() (hexBytes, error) {
+		return c.RPC.Call(ctx, call, block)
+	})
+	return res, err
+}
+
+// hexBytes is a byte slice that is encoded to JSON as a hex string.
+type hexBytes []byte
+
+func (b hexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutil.BytesToHex(b))
+}
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := hexutil.HexToBytes(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// recordRPC records the result of the given function, or returns
+// the previously recorded result in the replay mode.
+func recordRPC[T any](c *rpcClient, method string, params any, fn func() (T, error)) (T, error) {
+	var zero T
+	key := c.name + " " + method
+	if params != nil {
+		p, err := json.Marshal(params)
+		if err != nil {
+			return zero, err
+		}
+		key += " " + string(p)
+	}
+	if c.fixture.mode == Replay {
+		var rec rpcRecord
+		if err := c.fixture.load(rpcKind, key, &rec); err != nil {
+			return zero, fmt.Errorf("replay: %s: %w", key, err)
+		}
+		var res T
+		if err := json.Unmarshal(rec.Result, &res); err != nil {
+			return zero, fmt.Errorf("replay: %s: %w", key, err)
+		}
+		return res, nil
+	}
+	res, err := fn()
+	if err != nil {
+		return zero, err
+	}
+	result, err := json.Marshal(res)
+	if err != nil {
+		return zero, err
+	}
+	rec := rpcRecord{Client: c.name, Method: method, Params: params, Result: result}
+	if err := c.fixture.store(rpcKind, key, rec); err != nil {
+		return zero, fmt.Errorf("record: %s: %w", key, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,392 @@
+// Package allowlist provides lists of feeds that are allowed to send messages
+// over the transport layer.
+//
+// Besides the static list, the allowlist can be read from a file or from an
+// Ethereum contract. These lists are refreshed at runtime, so feeds can be
+// added or removed without restarting nodes.
+package allowlist
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+)
+
+const LoggerTag = "ALLOWLIST"
+
+// minRetryInterval is the initial interval between attempts to fetch the
+// list after a failure. The interval is doubled after each consecutive
+// failure, up to the refresh interval of the list.
+const minRetryInterval = time.Second
+
+// fetchTimeout is the maximum time a single fetch of the list may take.
+const fetchTimeout = 30 * time.Second
+
+// Allowlist provides a list of feed addresses that are allowed to send
+// messages.
+type Allowlist interface {
+	// Feeds returns the list of allowed feed addresses.
+	Feeds(ctx context.Context) ([]types.Address, error)
+}
+
+// IsAllowed returns true if the given address is on the allowlist.
+func IsAllowed(ctx context.Context, a Allowlist, addr types.Address) (bool, error) {
+	feeds, err := a.Feeds(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range feeds {
+		if f == addr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MultiAllowlist is an implementation of Allowlist that merges the addresses
+// from multiple Allowlist instances.
+//
+// If some of the lists cannot be fetched, the addresses from the remaining
+// lists are returned, so a failing file or contract does not cause the
+// static list to be rejected. An error is returned only if all lists fail.
+type MultiAllowlist struct {
+	lists []Allowlist
+}
+
+// NewMultiAllowlist creates a new instance of MultiAllowlist.
+func NewMultiAllowlist(lists ...Allowlist) *MultiAllowlist {
+	return &MultiAllowlist{
+		lists: lists,
+	}
+}
+
+// Feeds implements the Allowlist interface.
+func (m *MultiAllowlist) Feeds(ctx context.Context) ([]types.Address, error) {
+	var (
+		feeds   []types.Address
+		lastErr error
+		failed  int
+	)
+	seen := make(map[types.Address]bool)
+	for _, list := range m.lists {
+		toMerge, err := list.Feeds(ctx)
+		if err != nil {
+			// Errors are logged by the lists when the fetch fails, so
+			// they are not logged here on every call.
+			lastErr = err
+			failed++
+			continue
+		}
+		for _, addr := range toMerge {
+			if !seen[addr] {
+				seen[addr] = true
+				feeds = append(feeds, addr)
+			}
+		}
+	}
+	if failed > 0 && failed == len(m.lists) {
+		return nil, lastErr
+	}
+	return feeds, nil
+}
+
+// StaticAllowlist is an implementation of Allowlist that returns a static
+// list of addresses.
+type StaticAllowlist struct {
+	feeds []types.Address
+}
+
+// NewStaticAllowlist creates a new instance of StaticAllowlist.
+func NewStaticAllowlist(feeds []types.Address) *StaticAllowlist {
+	return &StaticAllowlist{
+		feeds: feeds,
+	}
+}
+
+// Feeds implements the Allowlist interface.
+func (s *StaticAllowlist) Feeds(_ context.Context) ([]types.Address, error) {
+	return s.feeds, nil
+}
+
+// FileAllowlist is an implementation of Allowlist that reads the list of
+// addresses from a file.
+//
+// The file contains hex-encoded addresses separated by new lines, spaces or
+// commas. Lines starting with "#" are ignored. The file is read again when
+// the cached list expires, so changes in the file are applied without
+// restarting the node.
+type FileAllowlist struct {
+	cache *cache
+	path  string
+}
+
+// NewFileAllowlist creates a new instance of FileAllowlist. The reloadInterval
+// parameter specifies how often the file is read again.
+func NewFileAllowlist(path string, reloadInterval time.Duration, logger log.Logger) *FileAllowlist {
+	f := &FileAllowlist{path: path}
+	f.cache = newCache("file:"+path, reloadInterval, f.fetchFeeds, logger)
+	return f
+}
+
+// Feeds implements the Allowlist interface.
+func (f *FileAllowlist) Feeds(ctx context.Context) ([]types.Address, error) {
+	return f.cache.feeds(ctx)
+}
+
+func (f *FileAllowlist) fetchFeeds(_ context.Context) ([]types.Address, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeeds(string(data))
+}
+
+// EthereumAllowlist is an implementation of Allowlist that reads the list of
+// addresses from an Ethereum contract.
+//
+// The contract must implement the "feeds() returns (address[])" method.
+type EthereumAllowlist struct {
+	cache   *cache
+	client  rpc.RPC       // Ethereum client
+	address types.Address // Address of the contract.
+}
+
+// NewEthereumAllowlist creates a new instance of EthereumAllowlist.
+// The cacheTTL parameter specifies how long the list of addresses should be
+// cached before it is fetched again from the Ethereum contract.
+func NewEthereumAllowlist(r rpc.RPC, addr types.Address, cacheTTL time.Duration, logger log.Logger) *EthereumAllowlist {
+	e := &EthereumAllowlist{client: r, address: addr}
+	e.cache = newCache("ethereum:"+addr.String(), cacheTTL, e.fetchFeeds, logger)
+	return e
+}
+
+// Feeds implements the Allowlist interface.
+func (e *EthereumAllowlist) Feeds(ctx context.Context) ([]types.Address, error) {
+	return e.cache.feeds(ctx)
+}
+
+func (e *EthereumAllowlist) fetchFeeds(ctx context.Context) ([]types.Address, error) {
+	cd, err := feedsMethod.EncodeArgs()
+	if err != nil {
+		return nil, err
+	}
+	res, err := e.client.Call(ctx, types.Call{
+		To:    &e.address,
+		Input: cd,
+	}, types.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var feeds []types.Address
+	err = feedsMethod.DecodeValues(res, &feeds)
+	if err != nil {
+		return nil, err
+	}
+	return feeds, nil
+}
+
+var feedsMethod = abi.MustParseMethod("function feeds() returns (address[])")
+
+// cache caches the list of feeds returned by the fetch function and logs
+// changes in the list.
+//
+// Once the list is fetched, it is refreshed in the background after it
+// expires, and the previous list is used until the refresh completes, so
+// callers are never blocked by a slow source. Only the initial fetch is
+// waited for. If the list cannot be fetched, the previous list is used, or
+// the last error is returned if there is no previous list. Failed attempts
+// are retried with an exponential backoff, so the source is not queried on
+// every call.
+type cache struct {
+	mu sync.Mutex
+
+	source     string
+	fetch      func(ctx context.Context) ([]types.Address, error)
+	cache      []types.Address
+	cacheTime  time.Time
+	cacheTTL   time.Duration
+	lastErr    error
+	retryTime  time.Time
+	backoff    time.Duration
+	refreshing chan struct{} // closed when the current fetch completes, nil if there is none
+	log        log.Logger
+}
+
+func newCache(
+	source string,
+	ttl time.Duration,
+	fetch func(ctx context.Context) ([]types.Address, error),
+	logger log.Logger,
+) *cache {
+
+	if logger == nil {
+		logger = null.New()
+	}
+	return &cache{
+		source:   source,
+		fetch:    fetch,
+		cacheTTL: ttl,
+		log:      logger.WithFields(log.Fields{"tag": LoggerTag, "source": source}),
+	}
+}
+
+func (c *cache) feeds(ctx context.Context) ([]types.Address, error) {
+	c.mu.Lock()
+	done := c.refresh()
+	if c.cache != nil {
+		defer c.mu.Unlock()
+		return c.cache, nil
+	}
+	c.mu.Unlock()
+
+	// There is no previous list, so the fetch must be waited for.
+	if done != nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-done:
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache == nil {
+		return nil, c.lastErr
+	}
+	return c.cache, nil
+}
+
+// refresh starts fetching the list in the background if the cached list is
+// expired, unless a fetch is already in progress or a retry is not due yet.
+// It returns a channel that is closed when the current fetch completes, or
+// nil if there is no fetch in progress. It must be called with the mutex
+// held.
+func (c *cache) refresh() chan struct{} {
+	now := time.Now()
+	if c.refreshing != nil {
+		return c.refreshing
+	}
+	if c.cache != nil && c.cacheTime.Add(c.cacheTTL).After(now) {
+		return nil
+	}
+	if now.Before(c.retryTime) {
+		return nil
+	}
+	done := make(chan struct{})
+	c.refreshing = done
+	go func() {
+		defer close(done)
+		ctx, ctxCancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer ctxCancel()
+		feeds, err := c.fetch(ctx)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.refreshing = nil
+		c.handleFetch(feeds, err)
+	}()
+	return done
+}
+
+// handleFetch updates the cache with the result of a fetch. It must be
+// called with the mutex held.
+func (c *cache) handleFetch(feeds []types.Address, err error) {
+	if err != nil {
+		c.lastErr = fmt.Errorf("unable to fetch allowlist from %s: %w", c.source, err)
+		c.backoff = c.nextBackoff()
+		c.retryTime = time.Now().Add(c.backoff)
+		if c.cache == nil {
+			c.log.
+				WithError(err).
+				WithField("retryIn", c.backoff.String()).
+				Warn("Unable to fetch the allowlist")
+			return
+		}
+		c.log.
+			WithError(err).
+			WithField("retryIn", c.backoff.String()).
+			Warn("Unable to refresh the allowlist, using the previous one")
+		return
+	}
+	c.lastErr = nil
+	c.retryTime = time.Time{}
+	c.backoff = 0
+	if feeds == nil {
+		feeds = []types.Address{}
+	}
+	c.update(feeds)
+}
+
+// nextBackoff returns the interval to wait before the next attempt after
+// a failed fetch. The interval never exceeds the cache TTL.
+func (c *cache) nextBackoff() time.Duration {
+	backoff := minRetryInterval
+	if c.backoff > 0 {
+		backoff = c.backoff * 2
+	}
+	if backoff > c.cacheTTL {
+		backoff = c.cacheTTL
+	}
+	return backoff
+}
+
+// update replaces the cached list and logs the differences. The log fields
+// may be used to build metrics using the Grafana logger.
+func (c *cache) update(feeds []types.Address) {
+	prev := make(map[types.Address]bool, len(c.cache))
+	for _, addr := range c.cache {
+		prev[addr] = true
+	}
+	curr := make(map[types.Address]bool, len(feeds))
+	var added, removed []string
+	for _, addr := range feeds {
+		curr[addr] = true
+		if !prev[addr] {
+			added = append(added, addr.String())
+		}
+	}
+	for _, addr := range c.cache {
+		if !curr[addr] {
+			removed = append(removed, addr.String())
+		}
+	}
+	initial := c.cache == nil
+	c.cache = feeds
+	c.cacheTime = time.Now()
+	if !initial && len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	c.log.
+		WithFields(log.Fields{
+			"feeds":   len(feeds),
+			"added":   added,
+			"removed": removed,
+		}).
+		Info("Allowlist updated")
+}
+
+func parseFeeds(s string) ([]types.Address, error) {
+	var feeds []types.Address
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, f := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			addr, err := types.AddressFromHex(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", f, err)
+			}
+			feeds = append(feeds, addr)
+		}
+	}
+	return feeds, nil
+}
@@ -0,0 +1,218 @@
+package allowlist
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/ethereum/mocks"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/errutil"
+)
+
+var (
+	address1 = types.MustAddressFromHex("0x2d800d93b065ce011af83f316cef9f0d005b0aa4")
+	address2 = types.MustAddressFromHex("0x8eb3daaf5cb4138f5f96711c09c0cfd0288a36e9")
+	address3 = types.MustAddressFromHex("0x1234567890123456789012345678901234567890")
+)
+
+func TestMultiAllowlist_Feeds(t *testing.T) {
+	list := NewMultiAllowlist(
+		NewStaticAllowlist([]types.Address{address1, address2}),
+		NewStaticAllowlist(nil),
+		NewStaticAllowlist([]types.Address{address2, address3}),
+	)
+	feeds, err := list.Feeds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1, address2, address3}, feeds)
+}
+
+func TestMultiAllowlist_FeedsPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	missing := NewFileAllowlist(filepath.Join(t.TempDir(), "feeds"), time.Minute, nil)
+
+	// The failing list must not cause the other lists to be rejected.
+	list := NewMultiAllowlist(NewStaticAllowlist([]types.Address{address1}), missing)
+	feeds, err := list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1}, feeds)
+
+	// An error is returned only if all lists fail.
+	list = NewMultiAllowlist(missing)
+	_, err = list.Feeds(ctx)
+	require.Error(t, err)
+}
+
+func TestIsAllowed(t *testing.T) {
+	list := NewStaticAllowlist([]types.Address{address1})
+	ok, err := IsAllowed(context.Background(), list, address1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = IsAllowed(context.Background(), list, address2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFileAllowlist_Feeds(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "feeds")
+	list := NewFileAllowlist(path, 0, nil)
+
+	// The file does not exist yet.
+	_, err := list.Feeds(ctx)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("# comment\n"+address1.String()+"\n"+address2.String()+", "+address3.String()+"\n"), 0600))
+	feeds, err := list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1, address2, address3}, feeds)
+
+	// Changes in the file are applied on the next reload. The previous
+	// list is used until the reload completes.
+	require.NoError(t, os.WriteFile(path, []byte(address2.String()), 0600))
+	feeds, err = list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1, address2, address3}, feeds)
+	waitForRefresh(list.cache)
+	feeds, err = list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address2}, feeds)
+
+	// Invalid file content does not replace the previous list.
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0600))
+	waitForRefresh(list.cache)
+	feeds, err = list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address2}, feeds)
+}
+
+func TestEthereumAllowlist_Feeds(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		rpc   = &mocks.RPC{}
+		to    = types.MustAddressFromHex("0x1234567890123456789012345678901234567890")
+		input = hexutil.MustHexToBytes("0xd63605b8")
+		call  = types.Call{To: &to, Input: input}
+	)
+
+	// Call method should be called once, because the result is cached.
+	rpc.On("Call", mock.Anything, call, types.LatestBlockNumber).Return(encodeAddresses([]types.Address{address1}), nil).Once()
+	list := NewEthereumAllowlist(rpc, to, time.Second, nil)
+	feeds, err := list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1}, feeds)
+	_, err = list.Feeds(ctx)
+	require.NoError(t, err)
+
+	// After one second, the cache is invalided and refreshed in
+	// the background.
+	time.Sleep(time.Second)
+	rpc.On("Call", mock.Anything, call, types.LatestBlockNumber).Return(encodeAddresses([]types.Address{address1, address2}), nil).Once()
+	_, err = list.Feeds(ctx)
+	require.NoError(t, err)
+	waitForRefresh(list.cache)
+	feeds, err = list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1, address2}, feeds)
+
+	// If the call fails, the previous list is used.
+	time.Sleep(time.Second)
+	rpc.On("Call", mock.Anything, call, types.LatestBlockNumber).Return([]byte(nil), errors.New("error")).Once()
+	_, err = list.Feeds(ctx)
+	require.NoError(t, err)
+	waitForRefresh(list.cache)
+	feeds, err = list.Feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1, address2}, feeds)
+	rpc.AssertExpectations(t)
+}
+
+func TestCache_Backoff(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	fail := true
+	c := newCache("test", time.Minute, func(ctx context.Context) ([]types.Address, error) {
+		calls++
+		if fail {
+			return nil, errors.New("error")
+		}
+		return []types.Address{address1}, nil
+	}, nil)
+
+	// Subsequent calls within the backoff interval must not fetch the list.
+	_, err := c.feeds(ctx)
+	require.Error(t, err)
+	_, err = c.feeds(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, minRetryInterval, c.backoff)
+
+	// The interval is doubled after each failure.
+	c.retryTime = time.Time{}
+	_, err = c.feeds(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2*minRetryInterval, c.backoff)
+
+	// After a successful fetch, the backoff is reset.
+	fail = false
+	c.retryTime = time.Time{}
+	feeds, err := c.feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1}, feeds)
+	assert.Zero(t, c.backoff)
+}
+
+func TestCache_SlowRefresh(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	calls := 0
+	c := newCache("test", time.Millisecond, func(ctx context.Context) ([]types.Address, error) {
+		calls++
+		if calls > 1 {
+			<-release
+		}
+		return []types.Address{address1}, nil
+	}, nil)
+
+	// The initial fetch is waited for.
+	feeds, err := c.feeds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{address1}, feeds)
+
+	// A slow refresh must not block callers, the previous list is used
+	// until the refresh completes.
+	time.Sleep(2 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		feeds, err = c.feeds(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []types.Address{address1}, feeds)
+	}
+	close(release)
+	waitForRefresh(c)
+	assert.Equal(t, 2, calls)
+}
+
+// waitForRefresh waits until the background refresh of the cache, if any,
+// completes.
+func waitForRefresh(c *cache) {
+	c.mu.Lock()
+	done := c.refreshing
+	c.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+func encodeAddresses(addresses []types.Address) []byte {
+	return errutil.Must(abi.EncodeValues(feedsMethod.Outputs(), addresses))
+}
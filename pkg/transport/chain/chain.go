@@ -17,38 +17,128 @@ package chain
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/chanutil"
 )
 
+const LoggerTag = "CHAIN"
+
+// defaultDedupWindow is the default time window in which messages with the
+// same content are considered duplicates.
+const defaultDedupWindow = 5 * time.Minute
+
 // Chain is a transport implementation that chains multiple transports
 // together.
+//
+// Messages with the same content received from different transports (or
+// received more than once from the same transport) within the deduplication
+// window are delivered only once. For each transport, the number of messages
+// that were received first and the number of duplicates are counted, which
+// shows which transport delivers messages faster.
 type Chain struct {
+	mu     sync.Mutex
 	ctx    context.Context
 	waitCh <-chan error
-	ts     []transport.Transport
+
+	names         []string
+	ts            map[string]transport.Transport
+	stats         map[string]*counters
+	subs          map[string]*chanutil.FanOut[transport.ReceivedMessage]
+	window        time.Duration
+	statsInterval time.Duration
+	log           log.Logger
 }
 
-// New creates a new Chain instance.
+// Config is the configuration for Chain.
+type Config struct {
+	// Transports is a map of chained transports. Keys are names of the
+	// transports used in statistics.
+	Transports map[string]transport.Transport
+
+	// DedupWindow is the time window in which messages with the same content
+	// are considered duplicates. If zero, the default value of 5 minutes is
+	// used.
+	DedupWindow time.Duration
+
+	// StatsInterval specifies how often the statistics of transports are
+	// logged. If zero, statistics are not logged.
+	StatsInterval time.Duration
+
+	// Logger is a custom logger instance. If not provided then null
+	// logger is used.
+	Logger log.Logger
+}
+
+// Stats contains the message counters of a single transport.
+type Stats struct {
+	// FirstSeen is the number of messages that were received from the
+	// transport before they were received from any other transport.
+	FirstSeen uint64
+
+	// Duplicates is the number of messages that were dropped because they
+	// were already received.
+	Duplicates uint64
+}
+
+type counters struct {
+	firstSeen  atomic.Uint64
+	duplicates atomic.Uint64
+}
+
+// New creates a new Chain instance. Transports are named using their index
+// in the argument list.
 func New(ts ...transport.Transport) *Chain {
+	m := make(map[string]transport.Transport, len(ts))
+	for i, t := range ts {
+		m[strconv.Itoa(i)] = t
+	}
+	return NewWithConfig(Config{Transports: m})
+}
+
+// NewWithConfig creates a new Chain instance using the given configuration.
+func NewWithConfig(cfg Config) *Chain {
+	if cfg.DedupWindow == 0 {
+		cfg.DedupWindow = defaultDedupWindow
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	c := &Chain{
+		ts:            make(map[string]transport.Transport, len(cfg.Transports)),
+		stats:         make(map[string]*counters, len(cfg.Transports)),
+		subs:          make(map[string]*chanutil.FanOut[transport.ReceivedMessage]),
+		window:        cfg.DedupWindow,
+		statsInterval: cfg.StatsInterval,
+		log:           cfg.Logger.WithField("tag", LoggerTag),
+	}
 	fi := chanutil.NewFanIn[error]()
-	for _, t := range ts {
+	for name, t := range cfg.Transports {
+		c.names = append(c.names, name)
+		c.ts[name] = t
+		c.stats[name] = &counters{}
 		_ = fi.Add(t.Wait())
 	}
 	fi.AutoClose()
-	return &Chain{
-		waitCh: fi.Chan(),
-		ts:     ts,
-	}
+	sort.Strings(c.names)
+	c.waitCh = fi.Chan()
+	return c
 }
 
 // Broadcast implements the transport.Transport interface.
 func (m *Chain) Broadcast(topic string, message transport.Message) error {
 	var err error
-	for _, t := range m.ts {
-		if bErr := t.Broadcast(topic, message); bErr != nil {
+	for _, name := range m.names {
+		if bErr := m.ts[name].Broadcast(topic, message); bErr != nil {
 			err = bErr // TODO(mdobak): Collect all errors.
 		}
 	}
@@ -56,13 +146,50 @@ func (m *Chain) Broadcast(topic string, message transport.Message) error {
 }
 
 // Messages implements the transport.Transport interface.
+//
+// Underlying transports are subscribed only once for each topic, and each
+// call returns a new channel that receives deduplicated messages.
 func (m *Chain) Messages(topic string) <-chan transport.ReceivedMessage {
-	fi := chanutil.NewFanIn[transport.ReceivedMessage]()
-	for _, t := range m.ts {
-		_ = fi.Add(t.Messages(topic))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if fo, ok := m.subs[topic]; ok {
+		return fo.Chan()
 	}
-	fi.AutoClose()
-	return fi.Chan()
+	var (
+		wg  sync.WaitGroup
+		out = make(chan transport.ReceivedMessage)
+		d   = newDeduplicator(m.window)
+	)
+	for _, name := range m.names {
+		ch := m.ts[name].Messages(topic)
+		if ch == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, ch <-chan transport.ReceivedMessage) {
+			defer wg.Done()
+			m.dedupRoutine(name, ch, out, d)
+		}(name, ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	fo := chanutil.NewFanOut(out)
+	m.subs[topic] = fo
+	return fo.Chan()
+}
+
+// Stats returns the message counters for each transport.
+func (m *Chain) Stats() map[string]Stats {
+	s := make(map[string]Stats, len(m.stats))
+	for name, c := range m.stats {
+		s[name] = Stats{
+			FirstSeen:  c.firstSeen.Load(),
+			Duplicates: c.duplicates.Load(),
+		}
+	}
+	return s
 }
 
 // Start implements the transport.Transport interface.
@@ -74,11 +201,14 @@ func (m *Chain) Start(ctx context.Context) error {
 		return errors.New("context must not be nil")
 	}
 	m.ctx = ctx
-	for _, t := range m.ts {
-		if err := t.Start(ctx); err != nil {
+	for _, name := range m.names {
+		if err := m.ts[name].Start(ctx); err != nil {
 			return err
 		}
 	}
+	if m.statsInterval > 0 {
+		go m.statsRoutine()
+	}
 	return nil
 }
 
@@ -86,3 +216,87 @@ func (m *Chain) Start(ctx context.Context) error {
 func (m *Chain) Wait() <-chan error {
 	return m.waitCh
 }
+
+func (m *Chain) dedupRoutine(
+	name string,
+	in <-chan transport.ReceivedMessage,
+	out chan<- transport.ReceivedMessage,
+	d *deduplicator,
+) {
+
+	for msg := range in {
+		if msg.Error == nil && msg.Message != nil {
+			data, err := msg.Message.MarshallBinary()
+			if err == nil {
+				if d.seen(sha256.Sum256(data)) {
+					m.stats[name].duplicates.Add(1)
+					continue
+				}
+				m.stats[name].firstSeen.Add(1)
+			}
+		}
+		out <- msg
+	}
+}
+
+// statsRoutine periodically logs the message counters. The log fields may be
+// used to build metrics using the Grafana logger.
+func (m *Chain) statsRoutine() {
+	t := time.NewTicker(m.statsInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-t.C:
+			stats := m.Stats()
+			for _, name := range m.names {
+				m.log.
+					WithFields(log.Fields{
+						"transport":  name,
+						"firstSeen":  stats[name].FirstSeen,
+						"duplicates": stats[name].Duplicates,
+					}).
+					Info("Transport statistics")
+			}
+		}
+	}
+}
+
+// deduplicator remembers hashes of messages seen within the time window.
+type deduplicator struct {
+	mu sync.Mutex
+
+	window    time.Duration
+	hashes    map[[sha256.Size]byte]time.Time
+	lastPrune time.Time
+}
+
+func newDeduplicator(window time.Duration) *deduplicator {
+	return &deduplicator{
+		window:    window,
+		hashes:    make(map[[sha256.Size]byte]time.Time),
+		lastPrune: time.Now(),
+	}
+}
+
+// seen returns true if the hash was already seen within the time window.
+// Otherwise, it remembers the hash and returns false.
+func (d *deduplicator) seen(h [sha256.Size]byte) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	if now.Sub(d.lastPrune) > d.window {
+		for k, t := range d.hashes {
+			if now.Sub(t) > d.window {
+				delete(d.hashes, k)
+			}
+		}
+		d.lastPrune = now
+	}
+	if t, ok := d.hashes[h]; ok && now.Sub(t) <= d.window {
+		return true
+	}
+	d.hashes[h] = now
+	return false
+}
@@ -17,6 +17,7 @@ package chain
 
 import (
 	"context"
+	"crypto/sha256"
 	"sync"
 	"testing"
 	"time"
@@ -52,9 +53,10 @@ func TestChain_Broadcast(t *testing.T) {
 
 	tm := &testMsg{Val: "bar"}
 
-	// Because two local transports are used, the message should be received
-	// twice. Also, because each call to the Messages method must create a new
-	// fan-out channel, the total number of messages received should be 4.
+	// Because two local transports are used, the message is received twice,
+	// but the duplicate must be dropped. Because each call to the Messages
+	// method must create a new fan-out channel, the total number of messages
+	// received should be 2.
 	m1 := l.Messages("foo")
 	m2 := l.Messages("foo")
 
@@ -63,7 +65,7 @@ func TestChain_Broadcast(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
-		for c := 0; c < 4; c++ {
+		for c := 0; c < 2; c++ {
 			select {
 			case m, ok := <-m1:
 				assert.True(t, ok)
@@ -79,6 +81,63 @@ func TestChain_Broadcast(t *testing.T) {
 	}()
 
 	wg.Wait()
+
+	// No more messages are expected.
+	select {
+	case <-m1:
+		assert.Fail(t, "unexpected message")
+	case <-m2:
+		assert.Fail(t, "unexpected message")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	stats := l.Stats()
+	assert.Equal(t, uint64(1), stats["0"].FirstSeen+stats["1"].FirstSeen)
+	assert.Equal(t, uint64(1), stats["0"].Duplicates+stats["1"].Duplicates)
+}
+
+func TestChain_Dedup(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer ctxCancel()
+
+	l1 := local.New([]byte("test"), 1, map[string]transport.Message{"foo": (*testMsg)(nil)})
+	l2 := local.New([]byte("test"), 1, map[string]transport.Message{"foo": (*testMsg)(nil)})
+
+	l := NewWithConfig(Config{
+		Transports: map[string]transport.Transport{"l1": l1, "l2": l2},
+	})
+	_ = l.Start(ctx)
+	m := l.Messages("foo")
+
+	// The message arrives through l1 first, then through l2.
+	assert.NoError(t, l1.Broadcast("foo", &testMsg{Val: "a"}))
+	assert.Equal(t, &testMsg{Val: "a"}, (<-m).Message)
+	assert.NoError(t, l2.Broadcast("foo", &testMsg{Val: "a"}))
+
+	// A different message is not a duplicate.
+	assert.NoError(t, l2.Broadcast("foo", &testMsg{Val: "b"}))
+	assert.Equal(t, &testMsg{Val: "b"}, (<-m).Message)
+
+	assert.Equal(t, map[string]Stats{
+		"l1": {FirstSeen: 1, Duplicates: 0},
+		"l2": {FirstSeen: 1, Duplicates: 1},
+	}, l.Stats())
+}
+
+func TestDeduplicator(t *testing.T) {
+	d := newDeduplicator(50 * time.Millisecond)
+	h1 := sha256.Sum256([]byte("a"))
+	h2 := sha256.Sum256([]byte("b"))
+
+	assert.False(t, d.seen(h1))
+	assert.True(t, d.seen(h1))
+	assert.False(t, d.seen(h2))
+
+	// After the window elapses, messages are no longer duplicates and old
+	// hashes are pruned.
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, d.seen(h1))
+	assert.Len(t, d.hashes, 1)
 }
 
 func TestChain_Wait(t *testing.T) {
@@ -18,13 +18,13 @@ package libp2p
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"time"
 
 	cryptoETH "github.com/defiweb/go-eth/crypto"
 	"github.com/defiweb/go-eth/types"
 	"github.com/defiweb/go-eth/wallet"
-	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core"
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -33,9 +33,9 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/allowlist"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p/crypto/ethkey"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p/internal"
-	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/chanutil"
 )
 
@@ -122,9 +122,23 @@ type Config struct {
 	BlockedAddrs []string
 
 	// AuthorAllowlist is a list of allowed message authors. Only messages from
-	// these addresses will be accepted.
+	// these addresses will be accepted. Ignored if Allowlist is provided.
+	//
+	// The number of addresses on the list is also used to calculate peer
+	// scoring and rate limiting parameters. If the list is empty, the initial
+	// list returned by Allowlist is used for that purpose.
 	AuthorAllowlist []types.Address
 
+	// Allowlist is an optional source of allowed message authors that is
+	// consulted at runtime, so authors can be added or removed without
+	// restarting the node.
+	Allowlist allowlist.Allowlist
+
+	// PriceBatchScoring enables peer scoring for the price/v2 topic. It
+	// should be enabled only when feeders broadcast price batches, otherwise
+	// peers would be penalized for not delivering price/v2 messages.
+	PriceBatchScoring bool
+
 	// Discovery indicates whenever peer discovery should be enabled.
 	// If discovery is disabled, then DirectPeersAddrs must be used
 	// to connect to the network. Always enabled in bootstrap mode.
@@ -164,6 +178,17 @@ func New(cfg Config) (*P2P, error) {
 	if cfg.Logger == nil {
 		cfg.Logger = null.New()
 	}
+	if cfg.Allowlist == nil {
+		cfg.Allowlist = allowlist.NewStaticAllowlist(cfg.AuthorAllowlist)
+	} else if len(cfg.AuthorAllowlist) == 0 && cfg.Mode == ClientMode {
+		cfg.AuthorAllowlist, err = cfg.Allowlist.Feeds(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("P2P transport error, unable to fetch the author allowlist: %w", err)
+		}
+		if len(cfg.AuthorAllowlist) == 0 {
+			return nil, errors.New("P2P transport error, the author allowlist is empty")
+		}
+	}
 
 	listenAddrs, err := strsToMaddrs(cfg.ListenAddrs)
 	if err != nil {
@@ -205,28 +230,16 @@ func New(cfg Config) (*P2P, error) {
 
 	switch cfg.Mode {
 	case ClientMode:
-		priceTopicScoreParams, err := calculatePriceTopicScoreParams(cfg)
-		if err != nil {
-			return nil, fmt.Errorf("P2P transport error: invalid price topic scoring parameters: %w", err)
-		}
-		eventTopicScoreParams, err := calculateEventTopicScoreParams(cfg)
+		topicScoreParams, err := calculateTopicScoreParams(cfg)
 		if err != nil {
-			return nil, fmt.Errorf("P2P transport error: invalid event topic scoring parameters: %w", err)
+			return nil, fmt.Errorf("P2P transport error: %w", err)
 		}
 		opts = append(opts,
 			internal.MessageLogger(),
 			internal.RateLimiter(rateLimiterConfig(cfg)),
-			internal.PeerScoring(peerScoreParams, thresholds, func(topic string) *pubsub.TopicScoreParams {
-				if topic == messages.PriceV0MessageName || topic == messages.PriceV1MessageName {
-					return priceTopicScoreParams
-				}
-				if topic == messages.EventV1MessageName {
-					return eventTopicScoreParams
-				}
-				return nil
-			}),
+			internal.PeerScoring(peerScoreParams, calculateThresholds(cfg), topicScoreParams),
 			messageValidator(cfg.Topics, logger), // must be registered before any other validator
-			feederValidator(cfg.AuthorAllowlist, logger),
+			feederValidator(cfg.Allowlist, logger),
 			eventValidator(logger),
 			priceValidator(logger, cryptoETH.ECRecoverer),
 		)
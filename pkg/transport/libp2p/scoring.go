@@ -17,11 +17,14 @@ package libp2p
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"time"
 
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
 // Peer scoring:
@@ -30,16 +33,32 @@ import (
 const decayInterval = time.Minute
 const decayToZero = 0.01
 
-var thresholds = &pubsub.PeerScoreThresholds{
-	// -4000 is sum of P₃ and P₃b for the "price" and "event" topics. It should
-	// be equal to the lowest score a silent peer can get without receiving any
-	// penalties other than P₃ and P₃b. Because only very few peers can produce
-	// messages, some honest peers may have a score equal to this number.
-	GossipThreshold:             -4000,
-	PublishThreshold:            -4000,
-	GraylistThreshold:           -4000,
-	AcceptPXThreshold:           0,
-	OpportunisticGraftThreshold: 0,
+// silentPeerScore is the sum of P₃ and P₃b for the "price" and "event"
+// topics. It is equal to the lowest score a silent peer can get without
+// receiving any penalties other than P₃ and P₃b. Because only very few peers
+// can produce messages, some honest peers may have a score equal to this
+// number.
+const silentPeerScore = -4000
+
+// silentPeerBatchScore is the sum of P₃ and P₃b for the "price/v2" topic.
+// It is added to silentPeerScore when the price batch topic is scored.
+const silentPeerBatchScore = -2000
+
+// calculateThresholds returns the peer score thresholds. The thresholds are
+// set to the lowest score a silent peer can get, so the topics that are
+// scored must be taken into account.
+func calculateThresholds(cfg Config) *pubsub.PeerScoreThresholds {
+	silentScore := float64(silentPeerScore)
+	if cfg.PriceBatchScoring {
+		silentScore += silentPeerBatchScore
+	}
+	return &pubsub.PeerScoreThresholds{
+		GossipThreshold:             silentScore,
+		PublishThreshold:            silentScore,
+		GraylistThreshold:           silentScore,
+		AcceptPXThreshold:           0,
+		OpportunisticGraftThreshold: 0,
+	}
 }
 
 var peerScoreParams = &pubsub.PeerScoreParams{
@@ -56,6 +75,42 @@ var peerScoreParams = &pubsub.PeerScoreParams{
 	Topics:                      make(map[string]*pubsub.TopicScoreParams),
 }
 
+// calculateTopicScoreParams returns a function that returns the score
+// parameters for the given topic. Topics for which the function returns nil
+// are not scored.
+//
+// The "price/v2" topic is scored only if cfg.PriceBatchScoring is set.
+// Otherwise, until feeders start to broadcast price batches, every peer in
+// the mesh would be penalized for not delivering any messages.
+func calculateTopicScoreParams(cfg Config) (func(topic string) *pubsub.TopicScoreParams, error) {
+	priceTopicScoreParams, err := calculatePriceTopicScoreParams(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price topic scoring parameters: %w", err)
+	}
+	var priceBatchTopicScoreParams *pubsub.TopicScoreParams
+	if cfg.PriceBatchScoring {
+		priceBatchTopicScoreParams, err = calculatePriceBatchTopicScoreParams(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price batch topic scoring parameters: %w", err)
+		}
+	}
+	eventTopicScoreParams, err := calculateEventTopicScoreParams(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event topic scoring parameters: %w", err)
+	}
+	return func(topic string) *pubsub.TopicScoreParams {
+		switch topic {
+		case messages.PriceV0MessageName, messages.PriceV1MessageName:
+			return priceTopicScoreParams
+		case messages.PriceV2MessageName:
+			return priceBatchTopicScoreParams
+		case messages.EventV1MessageName:
+			return eventTopicScoreParams
+		}
+		return nil
+	}, nil
+}
+
 func calculatePriceTopicScoreParams(cfg Config) (*pubsub.TopicScoreParams, error) {
 	var maxPeers = float64(pubsub.GossipSubDhi)
 	// Minimum and maximum expected number of feeders connected to the network:
@@ -83,6 +138,34 @@ func calculatePriceTopicScoreParams(cfg Config) (*pubsub.TopicScoreParams, error
 	}).calculate()
 }
 
+func calculatePriceBatchTopicScoreParams(cfg Config) (*pubsub.TopicScoreParams, error) {
+	var maxPeers = float64(pubsub.GossipSubDhi)
+	// Minimum and maximum expected number of feeders connected to the network:
+	var minFeederCount = float64(len(cfg.AuthorAllowlist)) / 2 // assume that 50% of feeders are offline
+	var maxFeederCount = float64(len(cfg.AuthorAllowlist))
+	// Minimum and maximum expected number of messages to be received from a single peer in a mesh.
+	// Every feeder sends a single batch with all asset pairs per update interval:
+	var minMsgsPerSecond = minFeederCount / maxPeers / priceUpdateInterval.Seconds()
+	var maxMsgsPerSecond = maxFeederCount / priceUpdateInterval.Seconds()
+
+	//nolint:gomnd
+	return (&scoreParams{
+		p1Score:              500,
+		p2Score:              500,
+		p3Score:              -1000,
+		p3bScore:             -1000,
+		p4Score:              -1000,
+		p1Length:             15 * time.Minute,
+		p2Length:             15 * time.Minute,
+		p3Length:             15 * time.Minute,
+		p3bLength:            15 * time.Minute,
+		p4Length:             time.Hour,
+		minMessagesPerSecond: minMsgsPerSecond,
+		maxMessagesPerSecond: maxMsgsPerSecond,
+		maxInvalidMessages:   maxInvalidMsgsPerHour,
+	}).calculate()
+}
+
 func calculateEventTopicScoreParams(cfg Config) (*pubsub.TopicScoreParams, error) {
 	// NOTE: The scoring parameters for events are just guesses at the moment, we will have to update them when we
 	// know how many events we can expect.
@@ -20,8 +20,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/defiweb/go-eth/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
 )
 
 func TestScoreParams_calculate(t *testing.T) {
@@ -63,3 +66,30 @@ func TestScoreParams_calculate(t *testing.T) {
 	assert.InDelta(t, p.maxMessagesPerSecond, pc.MeshMessageDeliveriesCap/p.p3Length.Seconds(), 0.01)
 	assert.InDelta(t, p.maxInvalidMessages, decayToZero*math.Pow(pc.InvalidMessageDeliveriesDecay, p.p4Length.Seconds()/decayInterval.Seconds()*-1), 0.01)
 }
+
+func TestCalculateTopicScoreParams(t *testing.T) {
+	cfg := Config{
+		AuthorAllowlist: []types.Address{
+			types.MustAddressFromHex("0x1234567890123456789012345678901234567890"),
+			types.MustAddressFromHex("0x2345678901234567890123456789012345678901"),
+		},
+	}
+
+	// Price batches are not scored by default:
+	params, err := calculateTopicScoreParams(cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, params(messages.PriceV0MessageName))
+	assert.NotNil(t, params(messages.PriceV1MessageName))
+	assert.NotNil(t, params(messages.EventV1MessageName))
+	assert.Nil(t, params(messages.PriceV2MessageName))
+	assert.Nil(t, params("unknown"))
+	assert.Equal(t, float64(silentPeerScore), calculateThresholds(cfg).GraylistThreshold)
+
+	// With price batch scoring enabled, the thresholds must take
+	// the additional topic into account:
+	cfg.PriceBatchScoring = true
+	params, err = calculateTopicScoreParams(cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, params(messages.PriceV2MessageName))
+	assert.Equal(t, float64(silentPeerScore+silentPeerBatchScore), calculateThresholds(cfg).GraylistThreshold)
+}
@@ -21,12 +21,12 @@ import (
 	"time"
 
 	"github.com/defiweb/go-eth/crypto"
-	"github.com/defiweb/go-eth/types"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/allowlist"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p/crypto/ethkey"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p/internal"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
@@ -60,16 +60,21 @@ func messageValidator(topics map[string]transport.Message, logger log.Logger) in
 	}
 }
 
-func feederValidator(feeders []types.Address, logger log.Logger) internal.Options {
+// feederValidator adds a validator that ignores messages from feeders that
+// are not on the allowlist. The allowlist is consulted for every message, so
+// changes in the allowlist are applied immediately.
+func feederValidator(feeders allowlist.Allowlist, logger log.Logger) internal.Options {
 	return func(n *internal.Node) error {
 		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
 			feedAddr := ethkey.PeerIDToAddress(psMsg.GetFrom())
-			feedAllowed := false
-			for _, addr := range feeders {
-				if addr == feedAddr {
-					feedAllowed = true
-					break
-				}
+			feedAllowed, err := allowlist.IsAllowed(ctx, feeders, feedAddr)
+			if err != nil {
+				logger.
+					WithError(err).
+					WithField("peerID", psMsg.GetFrom().String()).
+					WithField("from", feedAddr).
+					Warn("The message has been ignored, unable to fetch the feeder allowlist")
+				return pubsub.ValidationIgnore
 			}
 			if !feedAllowed {
 				logger.
@@ -112,9 +117,13 @@ func eventValidator(logger log.Logger) internal.Options {
 
 // priceValidator adds a validator for price messages. The validator checks if
 // the price message is valid, and if the price is not older than 5 min.
+// For price batch messages, the oldest price in the batch is checked.
 func priceValidator(logger log.Logger, recoverer crypto.Recoverer) internal.Options {
 	return func(n *internal.Node) error {
 		n.AddValidator(func(ctx context.Context, topic string, id peer.ID, psMsg *pubsub.Message) pubsub.ValidationResult {
+			if batchMsg, ok := psMsg.ValidatorData.(*messages.PriceBatch); ok {
+				return validatePriceBatch(logger, recoverer, psMsg, batchMsg)
+			}
 			priceMsg, ok := psMsg.ValidatorData.(*messages.Price)
 			if !ok {
 				return pubsub.ValidationAccept
@@ -164,3 +173,59 @@ func priceValidator(logger log.Logger, recoverer crypto.Recoverer) internal.Opti
 		return nil
 	}
 }
+
+func validatePriceBatch(
+	logger log.Logger,
+	recoverer crypto.Recoverer,
+	psMsg *pubsub.Message,
+	batchMsg *messages.PriceBatch,
+) pubsub.ValidationResult {
+
+	if len(batchMsg.Prices) == 0 {
+		logger.
+			WithField("peerID", psMsg.GetFrom().String()).
+			Warn("The price batch message has been rejected, the batch is empty")
+		return pubsub.ValidationReject
+	}
+	// Check if signatures of all prices are valid and belong to the same
+	// feeder. Prices are verified here, because there is no other signature
+	// that would bind them to the author of the message:
+	batchFrom, err := batchMsg.From(recoverer)
+	if err != nil {
+		logger.
+			WithError(err).
+			WithField("peerID", psMsg.GetFrom().String()).
+			WithField("prices", len(batchMsg.Prices)).
+			Warn("The price batch message has been rejected, invalid price signature")
+		return pubsub.ValidationReject
+	}
+	// The libp2p message should be created by the same person who signs the prices:
+	if ethkey.AddressToPeerID(*batchFrom) != psMsg.GetFrom() {
+		logger.
+			WithField("peerID", psMsg.GetFrom().String()).
+			WithField("from", batchFrom.String()).
+			WithField("prices", len(batchMsg.Prices)).
+			Warn("The price batch message has been rejected, the message and price signatures do not match")
+		return pubsub.ValidationReject
+	}
+	// Check when the oldest price was created, ignore if older than 5 min, reject if older than 10 min:
+	oldest := batchMsg.Prices[0].Price.Age
+	for _, p := range batchMsg.Prices[1:] {
+		if p.Price.Age.Before(oldest) {
+			oldest = p.Price.Age
+		}
+	}
+	if time.Since(oldest) > 5*time.Minute {
+		logger.
+			WithField("peerID", psMsg.GetFrom().String()).
+			WithField("from", batchFrom.String()).
+			WithField("prices", len(batchMsg.Prices)).
+			WithField("age", oldest.UTC().Format(time.RFC3339)).
+			Warn("The price batch message has been rejected, the message is older than 5 min")
+		if time.Since(oldest) > 10*time.Minute {
+			return pubsub.ValidationReject
+		}
+		return pubsub.ValidationIgnore
+	}
+	return pubsub.ValidationAccept
+}
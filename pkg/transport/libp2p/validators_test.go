@@ -0,0 +1,66 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package libp2p
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/defiweb/go-eth/crypto"
+	"github.com/defiweb/go-eth/wallet"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubPB "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/median"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/libp2p/crypto/ethkey"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages"
+)
+
+func TestValidatePriceBatch(t *testing.T) {
+	feeder := wallet.NewRandomKey()
+	other := wallet.NewRandomKey()
+	psMsg := &pubsub.Message{Message: &pubsubPB.Message{From: []byte(ethkey.AddressToPeerID(feeder.Address()))}}
+
+	newBatch := func(keys ...wallet.Key) *messages.PriceBatch {
+		batch := &messages.PriceBatch{}
+		for i, key := range keys {
+			price := &median.Price{Wat: "AAABBB", Val: big.NewInt(int64(i + 1)), Age: time.Now()}
+			require.NoError(t, price.Sign(key))
+			batch.Prices = append(batch.Prices, &messages.Price{Price: price})
+		}
+		return batch
+	}
+
+	tests := []struct {
+		name  string
+		batch *messages.PriceBatch
+		want  pubsub.ValidationResult
+	}{
+		{name: "valid", batch: newBatch(feeder, feeder), want: pubsub.ValidationAccept},
+		{name: "empty", batch: newBatch(), want: pubsub.ValidationReject},
+		{name: "forged price", batch: newBatch(feeder, other), want: pubsub.ValidationReject},
+		{name: "different author", batch: newBatch(other, other), want: pubsub.ValidationReject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validatePriceBatch(null.New(), crypto.ECRecoverer, psMsg, tt.batch))
+		})
+	}
+}
@@ -0,0 +1,135 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package messages
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/defiweb/go-eth/crypto"
+	"github.com/defiweb/go-eth/types"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/median"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/messages/pb"
+)
+
+const PriceV2MessageName = "price/v2"
+
+var ErrEmptyPriceBatch = errors.New("price batch is empty")
+var ErrUnsignedPriceInBatch = errors.New("price in the batch is not signed")
+var ErrMixedPriceBatch = errors.New("prices in the batch are signed by different feeders")
+
+// PriceBatch is the price/v2 message. It contains prices for many asset
+// pairs in a single message, instead of sending a separate price/v1 message
+// for every pair.
+//
+// The batch itself is not signed. Every price in the batch carries its own
+// signature, because the Median contract requires a signature for every
+// price. All prices in the batch must be signed by the same feeder, who is
+// the author of the batch. Compared to price/v1 messages, the batch saves
+// the per-message overhead of the transport, such as the message envelope
+// and its signature, but not the signatures of prices.
+type PriceBatch struct {
+	Prices  []*Price `json:"prices"`
+	Version string   `json:"version,omitempty"`
+}
+
+// From returns the address of the feeder that signed the prices in the
+// batch. It returns an error if the batch is empty, if any signature is
+// invalid or if prices are signed by different feeders.
+func (p *PriceBatch) From(r crypto.Recoverer) (*types.Address, error) {
+	if len(p.Prices) == 0 {
+		return nil, ErrEmptyPriceBatch
+	}
+	var from *types.Address
+	for _, price := range p.Prices {
+		if price.Price == nil {
+			return nil, median.ErrPriceNotSet
+		}
+		if price.Price.Sig.V == nil || price.Price.Sig.R == nil || price.Price.Sig.S == nil {
+			return nil, ErrUnsignedPriceInBatch
+		}
+		priceFrom, err := price.Price.From(r)
+		if err != nil {
+			return nil, err
+		}
+		if from != nil && *from != *priceFrom {
+			return nil, ErrMixedPriceBatch
+		}
+		from = priceFrom
+	}
+	return from, nil
+}
+
+// MarshallBinary implements the transport.Message interface.
+func (p *PriceBatch) MarshallBinary() ([]byte, error) {
+	msg := &pb.PriceBatch{
+		Prices:  make([]*pb.PriceBatch_Price, len(p.Prices)),
+		Version: p.Version,
+	}
+	for i, price := range p.Prices {
+		msg.Prices[i] = &pb.PriceBatch_Price{
+			Wat:   price.Price.Wat,
+			Age:   price.Price.Age.Unix(),
+			Trace: price.Trace,
+			Vrs:   price.Price.Sig.Bytes(),
+		}
+		if price.Price.Val != nil {
+			msg.Prices[i].Val = price.Price.Val.Bytes()
+		}
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > priceMessageMaxSize {
+		return nil, ErrPriceMessageTooLarge
+	}
+	return data, nil
+}
+
+// UnmarshallBinary implements the transport.Message interface.
+func (p *PriceBatch) UnmarshallBinary(data []byte) error {
+	if len(data) > priceMessageMaxSize {
+		return ErrPriceMessageTooLarge
+	}
+	msg := &pb.PriceBatch{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	p.Prices = make([]*Price, len(msg.Prices))
+	for i, price := range msg.Prices {
+		priceSig, err := types.SignatureFromBytes(price.Vrs)
+		if err != nil {
+			return err
+		}
+		p.Prices[i] = &Price{
+			Price: &median.Price{
+				Wat: price.Wat,
+				Val: new(big.Int).SetBytes(price.Val),
+				Age: time.Unix(price.Age, 0),
+				Sig: priceSig,
+			},
+			Trace:          price.Trace,
+			Version:        msg.Version,
+			messageVersion: 1,
+		}
+	}
+	p.Version = msg.Version
+	return nil
+}
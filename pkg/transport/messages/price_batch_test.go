@@ -0,0 +1,119 @@
+//  Copyright (C) 2020 Maker Ecosystem Growth Holdings, INC.
+//
+//  This program is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  This program is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package messages
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defiweb/go-eth/crypto"
+	"github.com/defiweb/go-eth/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/price/median"
+)
+
+func testPriceBatch() *PriceBatch {
+	return &PriceBatch{
+		Prices: []*Price{
+			{
+				Price: &median.Price{Wat: "AAABBB", Val: big.NewInt(10), Age: time.Unix(100, 0)},
+				Trace: []byte(`{"a":1}`),
+			},
+			{
+				Price: &median.Price{Wat: "XXXYYY", Val: big.NewInt(20), Age: time.Unix(200, 0)},
+			},
+		},
+		Version: "0.0.1",
+	}
+}
+
+func signPrices(t *testing.T, batch *PriceBatch, key wallet.Key) {
+	for _, p := range batch.Prices {
+		require.NoError(t, p.Price.Sign(key))
+	}
+}
+
+func TestPriceBatch_Marshalling(t *testing.T) {
+	batch := testPriceBatch()
+	key := wallet.NewRandomKey()
+	signPrices(t, batch, key)
+
+	data, err := batch.MarshallBinary()
+	require.NoError(t, err)
+
+	res := &PriceBatch{}
+	require.NoError(t, res.UnmarshallBinary(data))
+	require.Len(t, res.Prices, 2)
+	for i, p := range res.Prices {
+		assert.Equal(t, batch.Prices[i].Price.Wat, p.Price.Wat)
+		assert.Equal(t, batch.Prices[i].Price.Val, p.Price.Val)
+		assert.Equal(t, batch.Prices[i].Price.Age.Unix(), p.Price.Age.Unix())
+		assert.Equal(t, []byte(batch.Prices[i].Trace), []byte(p.Trace))
+		assert.Equal(t, "0.0.1", p.Version)
+
+		// Signatures of individual prices must be preserved.
+		priceFrom, err := p.Price.From(crypto.ECRecoverer)
+		require.NoError(t, err)
+		assert.Equal(t, key.Address(), *priceFrom)
+	}
+	assert.Equal(t, batch.Version, res.Version)
+
+	from, err := res.From(crypto.ECRecoverer)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), *from)
+}
+
+func TestPriceBatch_From(t *testing.T) {
+	key := wallet.NewRandomKey()
+
+	batch := testPriceBatch()
+	signPrices(t, batch, key)
+	from, err := batch.From(crypto.ECRecoverer)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), *from)
+
+	// Prices signed by different feeders.
+	require.NoError(t, batch.Prices[1].Price.Sign(wallet.NewRandomKey()))
+	_, err = batch.From(crypto.ECRecoverer)
+	assert.ErrorIs(t, err, ErrMixedPriceBatch)
+
+	// Unsigned price.
+	batch = testPriceBatch()
+	require.NoError(t, batch.Prices[0].Price.Sign(key))
+	_, err = batch.From(crypto.ECRecoverer)
+	assert.ErrorIs(t, err, ErrUnsignedPriceInBatch)
+
+	_, err = (&PriceBatch{}).From(crypto.ECRecoverer)
+	assert.ErrorIs(t, err, ErrEmptyPriceBatch)
+}
+
+func TestPriceBatch_TooLarge(t *testing.T) {
+	batch := testPriceBatch()
+	batch.Version = strings.Repeat("a", priceMessageMaxSize+1)
+	_, err := batch.MarshallBinary()
+	assert.ErrorIs(t, err, ErrPriceMessageTooLarge)
+	assert.ErrorIs(t, (&PriceBatch{}).UnmarshallBinary(make([]byte, priceMessageMaxSize+1)), ErrPriceMessageTooLarge)
+}
+
+func FuzzPriceBatch_UnmarshallBinary(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = (&PriceBatch{}).UnmarshallBinary(data)
+	})
+}
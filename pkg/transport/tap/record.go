@@ -0,0 +1,183 @@
+package tap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultMaxFiles is the default number of rotated files to keep.
+const defaultMaxFiles = 5
+
+// maxRecordSize is the maximum size of a single record in a file.
+const maxRecordSize = 16 * 1024 * 1024
+
+var errWriterClosed = errors.New("writer is closed")
+
+// Direction describes whether the message was received from or broadcast
+// to the transport.
+type Direction string
+
+const (
+	Received  Direction = "received"
+	Broadcast Direction = "broadcast"
+)
+
+// Record is a single message stored in a tap file. Records are stored as
+// JSON objects separated by new lines.
+type Record struct {
+	// Time is the time at which the message was received or broadcast.
+	Time time.Time `json:"time"`
+
+	// Direction is the direction of the message.
+	Direction Direction `json:"direction"`
+
+	// Topic is the topic of the message.
+	Topic string `json:"topic"`
+
+	// Author is the author of the message as returned by the transport.
+	// It is empty for broadcast messages.
+	Author []byte `json:"author,omitempty"`
+
+	// Data is the message serialized using the MarshallBinary method.
+	Data []byte `json:"data"`
+}
+
+// recordWriter appends records to a file. If the file size exceeds maxSize,
+// the file is rotated: the current file is renamed to path.1, the previous
+// path.1 is renamed to path.2, and so on. Only maxFiles rotated files are
+// kept.
+type recordWriter struct {
+	mu sync.Mutex
+
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+	closed   bool
+}
+
+func newRecordWriter(path string, maxSize int64, maxFiles int) *recordWriter {
+	if maxFiles <= 0 {
+		maxFiles = defaultMaxFiles
+	}
+	return &recordWriter{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+}
+
+func (w *recordWriter) open() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.openFile()
+}
+
+func (w *recordWriter) write(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || w.file == nil {
+		return errWriterClosed
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(data)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	return err
+}
+
+func (w *recordWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *recordWriter) openFile() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	s, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file = f
+	w.size = s.Size()
+	return nil
+}
+
+func (w *recordWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+	if err := os.Remove(rotatedPath(w.path, w.maxFiles)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedPath(w.path, i), rotatedPath(w.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(w.path, rotatedPath(w.path, 1)); err != nil {
+		return err
+	}
+	return w.openFile()
+}
+
+func rotatedPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// recordReader reads records from a file written by recordWriter.
+type recordReader struct {
+	scanner *bufio.Scanner
+}
+
+func newRecordReader(r io.Reader) *recordReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxRecordSize)
+	return &recordReader{scanner: s}
+}
+
+// next returns the next record. It returns io.EOF if there are no more
+// records.
+func (r *recordReader) next() (Record, error) {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return Record{}, err
+		}
+		return rec, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
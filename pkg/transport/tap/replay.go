@@ -0,0 +1,222 @@
+package tap
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/chanutil"
+)
+
+// Replay is a transport that reads messages recorded by Tap from a file
+// and sends them to subscribers of the Messages channels.
+//
+// Only received messages are replayed. Broadcast messages stored in the
+// file are skipped, and messages broadcast using the Replay transport are
+// discarded.
+//
+// Messages are sent with the same intervals between them as when they were
+// recorded, divided by the configured speed.
+type Replay struct {
+	ctx    context.Context
+	waitCh chan error
+	doneCh chan struct{}
+
+	path  string
+	speed float64
+	delay time.Duration
+	subs  map[string]*replaySubscription
+	log   log.Logger
+}
+
+type replaySubscription struct {
+	// typ is the structure type to which the message must be unmarshalled.
+	typ reflect.Type
+
+	// msgCh is a channel used to send replayed messages.
+	msgCh chan transport.ReceivedMessage
+
+	// msgFanOut is a fan-out demultiplexer for the msgCh channel.
+	msgFanOut *chanutil.FanOut[transport.ReceivedMessage]
+}
+
+// ReplayConfig is the configuration for Replay.
+type ReplayConfig struct {
+	// Path is the path to the file recorded by Tap.
+	Path string
+
+	// Topics is a list of subscribed topics. A value of the map a type of
+	// message given as a nil pointer, e.g.: (*Message)(nil).
+	Topics map[string]transport.Message
+
+	// Speed is the replay speed multiplier. For example, 1 replays messages
+	// with the original timing and 10 replays them ten times faster. If zero,
+	// the original timing is used.
+	Speed float64
+
+	// Delay is the time to wait after the transport is started before the
+	// first message is sent. Messages sent before services subscribe to
+	// the Messages channels are lost, so the delay should be long enough for
+	// all services to start.
+	Delay time.Duration
+
+	// Logger is a custom logger instance. If not provided then null
+	// logger is used.
+	Logger log.Logger
+}
+
+// NewReplay creates a new Replay instance.
+func NewReplay(cfg ReplayConfig) (*Replay, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+	if cfg.Speed < 0 {
+		return nil, errors.New("speed must not be negative")
+	}
+	if cfg.Speed == 0 {
+		cfg.Speed = 1
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	r := &Replay{
+		waitCh: make(chan error),
+		doneCh: make(chan struct{}),
+		path:   cfg.Path,
+		speed:  cfg.Speed,
+		delay:  cfg.Delay,
+		subs:   make(map[string]*replaySubscription),
+		log:    cfg.Logger.WithFields(log.Fields{"tag": LoggerTag, "path": cfg.Path}),
+	}
+	for topic, typ := range cfg.Topics {
+		msgCh := make(chan transport.ReceivedMessage)
+		r.subs[topic] = &replaySubscription{
+			typ:       reflect.TypeOf(typ).Elem(),
+			msgCh:     msgCh,
+			msgFanOut: chanutil.NewFanOut(msgCh),
+		}
+	}
+	return r, nil
+}
+
+// Start implements the transport.Transport interface.
+func (r *Replay) Start(ctx context.Context) error {
+	if r.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	r.ctx = ctx
+	r.log.Info("Starting")
+	f, err := os.Open(r.path)
+	if err != nil {
+		return err
+	}
+	go r.replayRoutine(f)
+	go r.contextCancelHandler()
+	return nil
+}
+
+// Wait implements the transport.Transport interface.
+func (r *Replay) Wait() <-chan error {
+	return r.waitCh
+}
+
+// Broadcast implements the transport.Transport interface. Messages are
+// discarded.
+func (r *Replay) Broadcast(_ string, _ transport.Message) error {
+	return nil
+}
+
+// Messages implements the transport.Transport interface.
+func (r *Replay) Messages(topic string) <-chan transport.ReceivedMessage {
+	if sub, ok := r.subs[topic]; ok {
+		return sub.msgFanOut.Chan()
+	}
+	return nil
+}
+
+func (r *Replay) replayRoutine(f *os.File) {
+	defer close(r.doneCh)
+	defer f.Close()
+	if !r.sleep(r.delay) {
+		return
+	}
+	var (
+		reader   = newRecordReader(f)
+		start    = time.Now()
+		first    time.Time
+		replayed int
+	)
+	for {
+		rec, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			r.log.WithField("messages", replayed).Info("Replay finished")
+			return
+		}
+		if err != nil {
+			r.log.WithError(err).Error("Unable to read the file")
+			return
+		}
+		if rec.Direction != Received {
+			continue
+		}
+		sub, ok := r.subs[rec.Topic]
+		if !ok {
+			continue
+		}
+		if first.IsZero() {
+			first = rec.Time
+		}
+		offset := time.Duration(float64(rec.Time.Sub(first)) / r.speed)
+		if !r.sleep(time.Until(start.Add(offset))) {
+			return
+		}
+		msg := reflect.New(sub.typ).Interface().(transport.Message)
+		recMsg := transport.ReceivedMessage{Author: rec.Author}
+		if err := msg.UnmarshallBinary(rec.Data); err != nil {
+			recMsg.Error = err
+		} else {
+			recMsg.Message = msg
+		}
+		select {
+		case <-r.ctx.Done():
+			return
+		case sub.msgCh <- recMsg:
+			replayed++
+		}
+	}
+}
+
+// sleep waits for the given duration. It returns false if the context was
+// canceled before the duration elapsed.
+func (r *Replay) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return r.ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-r.ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// contextCancelHandler handles context cancellation.
+func (r *Replay) contextCancelHandler() {
+	defer func() { close(r.waitCh) }()
+	<-r.ctx.Done()
+	<-r.doneCh
+	for _, sub := range r.subs {
+		close(sub.msgCh)
+	}
+}
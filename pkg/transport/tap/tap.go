@@ -0,0 +1,171 @@
+// Package tap provides a transport wrapper that records messages to a file
+// and a transport that replays recorded messages.
+//
+// Recorded files can be used to reproduce the behavior of services that
+// consume messages from the transport, e.g. the relayer or the event store,
+// offline using traffic captured on a production node.
+package tap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/log"
+	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/chanutil"
+)
+
+const LoggerTag = "TAP"
+
+// Tap is a transport wrapper that writes every message received from and
+// broadcast to the underlying transport to an append-only file.
+//
+// Each message is stored as a Record. The file is rotated when its size
+// exceeds the configured limit.
+type Tap struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	waitCh chan error
+
+	t    transport.Transport
+	w    *recordWriter
+	subs map[string]*chanutil.FanOut[transport.ReceivedMessage]
+	log  log.Logger
+}
+
+// Config is the configuration for Tap.
+type Config struct {
+	// Path is the path to the file to which messages are written.
+	Path string
+
+	// MaxSize is the maximum size of the file in bytes. When the size is
+	// exceeded, the file is rotated. If zero, the file is never rotated.
+	MaxSize int64
+
+	// MaxFiles is the number of rotated files to keep. If zero, the default
+	// value of 5 is used.
+	MaxFiles int
+
+	// Logger is a custom logger instance. If not provided then null
+	// logger is used.
+	Logger log.Logger
+}
+
+// New creates a new Tap instance that wraps the given transport.
+func New(t transport.Transport, cfg Config) (*Tap, error) {
+	if t == nil {
+		return nil, errors.New("transport must not be nil")
+	}
+	if cfg.Path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = null.New()
+	}
+	return &Tap{
+		waitCh: make(chan error),
+		t:      t,
+		w:      newRecordWriter(cfg.Path, cfg.MaxSize, cfg.MaxFiles),
+		subs:   make(map[string]*chanutil.FanOut[transport.ReceivedMessage]),
+		log:    cfg.Logger.WithFields(log.Fields{"tag": LoggerTag, "path": cfg.Path}),
+	}, nil
+}
+
+// Start implements the transport.Transport interface.
+func (t *Tap) Start(ctx context.Context) error {
+	if t.ctx != nil {
+		return errors.New("service can be started only once")
+	}
+	if ctx == nil {
+		return errors.New("context must not be nil")
+	}
+	t.ctx = ctx
+	t.log.Info("Starting")
+	if err := t.w.open(); err != nil {
+		return err
+	}
+	if err := t.t.Start(ctx); err != nil {
+		_ = t.w.close()
+		return err
+	}
+	go t.waitRoutine()
+	return nil
+}
+
+// Wait implements the transport.Transport interface.
+func (t *Tap) Wait() <-chan error {
+	return t.waitCh
+}
+
+// Broadcast implements the transport.Transport interface.
+func (t *Tap) Broadcast(topic string, message transport.Message) error {
+	if err := t.t.Broadcast(topic, message); err != nil {
+		return err
+	}
+	t.record(Broadcast, topic, nil, message)
+	return nil
+}
+
+// Messages implements the transport.Transport interface.
+//
+// The underlying transport is subscribed only once for each topic, so
+// messages are recorded only once regardless of the number of subscribers.
+func (t *Tap) Messages(topic string) <-chan transport.ReceivedMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if fo, ok := t.subs[topic]; ok {
+		return fo.Chan()
+	}
+	in := t.t.Messages(topic)
+	if in == nil {
+		return nil
+	}
+	out := make(chan transport.ReceivedMessage)
+	fo := chanutil.NewFanOut(out)
+	t.subs[topic] = fo
+	go t.tapRoutine(topic, in, out)
+	return fo.Chan()
+}
+
+func (t *Tap) tapRoutine(topic string, in <-chan transport.ReceivedMessage, out chan transport.ReceivedMessage) {
+	defer close(out)
+	for msg := range in {
+		if msg.Error == nil && msg.Message != nil {
+			t.record(Received, topic, msg.Author, msg.Message)
+		}
+		out <- msg
+	}
+}
+
+func (t *Tap) record(dir Direction, topic string, author []byte, message transport.Message) {
+	data, err := message.MarshallBinary()
+	if err != nil {
+		t.log.WithError(err).WithField("topic", topic).Warn("Unable to marshall the message")
+		return
+	}
+	err = t.w.write(Record{
+		Time:      time.Now(),
+		Direction: dir,
+		Topic:     topic,
+		Author:    author,
+		Data:      data,
+	})
+	if err != nil && !errors.Is(err, errWriterClosed) {
+		t.log.WithError(err).WithField("topic", topic).Warn("Unable to write the message")
+	}
+}
+
+// waitRoutine closes the file after the underlying transport is stopped.
+func (t *Tap) waitRoutine() {
+	defer close(t.waitCh)
+	err, ok := <-t.t.Wait()
+	if cErr := t.w.close(); cErr != nil {
+		t.log.WithError(cErr).Warn("Unable to close the file")
+	}
+	if ok && err != nil {
+		t.waitCh <- err
+	}
+}
@@ -0,0 +1,141 @@
+package tap
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/local"
+)
+
+type testMsg struct {
+	Val string
+}
+
+func (t *testMsg) MarshallBinary() ([]byte, error) {
+	return []byte(t.Val), nil
+}
+
+func (t *testMsg) UnmarshallBinary(bytes []byte) error {
+	t.Val = string(bytes)
+	return nil
+}
+
+func readRecords(t *testing.T, path string) []Record {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var recs []Record
+	r := newRecordReader(f)
+	for {
+		rec, err := r.next()
+		if err == io.EOF {
+			return recs
+		}
+		require.NoError(t, err)
+		recs = append(recs, rec)
+	}
+}
+
+func TestTap(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second)
+	defer ctxCancel()
+
+	path := filepath.Join(t.TempDir(), "tap.jsonl")
+	l := local.New([]byte("author"), 1, map[string]transport.Message{"foo": (*testMsg)(nil)})
+	tp, err := New(l, Config{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(ctx))
+
+	// Two subscribers must receive the message, but it must be recorded
+	// only once.
+	m1 := tp.Messages("foo")
+	m2 := tp.Messages("foo")
+	require.NoError(t, tp.Broadcast("foo", &testMsg{Val: "bar"}))
+	assert.Equal(t, &testMsg{Val: "bar"}, (<-m1).Message)
+	assert.Equal(t, &testMsg{Val: "bar"}, (<-m2).Message)
+
+	ctxCancel()
+	<-tp.Wait()
+
+	// The message may be received before the broadcast is recorded, so the
+	// order of records is not guaranteed.
+	recs := map[Direction]Record{}
+	for _, rec := range readRecords(t, path) {
+		recs[rec.Direction] = rec
+	}
+	require.Len(t, recs, 2)
+	assert.Equal(t, "foo", recs[Broadcast].Topic)
+	assert.Empty(t, recs[Broadcast].Author)
+	assert.Equal(t, []byte("bar"), recs[Broadcast].Data)
+	assert.Equal(t, "foo", recs[Received].Topic)
+	assert.Equal(t, []byte("author"), recs[Received].Author)
+	assert.Equal(t, []byte("bar"), recs[Received].Data)
+}
+
+func TestTap_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tap.jsonl")
+	w := newRecordWriter(path, 100, 2)
+	require.NoError(t, w.open())
+	for i := 0; i < 10; i++ {
+		require.NoError(t, w.write(Record{Topic: "foo", Data: []byte{byte(i)}}))
+	}
+	require.NoError(t, w.close())
+
+	// Each record is larger than half of the limit, so every write rotates
+	// the file and only the last 3 records are kept.
+	assert.Equal(t, []byte{9}, readRecords(t, path)[0].Data)
+	assert.Equal(t, []byte{8}, readRecords(t, path+".1")[0].Data)
+	assert.Equal(t, []byte{7}, readRecords(t, path+".2")[0].Data)
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestReplay(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second)
+	defer ctxCancel()
+
+	path := filepath.Join(t.TempDir(), "tap.jsonl")
+	now := time.Now()
+	w := newRecordWriter(path, 0, 0)
+	require.NoError(t, w.open())
+	require.NoError(t, w.write(Record{Time: now, Direction: Received, Topic: "foo", Author: []byte("a"), Data: []byte("1")}))
+	require.NoError(t, w.write(Record{Time: now, Direction: Broadcast, Topic: "foo", Data: []byte("x")}))
+	require.NoError(t, w.write(Record{Time: now, Direction: Received, Topic: "bar", Data: []byte("x")}))
+	require.NoError(t, w.write(Record{Time: now.Add(time.Second), Direction: Received, Topic: "foo", Author: []byte("b"), Data: []byte("2")}))
+	require.NoError(t, w.close())
+
+	r, err := NewReplay(ReplayConfig{
+		Path:   path,
+		Topics: map[string]transport.Message{"foo": (*testMsg)(nil)},
+		Speed:  10,
+		Delay:  10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	msgs := r.Messages("foo")
+	require.NoError(t, r.Start(ctx))
+
+	start := time.Now()
+	msg := <-msgs
+	assert.Equal(t, &testMsg{Val: "1"}, msg.Message)
+	assert.Equal(t, []byte("a"), msg.Author)
+	msg = <-msgs
+	assert.Equal(t, &testMsg{Val: "2"}, msg.Message)
+	assert.Equal(t, []byte("b"), msg.Author)
+
+	// One second between messages, replayed ten times faster.
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 90*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+
+	ctxCancel()
+	<-r.Wait()
+	_, ok := <-msgs
+	assert.False(t, ok)
+}
@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"context"
 	"crypto/rand"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 	"github.com/chronicleprotocol/oracle-suite/pkg/log"
 	"github.com/chronicleprotocol/oracle-suite/pkg/log/null"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport"
+	"github.com/chronicleprotocol/oracle-suite/pkg/transport/allowlist"
 	"github.com/chronicleprotocol/oracle-suite/pkg/transport/webapi/pb"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/chanutil"
 	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
@@ -54,7 +56,11 @@ const (
 )
 
 // WebAPI is transport that uses HTTP API to send and receive messages.
-// It is designed to use over secure network, e.g. Tor, I2P or VPN.
+// It is designed to use over secure network, e.g. Tor, I2P or VPN. It can
+// also be used over the public internet if TLS is enabled on the consumer's
+// server. In that case, addresses in the address book must use the https://
+// scheme or the UseHTTPS option must be enabled, and producers may be
+// required to present a client certificate.
 //
 // Transport involves two main actors: message producers and consumers.
 //
@@ -129,10 +135,11 @@ type WebAPI struct {
 	// Configuration fields:
 	addressBook  AddressBook
 	topics       map[string]transport.Message
-	allowlist    []types.Address
+	allowlist    allowlist.Allowlist
 	flushTicker  *timeutil.Ticker
 	signer       wallet.Key
 	client       *http.Client
+	scheme       string
 	server       *httpserver.HTTPServer
 	rand         io.Reader
 	maxClockSkew time.Duration
@@ -163,9 +170,14 @@ type Config struct {
 	Topics map[string]transport.Message
 
 	// AuthorAllowlist is a list of allowed message authors. Only messages from
-	// these addresses will be accepted.
+	// these addresses will be accepted. Ignored if Allowlist is provided.
 	AuthorAllowlist []types.Address
 
+	// Allowlist is an optional source of allowed message authors that is
+	// consulted at runtime, so authors can be added or removed without
+	// restarting the node.
+	Allowlist allowlist.Allowlist
+
 	// FlushTicker specifies how often the producer will flush messages
 	// to the consumers. If FlushTicker is nil, default ticker with 1 minute
 	// interval is used.
@@ -182,14 +194,26 @@ type Config struct {
 	// If timeout is zero, default value will be used (60 seconds).
 	Timeout time.Duration
 
+	// TLSConfig is an optional TLS configuration for the HTTP server. If
+	// provided, the server accepts only TLS connections. To require client
+	// certificates from producers, set the ClientAuth and ClientCAs fields.
+	//
+	// Ignored if Server is not nil.
+	TLSConfig *tls.Config
+
 	// Server is an optional custom HTTP server that will be used to receive
-	// messages. If provided, ListenAddr and Timeout are ignored.
+	// messages. If provided, ListenAddr, TLSConfig and Timeout are ignored.
 	Server *httpserver.HTTPServer
 
 	// Client is an optional custom HTTP client that will be used to send
 	// messages. If provided, Timeout is ignored.
 	Client *http.Client
 
+	// UseHTTPS specifies whether consumer addresses without a protocol scheme
+	// are contacted using HTTPS instead of HTTP. It should be enabled if the
+	// client is configured to use TLS, e.g. to present a client certificate.
+	UseHTTPS bool
+
 	// Rand is an optional random number generator. If not provided, Reader
 	// from crypto/rand package will be used.
 	Rand io.Reader
@@ -229,6 +253,9 @@ func New(cfg Config) (*WebAPI, error) {
 	if cfg.Logger == nil {
 		cfg.Logger = null.New()
 	}
+	if cfg.Allowlist == nil {
+		cfg.Allowlist = allowlist.NewStaticAllowlist(sliceutil.Copy(cfg.AuthorAllowlist))
+	}
 	server := cfg.Server
 	client := cfg.Client
 	if server == nil {
@@ -238,6 +265,7 @@ func New(cfg Config) (*WebAPI, error) {
 			ReadHeaderTimeout: cfg.Timeout,
 			WriteTimeout:      cfg.Timeout,
 			IdleTimeout:       cfg.Timeout,
+			TLSConfig:         cfg.TLSConfig,
 		})
 	}
 	if client == nil {
@@ -246,10 +274,11 @@ func New(cfg Config) (*WebAPI, error) {
 	w := &WebAPI{
 		waitCh:       make(chan error),
 		topics:       maputil.Copy(cfg.Topics),
-		allowlist:    sliceutil.Copy(cfg.AuthorAllowlist),
+		allowlist:    cfg.Allowlist,
 		addressBook:  cfg.AddressBook,
 		flushTicker:  cfg.FlushTicker,
 		client:       client,
+		scheme:       defaultScheme(cfg.UseHTTPS),
 		server:       server,
 		signer:       cfg.Signer,
 		lastReqs:     make(map[types.Address]time.Time),
@@ -355,9 +384,7 @@ func (w *WebAPI) flushMessages(ctx context.Context, t time.Time) error {
 	// Consumer addresses may omit protocol scheme, so we add it here.
 	for n, addr := range cons {
 		if !strings.Contains(addr, "://") {
-			// Data transmitted over the WebAPI protocol is signed, hence
-			// there is no need to use HTTPS.
-			cons[n] = "http://" + addr
+			cons[n] = w.scheme + "://" + addr
 		}
 	}
 	for _, addr := range cons {
@@ -366,6 +393,19 @@ func (w *WebAPI) flushMessages(ctx context.Context, t time.Time) error {
 	return nil
 }
 
+// defaultScheme returns the protocol scheme used for consumer addresses
+// without a scheme.
+//
+// Data transmitted over the WebAPI protocol is signed, hence there is no need
+// to use HTTPS by default. However, if the client is configured to use TLS,
+// sending messages over plain HTTP would silently bypass that configuration.
+func defaultScheme(useHTTPS bool) string {
+	if useHTTPS {
+		return "https"
+	}
+	return "http"
+}
+
 // doHTTPRequest sends a POST request to the given address with the given
 // data. The data must be gzipped protobuf-encoded MessagePack. The t parameter
 // is the time used for the URL signature.
@@ -463,7 +503,13 @@ func (w *WebAPI) consumeHandler(res http.ResponseWriter, req *http.Request) {
 	fields["timestamp"] = timestamp
 
 	// Verify if the feeder is allowed to send messages.
-	if !sliceutil.Contains(w.allowlist, *requestAuthor) {
+	allowed, err := allowlist.IsAllowed(req.Context(), w.allowlist, *requestAuthor)
+	if err != nil {
+		w.log.WithFields(fields).WithError(err).Warn("Unable to fetch the feeder allowlist")
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !allowed {
 		w.log.WithFields(fields).Debug("Feeder not allowed to send messages")
 		res.WriteHeader(http.StatusBadRequest)
 		return
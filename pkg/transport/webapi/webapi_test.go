@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -368,6 +369,42 @@ func Test_WebAPI(t *testing.T) {
 	}
 }
 
+type urlRecorder struct {
+	urls chan *url.URL
+}
+
+func (r *urlRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.urls <- req.URL
+	return nil, errors.New("not implemented")
+}
+
+func Test_WebAPI_UseHTTPS(t *testing.T) {
+	ctx, ctxCancel := context.WithTimeout(context.Background(), time.Second)
+	defer ctxCancel()
+
+	signer := &mocks.Key{}
+	signer.On("SignMessage", mock.Anything).Return(&fakeSignature, nil)
+	recorder := &urlRecorder{urls: make(chan *url.URL, 1)}
+	prod, err := New(Config{
+		ListenAddr:  "127.0.0.1:0",
+		Topics:      map[string]transport.Message{"test": (*message)(nil)},
+		AddressBook: &addressBook{addresses: []string{"consumer.onion"}},
+		Signer:      signer,
+		FlushTicker: timeutil.NewTicker(60 * time.Second),
+		Client:      &http.Client{Transport: recorder},
+		UseHTTPS:    true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, prod.Start(ctx))
+
+	// Address without a scheme must be contacted using HTTPS.
+	require.NoError(t, prod.Broadcast("test", &message{data: []byte("data")}))
+	prod.flushTicker.TickAt(time.Now())
+	u := <-recorder.urls
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "consumer.onion", u.Host)
+}
+
 func Test_signMessage(t *testing.T) {
 	var (
 		mp = &pb.MessagePack{
@@ -0,0 +1,256 @@
+// Package graphrender renders trees of nodes as directed graphs in the DOT
+// and Mermaid formats.
+//
+// Unlike the treerender package, identical sub trees are rendered only once,
+// so nodes shared by multiple branches are drawn as a single node with
+// multiple incoming edges.
+package graphrender
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chronicleprotocol/oracle-suite/pkg/util/maputil"
+)
+
+// Format is a format of the rendered graph.
+type Format int
+
+const (
+	// DOT is the Graphviz DOT format.
+	DOT Format = iota
+
+	// Mermaid is the Mermaid flowchart format.
+	Mermaid
+)
+
+// NodeData contains data for a single node in the graph.
+type NodeData struct {
+	Name      string
+	Params    map[string]any
+	Ancestors []any
+	Error     error
+
+	// Reference indicates that the node only refers to its single ancestor.
+	// Such nodes are not rendered, instead, an edge to the ancestor is drawn
+	// using a dashed line.
+	Reference bool
+}
+
+// Root is a named node from which rendering starts.
+type Root struct {
+	Name string
+	Node any
+}
+
+// RenderGraph renders a graph in the given format.
+//
+// The callback is called for each node in the graph. It receives a node and
+// returns a NodeData structure that contains information about the node.
+//
+// Nodes are identified by their content: nodes with the same name, params,
+// error and ancestors are rendered as a single node.
+func RenderGraph(format Format, callback func(any) NodeData, roots []Root) ([]byte, error) {
+	g := &graph{callback: callback, index: make(map[string]int)}
+	for _, root := range roots {
+		g.addRoot(root)
+	}
+	switch format {
+	case DOT:
+		return g.renderDOT(), nil
+	case Mermaid:
+		return g.renderMermaid(), nil
+	default:
+		return nil, fmt.Errorf("unsupported graph format: %d", format)
+	}
+}
+
+type nodeKind int
+
+const (
+	rootNode nodeKind = iota
+	innerNode
+	leafNode
+)
+
+type node struct {
+	id    string
+	kind  nodeKind
+	label []string
+	err   bool
+}
+
+type edge struct {
+	from, to  string
+	reference bool
+}
+
+type graph struct {
+	callback func(any) NodeData
+	nodes    []node
+	edges    []edge
+	index    map[string]int // node key -> index in nodes
+	edgeSet  map[edge]bool
+}
+
+func (g *graph) addRoot(root Root) {
+	id := fmt.Sprintf("m%d", len(g.nodes))
+	g.nodes = append(g.nodes, node{id: id, kind: rootNode, label: []string{root.Name}})
+	to, _, _ := g.addNode(root.Node)
+
+	// The root is a reference by itself, so a reference edge would be
+	// redundant here.
+	g.addEdge(edge{from: id, to: to})
+}
+
+// addNode adds the node and its ancestors to the graph. It returns the ID of
+// the node, its key, and whether the node is a reference. For references,
+// the ID and the key of the referenced node are returned.
+func (g *graph) addNode(n any) (string, string, bool) {
+	data := g.callback(n)
+	if data.Reference && len(data.Ancestors) == 1 {
+		id, key, _ := g.addNode(data.Ancestors[0])
+		return id, key, true
+	}
+	type ancestor struct {
+		id        string
+		reference bool
+	}
+	var (
+		ancestors []ancestor
+		keys      []string
+	)
+	for _, a := range data.Ancestors {
+		id, key, ref := g.addNode(a)
+		ancestors = append(ancestors, ancestor{id: id, reference: ref})
+		keys = append(keys, key)
+	}
+	label := nodeLabel(data)
+	key := strings.Join(label, "\n") + "\x00" + strings.Join(keys, "\x00")
+	if i, ok := g.index[key]; ok {
+		return g.nodes[i].id, key, false
+	}
+	kind := innerNode
+	if len(data.Ancestors) == 0 {
+		kind = leafNode
+	}
+	id := fmt.Sprintf("n%d", len(g.nodes))
+	g.index[key] = len(g.nodes)
+	g.nodes = append(g.nodes, node{id: id, kind: kind, label: label, err: data.Error != nil})
+	for _, a := range ancestors {
+		g.addEdge(edge{from: id, to: a.id, reference: a.reference})
+	}
+	return id, key, false
+}
+
+func (g *graph) addEdge(e edge) {
+	if g.edgeSet == nil {
+		g.edgeSet = make(map[edge]bool)
+	}
+	if g.edgeSet[e] {
+		return
+	}
+	g.edgeSet[e] = true
+	g.edges = append(g.edges, e)
+}
+
+func (g *graph) renderDOT() []byte {
+	buf := bytes.Buffer{}
+	buf.WriteString("digraph {\n")
+	buf.WriteString("  node [shape=box];\n")
+	for _, n := range g.nodes {
+		var attrs []string
+		attrs = append(attrs, fmt.Sprintf("label=%s", dotQuote(strings.Join(n.label, "\n"))))
+		switch n.kind {
+		case rootNode:
+			attrs = append(attrs, "shape=oval", "style=bold")
+		case leafNode:
+			attrs = append(attrs, "style=rounded")
+		}
+		if n.err {
+			attrs = append(attrs, "color=red", "fontcolor=red")
+		}
+		buf.WriteString(fmt.Sprintf("  %s [%s];\n", n.id, strings.Join(attrs, " ")))
+	}
+	for _, e := range g.edges {
+		if e.reference {
+			buf.WriteString(fmt.Sprintf("  %s -> %s [style=dashed];\n", e.from, e.to))
+		} else {
+			buf.WriteString(fmt.Sprintf("  %s -> %s;\n", e.from, e.to))
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func (g *graph) renderMermaid() []byte {
+	buf := bytes.Buffer{}
+	buf.WriteString("flowchart TD\n")
+	buf.WriteString("  classDef error stroke:#f00,color:#f00;\n")
+	for _, n := range g.nodes {
+		label := mermaidQuote(strings.Join(n.label, "\n"))
+		switch n.kind {
+		case rootNode:
+			buf.WriteString(fmt.Sprintf("  %s([%s])\n", n.id, label))
+		case leafNode:
+			buf.WriteString(fmt.Sprintf("  %s(%s)\n", n.id, label))
+		default:
+			buf.WriteString(fmt.Sprintf("  %s[%s]\n", n.id, label))
+		}
+		if n.err {
+			buf.WriteString(fmt.Sprintf("  class %s error\n", n.id))
+		}
+	}
+	for _, e := range g.edges {
+		if e.reference {
+			buf.WriteString(fmt.Sprintf("  %s -.-> %s\n", e.from, e.to))
+		} else {
+			buf.WriteString(fmt.Sprintf("  %s --> %s\n", e.from, e.to))
+		}
+	}
+	return buf.Bytes()
+}
+
+// nodeLabel returns lines of the node label: the node name, params sorted by
+// key, and the error, if any. Nested maps are flattened using dot-separated
+// keys.
+func nodeLabel(data NodeData) []string {
+	label := []string{data.Name}
+	params := make(map[string]any)
+	flattenParams(params, "", data.Params)
+	for _, key := range maputil.SortKeys(params, sort.Strings) {
+		label = append(label, fmt.Sprintf("%s: %v", key, params[key]))
+	}
+	if data.Error != nil {
+		label = append(label, "error: "+strings.TrimSpace(data.Error.Error()))
+	}
+	return label
+}
+
+func flattenParams(dst map[string]any, prefix string, params map[string]any) {
+	for k, v := range params {
+		switch v := v.(type) {
+		case map[string]any:
+			flattenParams(dst, prefix+k+".", v)
+		case interface{ Meta() map[string]any }:
+			flattenParams(dst, prefix+k+".", v.Meta())
+		default:
+			dst[prefix+k] = v
+		}
+	}
+}
+
+// dotQuote returns a quoted DOT string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// mermaidQuote returns a quoted Mermaid label. Quotes are replaced with
+// entity codes, as Mermaid does not support escaping them.
+func mermaidQuote(s string) string {
+	r := strings.NewReplacer(`"`, "#quot;", "\n", "<br>")
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,86 @@
+package graphrender
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNode struct {
+	name      string
+	params    map[string]any
+	err       error
+	reference bool
+	children  []*testNode
+}
+
+func testCallback(n any) NodeData {
+	node := n.(*testNode)
+	var ancestors []any
+	for _, c := range node.children {
+		ancestors = append(ancestors, c)
+	}
+	return NodeData{
+		Name:      node.name,
+		Params:    node.params,
+		Ancestors: ancestors,
+		Error:     node.err,
+		Reference: node.reference,
+	}
+}
+
+func testRoots() []Root {
+	origin := func() *testNode {
+		return &testNode{name: "origin", params: map[string]any{"origin": "a", "health": map[string]any{"ok": true}}}
+	}
+	shared := &testNode{name: "median", children: []*testNode{origin(), {name: "origin", err: errors.New("failed")}}}
+	return []Root{
+		{Name: "model_a", Node: &testNode{name: "reference", reference: true, children: []*testNode{shared}}},
+		{Name: "model_b", Node: &testNode{name: "indirect", children: []*testNode{
+			{name: "reference", reference: true, children: []*testNode{shared}},
+			origin(),
+		}}},
+	}
+}
+
+func TestRenderGraph_DOT(t *testing.T) {
+	b, err := RenderGraph(DOT, testCallback, testRoots())
+	require.NoError(t, err)
+	assert.Equal(t, `digraph {
+  node [shape=box];
+  m0 [label="model_a" shape=oval style=bold];
+  n1 [label="origin\nhealth.ok: true\norigin: a" style=rounded];
+  n2 [label="origin\nerror: failed" style=rounded color=red fontcolor=red];
+  n3 [label="median"];
+  m4 [label="model_b" shape=oval style=bold];
+  n5 [label="indirect"];
+  n3 -> n1;
+  n3 -> n2;
+  m0 -> n3;
+  n5 -> n3 [style=dashed];
+  n5 -> n1;
+  m4 -> n5;
+}
+`, string(b))
+}
+
+func TestRenderGraph_Mermaid(t *testing.T) {
+	b, err := RenderGraph(Mermaid, testCallback, []Root{
+		{Name: `"quoted"`, Node: &testNode{name: "origin", err: errors.New("failed")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `flowchart TD
+  classDef error stroke:#f00,color:#f00;
+  m0(["#quot;quoted#quot;"])
+  n1("origin<br>error: failed")
+  class n1 error
+  m0 --> n1
+`, string(b))
+}
+
+func TestRenderGraph_UnsupportedFormat(t *testing.T) {
+	_, err := RenderGraph(Format(-1), testCallback, nil)
+	assert.Error(t, err)
+}
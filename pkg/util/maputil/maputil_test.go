@@ -31,6 +31,15 @@ func TestKeys(t *testing.T) {
 	})
 }
 
+func TestValues(t *testing.T) {
+	t.Run("case-1", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"a", "b"}, Values(map[string]string{"x": "a", "y": "b"}))
+	})
+	t.Run("case-2", func(t *testing.T) {
+		assert.ElementsMatch(t, []int{1, 2}, Values(map[int]int{3: 1, 4: 2}))
+	})
+}
+
 func TestSortKeys(t *testing.T) {
 	t.Run("case-1", func(t *testing.T) {
 		m := map[string]string{"b": "b", "a": "a"}